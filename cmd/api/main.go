@@ -15,7 +15,11 @@ import (
 	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
 	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
 	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	kanbanws "github.com/Stenoliv/didlydoodash_api/internal/ws/kanban"
 	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/mail"
+	"github.com/Stenoliv/didlydoodash_api/pkg/storage"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -35,6 +39,9 @@ func main() {
 	logger := logging.New(cfg.Mode)
 	logger.Infof("Starting DidlyDooDash API in %s mode", cfg.Mode)
 
+	// Name fields in validation errors by their JSON name
+	utils.RegisterValidation()
+
 	// Create gin instance
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -43,8 +50,8 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CorsOrigins,
 		AllowMethods:     []string{"POST", "PUT", "PATCH", "GET", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "_retry"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match", "_retry"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -62,9 +69,42 @@ func main() {
 	userRepo := repositories.NewUserRepository(repo, logger)
 	roleRepo := repositories.NewRoleRepo(repo, logger)
 	memberRepo := repositories.NewMemberRepo(repo, logger)
+	announcementRepo := repositories.NewAnnouncementRepo(repo, logger)
+	projectRepo := repositories.NewProjectRepo(repo, logger)
+	projectTemplateRepo := repositories.NewProjectTemplateRepo(repo, logger)
+	kanbanRepo := repositories.NewKanbanRepo(repo, logger)
+	notificationRepo := repositories.NewNotificationRepo(repo, logger)
+	attachmentRepo := repositories.NewAttachmentRepo(repo, logger)
+	chatRepo := repositories.NewChatRepo(repo, logger)
+
+	// File storage, local files are downloaded through the API
+	var fileStorage storage.Storage
+	var localStorage *storage.Local
+	switch cfg.StorageDriver {
+	case "s3":
+		fileStorage, err = storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		var key []byte
+		key, err = cfg.StorageURLKey()
+		if err != nil {
+			break
+		}
+		localStorage, err = storage.NewLocal(cfg.StorageDir, cfg.StoragePublicURL+"/api/v1/files", key)
+		fileStorage = localStorage
+	}
+	if err != nil {
+		logger.Fatalf("failed to set up file storage: %v", err)
+	}
 
 	// Services
-	checkerService := services.NewChecker(memberRepo, roleRepo, logger)
+	checkerService := services.NewChecker(memberRepo, roleRepo, projectRepo, logger)
 	authService := services.NewAuthService(userRepo, txManager, cfg, logger)
 	orgService := services.NewOrganisationService(services.OrganisationServiceRepos{
 		Org:    orgRepo,
@@ -76,6 +116,58 @@ func main() {
 		Member: memberRepo,
 		User:   userRepo,
 	}, txManager, logger)
+	announcementService := services.NewAnnouncementService(announcementRepo, logger)
+	projectService := services.NewProjectService(services.ProjectServiceRepos{
+		Project: projectRepo,
+	}, txManager, logger)
+	projectTemplateService := services.NewProjectTemplateService(services.ProjectTemplateServiceRepos{
+		Template: projectTemplateRepo,
+	}, txManager, logger)
+	kanbanService := services.NewKanbanService(services.KanbanServiceRepos{
+		Kanban: kanbanRepo,
+	}, txManager, logger)
+
+	notificationService := services.NewNotificationService(notificationRepo, logger)
+	chatService := services.NewChatService(chatRepo, txManager, logger)
+
+	attachmentStore := services.NewAttachmentStore(attachmentRepo, txManager, fileStorage, services.AttachmentOptions{
+		MaxSize: cfg.AttachmentMaxSize,
+		URLTTL:  cfg.AttachmentURLTTL,
+	}, logger)
+	if cfg.AttachmentScanURL != "" {
+		attachmentStore.SetScanner(services.NewWebhookScanner(cfg.AttachmentScanURL))
+	}
+	kanbanService.SetAttachments(attachmentStore)
+	chatService.SetAttachments(attachmentStore)
+
+	// Due date reminders, by email as well when a mail server is set up
+	var mailer mail.Mailer
+	if cfg.SMTPHost != "" {
+		mailer, err = mail.NewSMTP(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			logger.Fatalf("failed to set up email: %v", err)
+		}
+	}
+	if err := kanbanService.SetReminders(services.ReminderOptions{
+		Offsets:    cfg.DueReminderOffsets,
+		DigestHour: cfg.DueDigestHour,
+		AppURL:     cfg.AppURL,
+	}, mailer); err != nil {
+		logger.Fatalf("failed to set up reminders: %v", err)
+	}
+
+	// Create recurring cards, fire overdue automation rules, purge expired
+	// archives, send due date reminders and remove files of deleted
+	// attachments in the background
+	automationCtx, stopAutomation := context.WithCancel(context.Background())
+	go kanbanService.RunScheduler(automationCtx, time.Minute)
+	go attachmentStore.RunCleanup(automationCtx, time.Minute)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authService, cfg)
@@ -88,12 +180,49 @@ func main() {
 		Organisation: orgService,
 		Checker:      checkerService,
 	}, cfg)
+	announcementHandler := handlers.NewAnnouncementHandler(handlers.AnnouncementHandlerServices{
+		Announcement: announcementService,
+		Checker:      checkerService,
+	}, cfg)
+
+	projectHandler := handlers.NewProjectHandler(handlers.ProjectHandlerServices{
+		Project: projectService,
+		Checker: checkerService,
+	}, cfg)
+
+	projectTemplateHandler := handlers.NewProjectTemplateHandler(handlers.ProjectTemplateHandlerServices{
+		Template: projectTemplateService,
+		Checker:  checkerService,
+	}, cfg)
+
+	kanbanHandler := handlers.NewKanbanHandler(handlers.KanbanHandlerServices{
+		Kanban:  kanbanService,
+		Checker: checkerService,
+	}, cfg)
+
+	notificationHandler := handlers.NewNotificationHandler(notificationService, cfg)
+	chatHandler := handlers.NewChatHandler(chatService, cfg)
+	fileHandler := handlers.NewFileHandler(localStorage)
+
+	// Websocket handlers
+	kanbanWSHandler := kanbanws.NewHandler(kanbanws.HandlerServices{
+		Kanban:  kanbanService,
+		Checker: checkerService,
+	}, cfg)
 
 	// API routes
 	api := r.Group("/api/v1")
 	authHandler.Routes(api)
 	orgHandler.Routes(api)
 	membershipHandler.Routes(api)
+	announcementHandler.Routes(api)
+	projectHandler.Routes(api)
+	projectTemplateHandler.Routes(api)
+	kanbanHandler.Routes(api)
+	kanbanWSHandler.Routes(api)
+	notificationHandler.Routes(api)
+	chatHandler.Routes(api)
+	fileHandler.Routes(api)
 
 	// Health check
 	api.GET("/health", func(c *gin.Context) {
@@ -113,6 +242,7 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down server...")
+	stopAutomation()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
 	"fmt"
 	"time"
 
@@ -27,6 +29,37 @@ type EnvConfig struct {
 	// RedisDB       int    `env:"REDIS_DB,required"`
 	// RedisPassword string `env:"REDIS_PASSWORD,required"`
 
+	// File storage for attachments, "local" keeps files in StorageDir and
+	// serves them from the API, "s3" uses an S3 compatible bucket. Download
+	// URLs of local files are signed with StorageURLSecret.
+	StorageDriver    string `env:"STORAGE_DRIVER" envDefault:"local"`
+	StorageDir       string `env:"STORAGE_DIR" envDefault:"./data/attachments"`
+	StoragePublicURL string `env:"STORAGE_PUBLIC_URL"`
+	StorageURLSecret string `env:"STORAGE_URL_SECRET"`
+	S3Endpoint       string `env:"S3_ENDPOINT"`
+	S3Region         string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket         string `env:"S3_BUCKET"`
+	S3AccessKey      string `env:"S3_ACCESS_KEY"`
+	S3SecretKey      string `env:"S3_SECRET_KEY"`
+	S3PathStyle      bool   `env:"S3_PATH_STYLE" envDefault:"false"`
+
+	// Attachment settings, organisations may lower the size limit
+	AttachmentMaxSize int64         `env:"ATTACHMENT_MAX_SIZE" envDefault:"26214400"`
+	AttachmentURLTTL  time.Duration `env:"ATTACHMENT_URL_TTL" envDefault:"15m"`
+	AttachmentScanURL string        `env:"ATTACHMENT_SCAN_URL"`
+
+	// Due date reminders go to assignees and watchers at each offset before
+	// the due date, the overdue digest is sent daily from DueDigestHour (UTC)
+	DueReminderOffsets []time.Duration `env:"DUE_REMINDER_OFFSETS" envDefault:"24h,0s" envSeparator:","`
+	DueDigestHour      int             `env:"DUE_DIGEST_HOUR" envDefault:"8"`
+
+	// Email, reminders are only sent in the app without an SMTP host
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+
 	// Token settings
 	TokenSecret             string        `env:"TOKEN_SECRET,required"`
 	TokenAccessTTL          time.Duration `env:"TOKEN_ACCESS_TTL,required" envDefault:"15m"`
@@ -42,3 +75,13 @@ func Load() (*EnvConfig, error) {
 
 	return &cfg, nil
 }
+
+// StorageURLKey is the key local download URLs are signed with. Without a
+// STORAGE_URL_SECRET it is derived from the token secret, so a signed URL
+// can never be passed off as a token or the other way around.
+func (c *EnvConfig) StorageURLKey() ([]byte, error) {
+	if c.StorageURLSecret != "" {
+		return []byte(c.StorageURLSecret), nil
+	}
+	return hkdf.Key(sha256.New, []byte(c.TokenSecret), nil, "didlydoodash storage url signing", sha256.Size)
+}
@@ -0,0 +1,16 @@
+DELETE FROM role_permissions WHERE permission_key LIKE 'announcement:%';
+
+DROP TABLE IF EXISTS announcement_reads;
+
+DROP INDEX IF EXISTS ix_announcements_org_publish;
+
+ALTER TABLE announcements DROP CONSTRAINT IF EXISTS ck_announcements_organisation;
+
+ALTER TABLE announcements
+    DROP COLUMN expires_at,
+    DROP COLUMN publish_at,
+    DROP COLUMN pinned,
+    DROP COLUMN author_id,
+    ALTER COLUMN body DROP NOT NULL;
+
+ALTER TABLE announcements RENAME COLUMN body TO announcment_text;
@@ -0,0 +1,58 @@
+-- Announcements: author, markdown body, pinning, scheduling and expiry
+ALTER TABLE announcements RENAME COLUMN announcment_text TO body;
+UPDATE announcements SET body = '' WHERE body IS NULL;
+
+ALTER TABLE announcements
+    ALTER COLUMN body SET NOT NULL,
+    ADD COLUMN author_id VARCHAR(21) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN publish_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN expires_at TIMESTAMPTZ;
+
+-- Old announcements without an organisation are kept but never listed, new
+-- ones need an organisation
+ALTER TABLE announcements
+    ADD CONSTRAINT ck_announcements_organisation CHECK (organisation_id IS NOT NULL) NOT VALID;
+
+CREATE INDEX IF NOT EXISTS ix_announcements_org_publish
+    ON announcements(organisation_id, publish_at DESC);
+
+-- Per-user read tracking
+CREATE TABLE IF NOT EXISTS announcement_reads (
+    announcement_id VARCHAR(21) NOT NULL,
+    user_id VARCHAR(21) NOT NULL,
+    read_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (announcement_id, user_id),
+    CONSTRAINT fk_announcement_reads_announcement
+        FOREIGN KEY (announcement_id)
+        REFERENCES announcements(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT fk_announcement_reads_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS ix_announcement_reads_user
+    ON announcement_reads(user_id);
+
+-- Grant announcement permissions to existing default roles
+INSERT INTO role_permissions (role_id, permission_key, allowed)
+SELECT r.id, p.key, TRUE
+FROM roles AS r
+CROSS JOIN (VALUES
+    ('announcement:create'),
+    ('announcement:edit'),
+    ('announcement:view'),
+    ('announcement:delete')
+) AS p(key)
+WHERE r.name IN ('owner', 'admin')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_key, allowed)
+SELECT r.id, 'announcement:view', TRUE
+FROM roles AS r
+WHERE r.name IN ('member', 'viewer')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+-- Organisation-level project templates
+CREATE TABLE IF NOT EXISTS project_templates (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    organisation_id VARCHAR(21) NOT NULL,
+    created_by VARCHAR(21),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    include_items BOOLEAN NOT NULL DEFAULT false,
+    include_whiteboards BOOLEAN NOT NULL DEFAULT false,
+    content JSONB NOT NULL DEFAULT '{}'::jsonb,
+    CONSTRAINT fk_project_templates_organisation
+        FOREIGN KEY (organisation_id)
+        REFERENCES organisations(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT fk_project_templates_user
+        FOREIGN KEY (created_by)
+        REFERENCES users(id)
+        ON UPDATE CASCADE
+        ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_project_templates_org_name
+    ON project_templates(organisation_id, name);
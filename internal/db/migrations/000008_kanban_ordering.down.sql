@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ix_kanban_items_rank;
+DROP INDEX IF EXISTS ix_kanban_categories_rank;
+
+ALTER TABLE kanban_items DROP COLUMN IF EXISTS rank;
+ALTER TABLE kanban_categories DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,27 @@
+-- Explicit ordering of kanban categories and items using lexicographic rank keys.
+-- Keys are compared byte by byte so the columns use the "C" collation.
+ALTER TABLE kanban_categories ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C";
+ALTER TABLE kanban_items ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C";
+
+-- Backfill existing rows in creation order
+UPDATE kanban_categories AS c
+SET rank = r.rank
+FROM (
+    SELECT id, lpad(to_hex(row_number() OVER (PARTITION BY kanban_id ORDER BY created_at, id)), 8, '0') || 'i' AS rank
+    FROM kanban_categories
+) AS r
+WHERE c.id = r.id AND c.rank IS NULL;
+
+UPDATE kanban_items AS i
+SET rank = r.rank
+FROM (
+    SELECT id, lpad(to_hex(row_number() OVER (PARTITION BY kanban_category_id ORDER BY created_at, id)), 8, '0') || 'i' AS rank
+    FROM kanban_items
+) AS r
+WHERE i.id = r.id AND i.rank IS NULL;
+
+ALTER TABLE kanban_categories ALTER COLUMN rank SET NOT NULL;
+ALTER TABLE kanban_items ALTER COLUMN rank SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS ix_kanban_categories_rank ON kanban_categories(kanban_id, rank);
+CREATE INDEX IF NOT EXISTS ix_kanban_items_rank ON kanban_items(kanban_category_id, rank);
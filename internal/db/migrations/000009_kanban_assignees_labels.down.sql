@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ix_kanbans_project;
+DROP TABLE IF EXISTS kanban_item_labels;
+DROP TABLE IF EXISTS kanban_labels;
+DROP TABLE IF EXISTS kanban_item_watchers;
+DROP TABLE IF EXISTS kanban_item_assignees;
@@ -0,0 +1,50 @@
+-- Card assignees, restricted to project members by the service layer
+CREATE TABLE IF NOT EXISTS kanban_item_assignees (
+    item_id VARCHAR(21) NOT NULL,
+    user_id VARCHAR(21) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, user_id),
+    CONSTRAINT fk_kanban_item_assignees_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_assignees_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_assignees_user ON kanban_item_assignees(user_id);
+
+-- Card watchers, any member of the organisation
+CREATE TABLE IF NOT EXISTS kanban_item_watchers (
+    item_id VARCHAR(21) NOT NULL,
+    user_id VARCHAR(21) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, user_id),
+    CONSTRAINT fk_kanban_item_watchers_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_watchers_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_watchers_user ON kanban_item_watchers(user_id);
+
+-- Coloured labels, shared by the organisation or scoped to a single kanban
+CREATE TABLE IF NOT EXISTS kanban_labels (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    organisation_id VARCHAR(21) NOT NULL,
+    kanban_id VARCHAR(21),
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL,
+    CONSTRAINT fk_kanban_labels_organisation FOREIGN KEY (organisation_id) REFERENCES organisations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_labels_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_kanban_labels_scope_name
+    ON kanban_labels(organisation_id, COALESCE(kanban_id, ''), lower(name));
+CREATE INDEX IF NOT EXISTS ix_kanban_labels_kanban ON kanban_labels(kanban_id);
+
+CREATE TABLE IF NOT EXISTS kanban_item_labels (
+    item_id VARCHAR(21) NOT NULL,
+    label_id VARCHAR(21) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, label_id),
+    CONSTRAINT fk_kanban_item_labels_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_labels_label FOREIGN KEY (label_id) REFERENCES kanban_labels(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_labels_label ON kanban_item_labels(label_id);
+
+-- Cross-board filters go from project to kanbans to categories
+CREATE INDEX IF NOT EXISTS ix_kanbans_project ON kanbans(project_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS kanban_item_comment_mentions;
+DROP TABLE IF EXISTS kanban_item_comment_revisions;
+DROP TABLE IF EXISTS kanban_item_comments;
@@ -0,0 +1,57 @@
+-- Threaded markdown comments on kanban items
+CREATE TABLE IF NOT EXISTS kanban_item_comments (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    item_id VARCHAR(21) NOT NULL,
+    parent_id VARCHAR(21),
+    author_id VARCHAR(21),
+    body TEXT NOT NULL,
+    CONSTRAINT fk_kanban_item_comments_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_comments_parent FOREIGN KEY (parent_id) REFERENCES kanban_item_comments(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_comments_item ON kanban_item_comments(item_id, created_at);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_comments_parent ON kanban_item_comments(parent_id);
+
+-- Previous bodies of edited and deleted comments
+CREATE TABLE IF NOT EXISTS kanban_item_comment_revisions (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    comment_id VARCHAR(21) NOT NULL,
+    editor_id VARCHAR(21),
+    action VARCHAR(10) NOT NULL,
+    body TEXT NOT NULL,
+    CONSTRAINT fk_kanban_item_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES kanban_item_comments(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_comment_revisions_editor FOREIGN KEY (editor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT ck_kanban_item_comment_revisions_action CHECK (action IN ('edit', 'delete'))
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_comment_revisions_comment ON kanban_item_comment_revisions(comment_id, created_at);
+
+CREATE TABLE IF NOT EXISTS kanban_item_comment_mentions (
+    comment_id VARCHAR(21) NOT NULL,
+    user_id VARCHAR(21) NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    CONSTRAINT fk_kanban_item_comment_mentions_comment FOREIGN KEY (comment_id) REFERENCES kanban_item_comments(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_comment_mentions_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_comment_mentions_user ON kanban_item_comment_mentions(user_id);
+
+-- Per-user notifications, data holds what the client needs to link to the subject
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    user_id VARCHAR(21) NOT NULL,
+    organisation_id VARCHAR(21) NOT NULL,
+    actor_id VARCHAR(21),
+    type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    read_at TIMESTAMPTZ,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_organisation FOREIGN KEY (organisation_id) REFERENCES organisations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS ix_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
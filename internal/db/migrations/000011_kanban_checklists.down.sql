@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS kanban_checklist_entries;
+
+DROP INDEX IF EXISTS ix_kanban_items_parent;
+ALTER TABLE kanban_items DROP COLUMN IF EXISTS parent_item_id;
@@ -0,0 +1,25 @@
+-- Cards promoted from a checklist entry link back to the card they came from
+ALTER TABLE kanban_items
+    ADD COLUMN IF NOT EXISTS parent_item_id VARCHAR(21)
+    REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS ix_kanban_items_parent ON kanban_items(parent_item_id);
+
+-- Ordered checklist entries on a card
+CREATE TABLE IF NOT EXISTS kanban_checklist_entries (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    item_id VARCHAR(21) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT false,
+    done_at TIMESTAMPTZ,
+    assignee_id VARCHAR(21),
+    due_date TIMESTAMPTZ,
+    rank TEXT COLLATE "C" NOT NULL,
+    promoted_item_id VARCHAR(21),
+    CONSTRAINT fk_kanban_checklist_entries_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_checklist_entries_assignee FOREIGN KEY (assignee_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_kanban_checklist_entries_promoted FOREIGN KEY (promoted_item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_checklist_entries_rank ON kanban_checklist_entries(item_id, rank);
+CREATE INDEX IF NOT EXISTS ix_kanban_checklist_entries_assignee ON kanban_checklist_entries(assignee_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS kanban_item_links;
+
+ALTER TABLE kanbans DROP CONSTRAINT IF EXISTS ck_kanbans_blocked_move_policy;
+ALTER TABLE kanbans DROP COLUMN IF EXISTS blocked_move_policy;
+ALTER TABLE kanban_categories DROP COLUMN IF EXISTS done;
@@ -0,0 +1,30 @@
+-- Categories marked as done columns, cards in them count as finished
+ALTER TABLE kanban_categories ADD COLUMN IF NOT EXISTS done BOOLEAN NOT NULL DEFAULT false;
+
+-- Whether moving a blocked card into a done column is refused or only warned about
+ALTER TABLE kanbans ADD COLUMN IF NOT EXISTS blocked_move_policy VARCHAR(10) NOT NULL DEFAULT 'warn';
+ALTER TABLE kanbans DROP CONSTRAINT IF EXISTS ck_kanbans_blocked_move_policy;
+ALTER TABLE kanbans ADD CONSTRAINT ck_kanbans_blocked_move_policy CHECK (blocked_move_policy IN ('warn', 'reject'));
+
+-- Links between cards of one project, "blocks" means item_id blocks
+-- linked_item_id while "relates" has no direction
+CREATE TABLE IF NOT EXISTS kanban_item_links (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    project_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21) NOT NULL,
+    linked_item_id VARCHAR(21) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    created_by VARCHAR(21),
+    CONSTRAINT ck_kanban_item_links_type CHECK (type IN ('blocks', 'relates')),
+    CONSTRAINT ck_kanban_item_links_self CHECK (item_id <> linked_item_id),
+    CONSTRAINT fk_kanban_item_links_project FOREIGN KEY (project_id) REFERENCES projects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_links_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_links_linked FOREIGN KEY (linked_item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_links_creator FOREIGN KEY (created_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+-- A pair of cards has at most one link, whichever way round it was made
+CREATE UNIQUE INDEX IF NOT EXISTS ux_kanban_item_links_pair
+    ON kanban_item_links(LEAST(item_id, linked_item_id), GREATEST(item_id, linked_item_id));
+CREATE INDEX IF NOT EXISTS ix_kanban_item_links_item ON kanban_item_links(item_id);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_links_linked ON kanban_item_links(linked_item_id);
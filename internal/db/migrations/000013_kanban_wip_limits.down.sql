@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS ix_kanban_items_completed;
+ALTER TABLE kanban_items DROP COLUMN IF EXISTS completed_at;
+
+ALTER TABLE kanbans DROP CONSTRAINT IF EXISTS ck_kanbans_wip_policy;
+ALTER TABLE kanbans DROP COLUMN IF EXISTS wip_policy;
+
+ALTER TABLE kanban_categories DROP CONSTRAINT IF EXISTS ck_kanban_categories_wip_limit;
+ALTER TABLE kanban_categories DROP COLUMN IF EXISTS wip_limit;
@@ -0,0 +1,16 @@
+-- Optional work in progress limit per category
+ALTER TABLE kanban_categories ADD COLUMN IF NOT EXISTS wip_limit INTEGER;
+ALTER TABLE kanban_categories DROP CONSTRAINT IF EXISTS ck_kanban_categories_wip_limit;
+ALTER TABLE kanban_categories ADD CONSTRAINT ck_kanban_categories_wip_limit CHECK (wip_limit IS NULL OR wip_limit > 0);
+
+-- Whether moves over a WIP limit are refused or only warned about
+ALTER TABLE kanbans ADD COLUMN IF NOT EXISTS wip_policy VARCHAR(10) NOT NULL DEFAULT 'warn';
+ALTER TABLE kanbans DROP CONSTRAINT IF EXISTS ck_kanbans_wip_policy;
+ALTER TABLE kanbans ADD CONSTRAINT ck_kanbans_wip_policy CHECK (wip_policy IN ('warn', 'reject'));
+
+-- Set while a card sits in a done column
+ALTER TABLE kanban_items ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+UPDATE kanban_items AS i SET completed_at = i.updated_at
+FROM kanban_categories AS c
+WHERE c.id = i.kanban_category_id AND c.done AND i.completed_at IS NULL;
+CREATE INDEX IF NOT EXISTS ix_kanban_items_completed ON kanban_items(completed_at);
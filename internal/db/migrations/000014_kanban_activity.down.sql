@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS tr_kanban_activity_append_only ON kanban_activity;
+DROP FUNCTION IF EXISTS kanban_activity_append_only();
+DROP TABLE IF EXISTS kanban_activity;
@@ -0,0 +1,31 @@
+-- Append-only log of kanban changes, item and category ids are kept without
+-- foreign keys so the history outlives permanently deleted rows
+CREATE TABLE IF NOT EXISTS kanban_activity (
+    id VARCHAR(21) PRIMARY KEY,
+    -- clock time keeps several changes made in one transaction in order
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+    kanban_id VARCHAR(21) NOT NULL,
+    actor_id VARCHAR(21),
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21),
+    action VARCHAR(20) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    CONSTRAINT ck_kanban_activity_entity CHECK (entity_type IN ('kanban', 'category', 'item')),
+    CONSTRAINT fk_kanban_activity_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_activity_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_activity_kanban_created ON kanban_activity(kanban_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS ix_kanban_activity_item_created ON kanban_activity(item_id, created_at DESC);
+
+-- The log is append-only
+CREATE OR REPLACE FUNCTION kanban_activity_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'kanban_activity is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tr_kanban_activity_append_only ON kanban_activity;
+CREATE TRIGGER tr_kanban_activity_append_only
+    BEFORE UPDATE ON kanban_activity
+    FOR EACH ROW EXECUTE FUNCTION kanban_activity_append_only();
@@ -0,0 +1,3 @@
+ALTER TABLE kanban_items DROP COLUMN IF EXISTS version;
+ALTER TABLE kanban_categories DROP COLUMN IF EXISTS version;
+ALTER TABLE kanbans DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Edit counters for optimistic concurrency, bumped by every content update so
+-- a client can tell whether it edited the latest state
+ALTER TABLE kanbans ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE kanban_categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE kanban_items ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,20 @@
+-- Time spent by users on cards, a running timer has no end yet
+CREATE TABLE IF NOT EXISTS kanban_time_entries (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    project_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21) NOT NULL,
+    user_id VARCHAR(21) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    note VARCHAR(500),
+    CONSTRAINT ck_kanban_time_entries_range CHECK (ended_at IS NULL OR ended_at >= started_at),
+    CONSTRAINT fk_kanban_time_entries_project FOREIGN KEY (project_id) REFERENCES projects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_time_entries_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_time_entries_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+-- A user runs at most one timer at a time
+CREATE UNIQUE INDEX IF NOT EXISTS ux_kanban_time_entries_running ON kanban_time_entries(user_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS ix_kanban_time_entries_item ON kanban_time_entries(item_id);
+CREATE INDEX IF NOT EXISTS ix_kanban_time_entries_project_started ON kanban_time_entries(project_id, started_at);
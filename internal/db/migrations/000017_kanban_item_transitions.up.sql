@@ -0,0 +1,22 @@
+-- Every time a card enters a category, from is empty when it was created there.
+-- Used for flow metrics, so it is kept lean and indexed for range scans.
+CREATE TABLE IF NOT EXISTS kanban_item_transitions (
+    id VARCHAR(21) PRIMARY KEY,
+    moved_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+    kanban_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21) NOT NULL,
+    from_category_id VARCHAR(21),
+    to_category_id VARCHAR(21) NOT NULL,
+    CONSTRAINT fk_kanban_item_transitions_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_transitions_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_transitions_kanban_moved ON kanban_item_transitions(kanban_id, moved_at);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_transitions_item_moved ON kanban_item_transitions(item_id, moved_at);
+
+-- Earlier moves were never recorded, existing cards start in their current
+-- category from when they were created
+INSERT INTO kanban_item_transitions (id, moved_at, kanban_id, item_id, to_category_id)
+SELECT i.id, COALESCE(i.created_at, now()), c.kanban_id, i.id, i.kanban_category_id
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,8 @@
+DELETE FROM kanban_activity WHERE entity_type = 'rule';
+ALTER TABLE kanban_activity DROP CONSTRAINT IF EXISTS ck_kanban_activity_entity;
+ALTER TABLE kanban_activity ADD CONSTRAINT ck_kanban_activity_entity CHECK (entity_type IN ('kanban', 'category', 'item'));
+ALTER TABLE kanban_activity DROP COLUMN IF EXISTS rule_id;
+
+DROP INDEX IF EXISTS ix_kanban_items_due_date;
+DROP TABLE IF EXISTS kanban_rule_runs;
+DROP TABLE IF EXISTS kanban_rules;
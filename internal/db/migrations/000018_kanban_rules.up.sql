@@ -0,0 +1,38 @@
+-- "When X then Y" automation rules of a board, the actions are a JSON list
+-- validated by the service
+CREATE TABLE IF NOT EXISTS kanban_rules (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    kanban_id VARCHAR(21) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    event VARCHAR(20) NOT NULL,
+    -- Only cards created in, moved into or sitting in this category
+    category_id VARCHAR(21),
+    actions JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_by VARCHAR(21),
+    CONSTRAINT ck_kanban_rules_event CHECK (event IN ('item.created', 'item.moved', 'item.overdue')),
+    CONSTRAINT fk_kanban_rules_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_rules_category FOREIGN KEY (category_id) REFERENCES kanban_categories(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_rules_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_rules_kanban_event ON kanban_rules(kanban_id, event) WHERE enabled;
+
+-- Overdue rules fire once per card and due date, moving the due date arms them again
+CREATE TABLE IF NOT EXISTS kanban_rule_runs (
+    rule_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21) NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rule_id, item_id, due_date),
+    CONSTRAINT fk_kanban_rule_runs_rule FOREIGN KEY (rule_id) REFERENCES kanban_rules(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_rule_runs_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_items_due_date ON kanban_items(due_date) WHERE deleted_at IS NULL AND completed_at IS NULL;
+
+-- Changes made by a rule name it instead of a user, kept without a foreign
+-- key like the other ids of the log
+ALTER TABLE kanban_activity ADD COLUMN IF NOT EXISTS rule_id VARCHAR(21);
+ALTER TABLE kanban_activity DROP CONSTRAINT IF EXISTS ck_kanban_activity_entity;
+ALTER TABLE kanban_activity ADD CONSTRAINT ck_kanban_activity_entity CHECK (entity_type IN ('kanban', 'category', 'item', 'rule'));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS kanban_recurrence_runs;
+DROP TABLE IF EXISTS kanban_recurrences;
@@ -0,0 +1,38 @@
+-- Cards created on a schedule, the rule is an RRULE subset and next_run_at is
+-- empty once the schedule has ended
+CREATE TABLE IF NOT EXISTS kanban_recurrences (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    kanban_id VARCHAR(21) NOT NULL,
+    category_id VARCHAR(21) NOT NULL,
+    title VARCHAR(40) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) NOT NULL DEFAULT 'None',
+    estimated_time INTEGER,
+    rrule VARCHAR(100) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    until TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_by VARCHAR(21),
+    CONSTRAINT ck_kanban_recurrences_priority CHECK (priority IN ('Extreme', 'High', 'Medium', 'Low', 'None')),
+    CONSTRAINT ck_kanban_recurrences_estimated_time CHECK (estimated_time IS NULL OR estimated_time >= 0),
+    CONSTRAINT fk_kanban_recurrences_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_recurrences_category FOREIGN KEY (category_id) REFERENCES kanban_categories(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_recurrences_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_recurrences_kanban ON kanban_recurrences(kanban_id);
+CREATE INDEX IF NOT EXISTS ix_kanban_recurrences_next_run ON kanban_recurrences(next_run_at) WHERE enabled;
+
+-- One row per generated occurrence, the key keeps restarts and several API
+-- instances from creating the same card twice
+CREATE TABLE IF NOT EXISTS kanban_recurrence_runs (
+    recurrence_id VARCHAR(21) NOT NULL,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    item_id VARCHAR(21),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (recurrence_id, scheduled_at),
+    CONSTRAINT fk_kanban_recurrence_runs_recurrence FOREIGN KEY (recurrence_id) REFERENCES kanban_recurrences(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_recurrence_runs_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
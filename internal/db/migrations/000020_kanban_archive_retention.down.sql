@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS ix_kanban_items_deleted_at;
+DROP INDEX IF EXISTS ix_kanban_categories_deleted_at;
+
+ALTER TABLE organisations
+DROP CONSTRAINT IF EXISTS ck_organisations_kanban_archive_retention,
+DROP COLUMN IF EXISTS kanban_archive_retention_days;
@@ -0,0 +1,8 @@
+-- Days archived kanban categories and cards are kept before they are purged,
+-- null keeps them forever
+ALTER TABLE organisations
+ADD COLUMN IF NOT EXISTS kanban_archive_retention_days INTEGER,
+ADD CONSTRAINT ck_organisations_kanban_archive_retention CHECK (kanban_archive_retention_days BETWEEN 1 AND 3650);
+
+CREATE INDEX IF NOT EXISTS ix_kanban_categories_deleted_at ON kanban_categories(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS ix_kanban_items_deleted_at ON kanban_items(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS kanban_item_invitations;
+DROP TABLE IF EXISTS kanban_imports;
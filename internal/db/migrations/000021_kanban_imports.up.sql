@@ -0,0 +1,36 @@
+-- Boards imported from Trello or CSV, dry runs included, kept so the per-row
+-- report of an import can be downloaded afterwards
+CREATE TABLE IF NOT EXISTS kanban_imports (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    project_id VARCHAR(21) NOT NULL,
+    -- Empty for dry runs and failed imports
+    kanban_id VARCHAR(21),
+    source VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    imported_count INTEGER NOT NULL DEFAULT 0,
+    issues JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_by VARCHAR(21),
+    CONSTRAINT ck_kanban_imports_source CHECK (source IN ('trello', 'csv')),
+    CONSTRAINT fk_kanban_imports_project FOREIGN KEY (project_id) REFERENCES projects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_imports_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_kanban_imports_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_imports_project ON kanban_imports(project_id, created_at DESC);
+
+-- Assignees of imported cards that are not in the project yet, they become
+-- assignees when a user with that email joins the project
+CREATE TABLE IF NOT EXISTS kanban_item_invitations (
+    item_id VARCHAR(21) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    project_id VARCHAR(21) NOT NULL,
+    invited_by VARCHAR(21),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, email),
+    CONSTRAINT fk_kanban_item_invitations_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_invitations_project FOREIGN KEY (project_id) REFERENCES projects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_kanban_item_invitations_invited_by FOREIGN KEY (invited_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_item_invitations_project_email ON kanban_item_invitations(project_id, email);
@@ -0,0 +1,3 @@
+-- Boards exported as JSON can be imported again
+ALTER TABLE kanban_imports DROP CONSTRAINT IF EXISTS ck_kanban_imports_source;
+ALTER TABLE kanban_imports ADD CONSTRAINT ck_kanban_imports_source CHECK (source IN ('trello', 'csv', 'json'));
@@ -0,0 +1,8 @@
+DELETE FROM kanban_activity WHERE entity_type = 'lane';
+ALTER TABLE kanban_activity DROP CONSTRAINT IF EXISTS ck_kanban_activity_entity;
+ALTER TABLE kanban_activity ADD CONSTRAINT ck_kanban_activity_entity CHECK (entity_type IN ('kanban', 'category', 'item', 'rule'));
+
+DROP INDEX IF EXISTS ix_kanban_items_lane;
+ALTER TABLE kanban_items DROP CONSTRAINT IF EXISTS fk_kanban_items_lane;
+ALTER TABLE kanban_items DROP COLUMN IF EXISTS lane_id;
+DROP TABLE IF EXISTS kanban_lanes;
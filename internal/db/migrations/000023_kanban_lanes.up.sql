@@ -0,0 +1,22 @@
+-- Swimlanes, horizontal rows crossing the categories of a kanban. Cards
+-- without a lane are shown in a lane of their own.
+CREATE TABLE IF NOT EXISTS kanban_lanes (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    kanban_id VARCHAR(21) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    rank TEXT COLLATE "C" NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    CONSTRAINT uq_kanban_lanes_name UNIQUE (kanban_id, name),
+    CONSTRAINT fk_kanban_lanes_kanban FOREIGN KEY (kanban_id) REFERENCES kanbans(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS ix_kanban_lanes_rank ON kanban_lanes(kanban_id, rank);
+
+ALTER TABLE kanban_items ADD COLUMN IF NOT EXISTS lane_id VARCHAR(21);
+ALTER TABLE kanban_items ADD CONSTRAINT fk_kanban_items_lane FOREIGN KEY (lane_id) REFERENCES kanban_lanes(id) ON UPDATE CASCADE ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS ix_kanban_items_lane ON kanban_items(lane_id) WHERE lane_id IS NOT NULL;
+
+ALTER TABLE kanban_activity DROP CONSTRAINT IF EXISTS ck_kanban_activity_entity;
+ALTER TABLE kanban_activity ADD CONSTRAINT ck_kanban_activity_entity CHECK (entity_type IN ('kanban', 'category', 'item', 'rule', 'lane'));
@@ -0,0 +1,8 @@
+ALTER TABLE organisations DROP CONSTRAINT IF EXISTS ck_organisations_attachment_max_size;
+ALTER TABLE organisations DROP COLUMN IF EXISTS attachment_types;
+ALTER TABLE organisations DROP COLUMN IF EXISTS attachment_max_size;
+
+DROP TRIGGER IF EXISTS tr_attachments_queue_deletion ON attachments;
+DROP FUNCTION IF EXISTS attachments_queue_deletion();
+DROP TABLE IF EXISTS attachment_deletions;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,55 @@
+-- Files attached to kanban cards or chat messages, the files themselves live
+-- in the configured storage under storage_key
+CREATE TABLE IF NOT EXISTS attachments (
+    id VARCHAR(21) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    organisation_id VARCHAR(21) NOT NULL,
+    item_id VARCHAR(21),
+    chat_message_id VARCHAR(21),
+    uploaded_by VARCHAR(21),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    -- Hex SHA-256 of the content
+    checksum CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL,
+    -- unscanned when no virus scanner is configured, infected files are refused
+    scan_status VARCHAR(20) NOT NULL DEFAULT 'unscanned',
+    CONSTRAINT uq_attachments_storage_key UNIQUE (storage_key),
+    CONSTRAINT ck_attachments_owner CHECK (num_nonnulls(item_id, chat_message_id) = 1),
+    CONSTRAINT ck_attachments_size CHECK (size >= 0),
+    CONSTRAINT ck_attachments_scan_status CHECK (scan_status IN ('unscanned', 'clean')),
+    CONSTRAINT fk_attachments_organisation FOREIGN KEY (organisation_id) REFERENCES organisations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_chat_message FOREIGN KEY (chat_message_id) REFERENCES chat_messages(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS ix_attachments_item ON attachments(item_id, created_at) WHERE item_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS ix_attachments_chat_message ON attachments(chat_message_id, created_at) WHERE chat_message_id IS NOT NULL;
+
+-- Stored files whose attachment is gone, however it was deleted. The cleanup
+-- job removes them from storage and then from here.
+CREATE TABLE IF NOT EXISTS attachment_deletions (
+    storage_key TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION attachments_queue_deletion() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO attachment_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tr_attachments_queue_deletion ON attachments;
+CREATE TRIGGER tr_attachments_queue_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION attachments_queue_deletion();
+
+-- Upload limits of an organisation, null falls back to the server limit and
+-- allows every type. Types are MIME types, "image/*" allows a whole family.
+ALTER TABLE organisations
+ADD COLUMN IF NOT EXISTS attachment_max_size BIGINT,
+ADD COLUMN IF NOT EXISTS attachment_types TEXT[],
+ADD CONSTRAINT ck_organisations_attachment_max_size CHECK (attachment_max_size > 0);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS kanban_due_digests;
+DROP TABLE IF EXISTS kanban_due_reminders;
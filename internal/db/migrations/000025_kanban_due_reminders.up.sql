@@ -0,0 +1,19 @@
+-- Due date reminders sent per card, due date and offset before it in
+-- seconds, moving the due date arms them again
+CREATE TABLE IF NOT EXISTS kanban_due_reminders (
+    item_id VARCHAR(21) NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    offset_seconds INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, due_date, offset_seconds),
+    CONSTRAINT fk_kanban_due_reminders_item FOREIGN KEY (item_id) REFERENCES kanban_items(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Overdue digests sent per user and day
+CREATE TABLE IF NOT EXISTS kanban_due_digests (
+    user_id VARCHAR(21) NOT NULL,
+    day DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, day),
+    CONSTRAINT fk_kanban_due_digests_user FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ix_chat_members_room_user;
+DROP INDEX IF EXISTS ix_chat_messages_room;
@@ -0,0 +1,3 @@
+-- Messages are read per room, newest first
+CREATE INDEX IF NOT EXISTS ix_chat_messages_room ON chat_messages(room_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS ix_chat_members_room_user ON chat_members(room_id, user_id);
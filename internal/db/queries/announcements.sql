@@ -0,0 +1,84 @@
+-- name: CreateAnnouncement :one
+INSERT INTO announcements (id, organisation_id, author_id, body, pinned, publish_at, expires_at)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('organisation_id')::varchar,
+    sqlc.arg('author_id'),
+    sqlc.arg('body'),
+    sqlc.arg('pinned'),
+    COALESCE(sqlc.narg('publish_at')::timestamptz, now()),
+    sqlc.narg('expires_at')
+)
+RETURNING *;
+
+-- name: GetAnnouncement :one
+SELECT * FROM announcements
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')::varchar
+  AND (
+    sqlc.arg('include_unpublished')::boolean
+    OR (publish_at <= now() AND (expires_at IS NULL OR expires_at > now()))
+  );
+
+-- name: ListAnnouncements :many
+SELECT
+    a.*,
+    u.username AS author_username,
+    (r.read_at IS NOT NULL)::boolean AS is_read
+FROM announcements AS a
+LEFT JOIN users AS u ON u.id = a.author_id
+LEFT JOIN announcement_reads AS r
+    ON r.announcement_id = a.id AND r.user_id = sqlc.arg('user_id')
+WHERE a.organisation_id = sqlc.arg('organisation_id')::varchar
+  AND (
+    sqlc.arg('include_unpublished')::boolean
+    OR (a.publish_at <= now() AND (a.expires_at IS NULL OR a.expires_at > now()))
+  )
+ORDER BY a.pinned DESC, a.publish_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListUnreadAnnouncements :many
+SELECT
+    a.*,
+    u.username AS author_username
+FROM announcements AS a
+LEFT JOIN users AS u ON u.id = a.author_id
+WHERE a.organisation_id = sqlc.arg('organisation_id')::varchar
+  AND a.publish_at <= now()
+  AND (a.expires_at IS NULL OR a.expires_at > now())
+  AND NOT EXISTS (
+    SELECT 1 FROM announcement_reads AS r
+    WHERE r.announcement_id = a.id AND r.user_id = sqlc.arg('user_id')
+  )
+ORDER BY a.pinned DESC, a.publish_at DESC;
+
+-- name: UpdateAnnouncement :one
+UPDATE announcements
+SET
+    body       = COALESCE(sqlc.narg('body'), body),
+    pinned     = COALESCE(sqlc.narg('pinned'), pinned),
+    publish_at = COALESCE(sqlc.narg('publish_at'), publish_at),
+    expires_at = CASE
+        WHEN sqlc.arg('clear_expires_at')::boolean THEN NULL
+        ELSE COALESCE(sqlc.narg('expires_at'), expires_at)
+    END,
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')::varchar
+RETURNING *;
+
+-- name: DeleteAnnouncement :execrows
+DELETE FROM announcements
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')::varchar;
+
+-- name: MarkAnnouncementRead :exec
+INSERT INTO announcement_reads (announcement_id, user_id)
+VALUES (sqlc.arg('announcement_id'), sqlc.arg('user_id'))
+ON CONFLICT (announcement_id, user_id) DO NOTHING;
+
+-- name: MarkAllAnnouncementsRead :execrows
+INSERT INTO announcement_reads (announcement_id, user_id)
+SELECT a.id, sqlc.arg('user_id')
+FROM announcements AS a
+WHERE a.organisation_id = sqlc.arg('organisation_id')::varchar
+  AND a.publish_at <= now()
+  AND (a.expires_at IS NULL OR a.expires_at > now())
+ON CONFLICT (announcement_id, user_id) DO NOTHING;
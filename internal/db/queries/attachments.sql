@@ -0,0 +1,63 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (
+    id,
+    organisation_id,
+    item_id,
+    chat_message_id,
+    uploaded_by,
+    file_name,
+    content_type,
+    size,
+    checksum,
+    storage_key,
+    scan_status
+) VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('organisation_id'),
+    sqlc.narg('item_id'),
+    sqlc.narg('chat_message_id'),
+    sqlc.narg('uploaded_by'),
+    sqlc.arg('file_name'),
+    sqlc.arg('content_type'),
+    sqlc.arg('size'),
+    sqlc.arg('checksum'),
+    sqlc.arg('storage_key'),
+    sqlc.arg('scan_status')
+)
+RETURNING *;
+
+-- name: GetItemAttachment :one
+SELECT * FROM attachments
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id');
+
+-- name: ListItemAttachments :many
+SELECT * FROM attachments
+WHERE item_id = sqlc.arg('item_id')
+ORDER BY created_at, id;
+
+-- name: DeleteAttachment :execrows
+-- The stored file is queued for removal by a trigger
+DELETE FROM attachments WHERE id = sqlc.arg('id');
+
+-- name: GetOrganisationAttachmentLimits :one
+SELECT attachment_max_size, attachment_types FROM organisations
+WHERE id = sqlc.arg('id');
+
+-- name: ListAttachmentDeletions :many
+-- Locked until the transaction ends so instances sweep different files
+SELECT storage_key FROM attachment_deletions
+ORDER BY created_at
+LIMIT sqlc.arg('limit')
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteAttachmentDeletion :exec
+DELETE FROM attachment_deletions WHERE storage_key = sqlc.arg('storage_key');
+
+-- name: GetChatMessageAttachment :one
+SELECT * FROM attachments
+WHERE id = sqlc.arg('id') AND chat_message_id = sqlc.arg('chat_message_id');
+
+-- name: ListChatMessageAttachments :many
+SELECT * FROM attachments
+WHERE chat_message_id = ANY(sqlc.arg('message_ids')::varchar[])
+ORDER BY created_at, id;
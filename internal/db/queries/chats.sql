@@ -0,0 +1,66 @@
+-- name: CreateChatRoom :one
+INSERT INTO chat_rooms (id, organisation_id, name)
+VALUES (sqlc.arg('id'), sqlc.arg('organisation_id')::varchar, sqlc.narg('name'))
+RETURNING *;
+
+-- name: AddChatMember :execrows
+-- Only members of the organisation of the room are added, and only once
+INSERT INTO chat_members (id, room_id, user_id)
+SELECT sqlc.arg('id'), r.id, sqlc.arg('user_id')
+FROM chat_rooms AS r
+WHERE r.id = sqlc.arg('room_id')
+  AND EXISTS (
+    SELECT 1 FROM organisation_members AS m
+    WHERE m.organisation_id = r.organisation_id AND m.user_id = sqlc.arg('user_id')
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM chat_members AS c
+    WHERE c.room_id = r.id AND c.user_id = sqlc.arg('user_id')
+  );
+
+-- name: ListUserChatRooms :many
+SELECT r.* FROM chat_rooms AS r
+WHERE r.organisation_id = sqlc.arg('organisation_id')::varchar
+  AND EXISTS (
+    SELECT 1 FROM chat_members AS c
+    WHERE c.room_id = r.id AND c.user_id = sqlc.arg('user_id')
+  )
+  AND EXISTS (
+    SELECT 1 FROM organisation_members AS m
+    WHERE m.organisation_id = r.organisation_id AND m.user_id = sqlc.arg('user_id')
+  )
+ORDER BY r.created_at, r.id;
+
+-- name: GetUserChatRoom :one
+-- A room is only visible to its members while they are in the organisation
+SELECT r.* FROM chat_rooms AS r
+WHERE r.id = sqlc.arg('id')
+  AND r.organisation_id = sqlc.arg('organisation_id')::varchar
+  AND EXISTS (
+    SELECT 1 FROM chat_members AS c
+    WHERE c.room_id = r.id AND c.user_id = sqlc.arg('user_id')
+  )
+  AND EXISTS (
+    SELECT 1 FROM organisation_members AS m
+    WHERE m.organisation_id = r.organisation_id AND m.user_id = sqlc.arg('user_id')
+  );
+
+-- name: CreateChatMessage :one
+INSERT INTO chat_messages (id, room_id, user_id, message)
+VALUES (sqlc.arg('id'), sqlc.arg('room_id'), sqlc.arg('user_id'), sqlc.arg('message'))
+RETURNING *;
+
+-- name: GetChatMessage :one
+SELECT * FROM chat_messages
+WHERE id = sqlc.arg('id') AND room_id = sqlc.arg('room_id');
+
+-- name: ListChatMessages :many
+SELECT * FROM chat_messages
+WHERE room_id = sqlc.arg('room_id')
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SetChatMemberLastMessage :exec
+UPDATE chat_members
+SET last_message_id = sqlc.arg('message_id'), updated_at = now()
+WHERE room_id = sqlc.arg('room_id') AND user_id = sqlc.arg('user_id');
@@ -0,0 +1,312 @@
+-- name: CreateKanban :one
+-- Policies left out take the column defaults
+INSERT INTO kanbans (id, project_id, name, status, blocked_move_policy, wip_policy)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('project_id'),
+    sqlc.arg('name'),
+    sqlc.arg('status'),
+    COALESCE(sqlc.narg('blocked_move_policy')::varchar, 'warn'),
+    COALESCE(sqlc.narg('wip_policy')::varchar, 'warn')
+)
+RETURNING *;
+
+-- name: ListKanbansByProject :many
+SELECT * FROM kanbans
+WHERE project_id = sqlc.arg('project_id')
+ORDER BY created_at;
+
+-- name: CreateKanbanCategory :one
+INSERT INTO kanban_categories (id, kanban_id, name, rank, done, wip_limit)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_id'),
+    sqlc.arg('name'),
+    sqlc.arg('rank'),
+    sqlc.arg('done'),
+    sqlc.narg('wip_limit')
+)
+RETURNING *;
+
+-- name: ListKanbanCategories :many
+SELECT * FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NULL
+ORDER BY rank, created_at;
+
+-- name: CreateKanbanItem :one
+INSERT INTO kanban_items (
+    id,
+    kanban_category_id,
+    title,
+    description,
+    priority,
+    due_date,
+    estimated_time,
+    rank,
+    lane_id,
+    completed_at
+) VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_category_id'),
+    sqlc.arg('title'),
+    sqlc.narg('description'),
+    sqlc.arg('priority'),
+    sqlc.narg('due_date'),
+    sqlc.narg('estimated_time'),
+    sqlc.arg('rank'),
+    sqlc.narg('lane_id'),
+    CASE WHEN (SELECT c.done FROM kanban_categories AS c WHERE c.id = sqlc.arg('kanban_category_id')) THEN now() END
+)
+RETURNING *;
+
+-- name: ListKanbanItems :many
+SELECT i.* FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id')
+  AND c.deleted_at IS NULL
+  AND i.deleted_at IS NULL
+ORDER BY c.rank, i.rank, i.created_at;
+
+-- name: GetKanban :one
+SELECT * FROM kanbans
+WHERE id = sqlc.arg('id') AND project_id = sqlc.arg('project_id');
+
+-- name: GetKanbanByID :one
+SELECT * FROM kanbans WHERE id = sqlc.arg('id');
+
+-- name: UpdateKanban :one
+-- A set expected_version only matches the kanban while it is at that version
+UPDATE kanbans
+SET
+    name       = COALESCE(sqlc.narg('name'), name),
+    status     = COALESCE(NULLIF(sqlc.arg('status')::text, '')::kanban_status, status),
+    blocked_move_policy = COALESCE(sqlc.narg('blocked_move_policy'), blocked_move_policy),
+    wip_policy = COALESCE(sqlc.narg('wip_policy'), wip_policy),
+    version    = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND project_id = sqlc.arg('project_id')
+  AND (sqlc.narg('expected_version')::integer IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING *;
+
+-- name: DeleteKanban :execrows
+DELETE FROM kanbans
+WHERE id = sqlc.arg('id') AND project_id = sqlc.arg('project_id');
+
+-- name: GetKanbanCategory :one
+SELECT * FROM kanban_categories
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: UpdateKanbanCategory :one
+UPDATE kanban_categories
+SET
+    name       = COALESCE(sqlc.narg('name'), name),
+    done       = COALESCE(sqlc.narg('done'), done),
+    wip_limit  = CASE WHEN sqlc.arg('set_wip_limit')::boolean
+                      THEN sqlc.narg('wip_limit')::integer ELSE wip_limit END,
+    version    = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::integer IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING *;
+
+-- name: SoftDeleteKanbanCategory :one
+UPDATE kanban_categories
+SET deleted_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreKanbanCategory :one
+UPDATE kanban_categories
+SET deleted_at = NULL, updated_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PermaDeleteKanbanCategory :execrows
+DELETE FROM kanban_categories
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedKanbanCategories :many
+SELECT * FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountDeletedKanbanCategories :one
+SELECT COUNT(*) FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id') AND deleted_at IS NOT NULL;
+
+-- name: GetKanbanItem :one
+SELECT i.* FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE i.id = sqlc.arg('id') AND c.kanban_id = sqlc.arg('kanban_id');
+
+-- name: UpdateKanbanItem :one
+UPDATE kanban_items
+SET
+    title          = COALESCE(sqlc.narg('title'), title),
+    priority       = COALESCE(NULLIF(sqlc.arg('priority')::text, '')::kanban_item_priority, priority),
+    description    = CASE WHEN sqlc.arg('set_description')::boolean
+                          THEN sqlc.narg('description')::text ELSE description END,
+    due_date       = CASE WHEN sqlc.arg('set_due_date')::boolean
+                          THEN sqlc.narg('due_date')::timestamptz ELSE due_date END,
+    estimated_time = CASE WHEN sqlc.arg('set_estimated_time')::boolean
+                          THEN sqlc.narg('estimated_time')::integer ELSE estimated_time END,
+    version        = version + 1,
+    updated_at     = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::integer IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING *;
+
+-- name: MoveKanbanItem :one
+-- Moving into a done column stamps the completion time, moving between done
+-- columns keeps it and moving out of them clears it. The lane is only
+-- changed when set_lane is true.
+UPDATE kanban_items AS i
+SET
+    kanban_category_id = sqlc.arg('kanban_category_id'),
+    rank = sqlc.arg('rank'),
+    lane_id = CASE WHEN sqlc.arg('set_lane')::boolean THEN sqlc.narg('lane_id')::varchar ELSE i.lane_id END,
+    completed_at = CASE WHEN c.done THEN COALESCE(i.completed_at, now()) END,
+    updated_at = now()
+FROM kanban_categories AS c
+WHERE i.id = sqlc.arg('id') AND i.deleted_at IS NULL AND c.id = sqlc.arg('kanban_category_id')
+RETURNING i.*;
+
+-- name: SoftDeleteKanbanItem :one
+UPDATE kanban_items
+SET deleted_at = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreKanbanItem :one
+UPDATE kanban_items
+SET deleted_at = NULL, updated_at = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PermaDeleteKanbanItem :execrows
+DELETE FROM kanban_items
+WHERE id = sqlc.arg('id') AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedKanbanItems :many
+SELECT i.* FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id') AND i.deleted_at IS NOT NULL
+ORDER BY i.deleted_at DESC, i.id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountDeletedKanbanItems :one
+SELECT COUNT(*) FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id') AND i.deleted_at IS NOT NULL;
+
+-- Ordering. Neighbour lookups include archived rows so restored rows keep a unique rank.
+
+-- name: LockKanban :exec
+SELECT id FROM kanbans WHERE id = sqlc.arg('id') FOR UPDATE;
+
+-- name: LockKanbanCategory :exec
+SELECT id FROM kanban_categories WHERE id = sqlc.arg('id') FOR UPDATE;
+
+-- name: LastKanbanCategoryRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id');
+
+-- name: NextKanbanCategoryRank :one
+SELECT COALESCE(MIN(rank), '')::text FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id') AND rank > sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: PrevKanbanCategoryRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id') AND rank < sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: SetKanbanCategoryRank :one
+UPDATE kanban_categories
+SET rank = sqlc.arg('rank'), updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ListKanbanCategoryRanks :many
+SELECT id, rank FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id')
+ORDER BY rank, created_at;
+
+-- name: LastKanbanItemRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id');
+
+-- name: NextKanbanItemRank :one
+SELECT COALESCE(MIN(rank), '')::text FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id') AND rank > sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: PrevKanbanItemRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id') AND rank < sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: SetKanbanItemRank :exec
+UPDATE kanban_items SET rank = sqlc.arg('rank') WHERE id = sqlc.arg('id');
+
+-- name: ListKanbanItemRanks :many
+SELECT id, rank FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id')
+ORDER BY rank, created_at;
+
+-- name: ListProjectKanbanItems :many
+-- Active cards across all kanbans of a project, every filter is optional
+SELECT
+    i.*,
+    c.kanban_id,
+    k.name AS kanban_name,
+    EXISTS (
+        SELECT 1 FROM kanban_item_links AS l
+        JOIN kanban_items AS b ON b.id = l.item_id
+        JOIN kanban_categories AS bc ON bc.id = b.kanban_category_id
+        WHERE l.linked_item_id = i.id AND l.type = 'blocks'
+          AND b.deleted_at IS NULL AND bc.deleted_at IS NULL AND NOT bc.done
+    ) AS blocked
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+JOIN kanbans AS k ON k.id = c.kanban_id
+WHERE k.project_id = sqlc.arg('project_id')
+  AND i.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND (sqlc.narg('kanban_id')::text IS NULL OR k.id = sqlc.narg('kanban_id')::text)
+  AND (sqlc.narg('assignee_id')::text IS NULL OR EXISTS (
+    SELECT 1 FROM kanban_item_assignees AS a
+    WHERE a.item_id = i.id AND a.user_id = sqlc.narg('assignee_id')::text
+  ))
+  AND (sqlc.narg('watcher_id')::text IS NULL OR EXISTS (
+    SELECT 1 FROM kanban_item_watchers AS w
+    WHERE w.item_id = i.id AND w.user_id = sqlc.narg('watcher_id')::text
+  ))
+  AND (sqlc.narg('label_id')::text IS NULL OR EXISTS (
+    SELECT 1 FROM kanban_item_labels AS il
+    WHERE il.item_id = i.id AND il.label_id = sqlc.narg('label_id')::text
+  ))
+ORDER BY i.due_date NULLS LAST, k.name, c.rank, i.rank
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: LockKanbanItem :exec
+SELECT id FROM kanban_items WHERE id = sqlc.arg('id') FOR UPDATE;
+
+-- name: SetKanbanItemParent :one
+UPDATE kanban_items
+SET parent_item_id = sqlc.arg('parent_item_id'), updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: CountKanbanCategoryItems :one
+-- Active cards in a category, other than the one being moved
+SELECT COUNT(*) FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id')
+  AND deleted_at IS NULL
+  AND id <> sqlc.arg('exclude_id');
+
+-- name: SetKanbanCategoryItemsCompleted :exec
+-- Stamps or clears the completion time of every card after a category
+-- became or stopped being a done column
+UPDATE kanban_items
+SET completed_at = CASE WHEN sqlc.arg('done')::boolean THEN COALESCE(completed_at, now()) END
+WHERE kanban_category_id = sqlc.arg('kanban_category_id');
@@ -0,0 +1,49 @@
+-- name: CreateKanbanActivity :one
+INSERT INTO kanban_activity (id, kanban_id, actor_id, rule_id, entity_type, entity_id, item_id, action, changes)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_id'),
+    sqlc.narg('actor_id'),
+    sqlc.narg('rule_id'),
+    sqlc.arg('entity_type'),
+    sqlc.arg('entity_id'),
+    sqlc.narg('item_id'),
+    sqlc.arg('action'),
+    sqlc.arg('changes')
+)
+RETURNING *;
+
+-- name: ListKanbanActivity :many
+SELECT
+    a.*,
+    u.username AS actor_username,
+    r.name AS rule_name
+FROM kanban_activity AS a
+LEFT JOIN users AS u ON u.id = a.actor_id
+LEFT JOIN kanban_rules AS r ON r.id = a.rule_id
+WHERE a.kanban_id = sqlc.arg('kanban_id')
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListKanbanItemActivity :many
+SELECT
+    a.*,
+    u.username AS actor_username,
+    r.name AS rule_name
+FROM kanban_activity AS a
+LEFT JOIN users AS u ON u.id = a.actor_id
+LEFT JOIN kanban_rules AS r ON r.id = a.rule_id
+WHERE a.item_id = sqlc.arg('item_id')::text
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetKanbanItemActivity :one
+SELECT * FROM kanban_activity
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id')::text;
+
+-- name: ListKanbanItemActivitySince :many
+-- Changes to an item made after an entry, newest first, used to revert it
+SELECT * FROM kanban_activity
+WHERE item_id = sqlc.arg('item_id')::text
+  AND (created_at, id) > (sqlc.arg('created_at')::timestamptz, sqlc.arg('id')::text)
+ORDER BY created_at DESC, id DESC;
@@ -0,0 +1,27 @@
+-- name: ListExpiredKanbanCategories :many
+-- Archived categories kept longer than their organisation's retention, locked
+-- so several instances of the API can purge at the same time
+SELECT c.* FROM kanban_categories AS c
+JOIN kanbans AS k ON k.id = c.kanban_id
+JOIN projects AS p ON p.id = k.project_id
+JOIN organisations AS o ON o.id = p.organisation_id
+WHERE c.deleted_at IS NOT NULL
+  AND o.kanban_archive_retention_days IS NOT NULL
+  AND c.deleted_at < now() - o.kanban_archive_retention_days * INTERVAL '1 day'
+ORDER BY c.deleted_at
+LIMIT sqlc.arg('limit')
+FOR UPDATE OF c SKIP LOCKED;
+
+-- name: ListExpiredKanbanItems :many
+-- Archived cards kept longer than their organisation's retention
+SELECT i.id, c.kanban_id FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+JOIN kanbans AS k ON k.id = c.kanban_id
+JOIN projects AS p ON p.id = k.project_id
+JOIN organisations AS o ON o.id = p.organisation_id
+WHERE i.deleted_at IS NOT NULL
+  AND o.kanban_archive_retention_days IS NOT NULL
+  AND i.deleted_at < now() - o.kanban_archive_retention_days * INTERVAL '1 day'
+ORDER BY i.deleted_at
+LIMIT sqlc.arg('limit')
+FOR UPDATE OF i SKIP LOCKED;
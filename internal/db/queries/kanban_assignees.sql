@@ -0,0 +1,45 @@
+-- name: AddKanbanItemAssignee :execrows
+INSERT INTO kanban_item_assignees (item_id, user_id)
+VALUES (sqlc.arg('item_id'), sqlc.arg('user_id'))
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveKanbanItemAssignee :execrows
+DELETE FROM kanban_item_assignees
+WHERE item_id = sqlc.arg('item_id') AND user_id = sqlc.arg('user_id');
+
+-- name: ListKanbanItemAssignees :many
+SELECT
+    a.user_id,
+    u.username,
+    u.avatar
+FROM kanban_item_assignees AS a
+JOIN users AS u ON u.id = a.user_id
+WHERE a.item_id = sqlc.arg('item_id')
+ORDER BY u.username;
+
+-- name: ListKanbanAssignees :many
+SELECT a.item_id, a.user_id
+FROM kanban_item_assignees AS a
+JOIN kanban_items AS i ON i.id = a.item_id
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id')
+ORDER BY a.created_at;
+
+-- name: AddKanbanItemWatcher :execrows
+INSERT INTO kanban_item_watchers (item_id, user_id)
+VALUES (sqlc.arg('item_id'), sqlc.arg('user_id'))
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveKanbanItemWatcher :execrows
+DELETE FROM kanban_item_watchers
+WHERE item_id = sqlc.arg('item_id') AND user_id = sqlc.arg('user_id');
+
+-- name: ListKanbanItemWatchers :many
+SELECT
+    w.user_id,
+    u.username,
+    u.avatar
+FROM kanban_item_watchers AS w
+JOIN users AS u ON u.id = w.user_id
+WHERE w.item_id = sqlc.arg('item_id')
+ORDER BY u.username;
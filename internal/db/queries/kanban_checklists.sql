@@ -0,0 +1,79 @@
+-- name: CreateKanbanChecklistEntry :one
+INSERT INTO kanban_checklist_entries (id, item_id, title, assignee_id, due_date, rank)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('item_id'),
+    sqlc.arg('title'),
+    sqlc.narg('assignee_id'),
+    sqlc.narg('due_date'),
+    sqlc.arg('rank')
+)
+RETURNING *;
+
+-- name: GetKanbanChecklistEntry :one
+SELECT * FROM kanban_checklist_entries
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id');
+
+-- name: ListKanbanChecklistEntries :many
+SELECT * FROM kanban_checklist_entries
+WHERE item_id = sqlc.arg('item_id')
+ORDER BY rank, created_at;
+
+-- name: UpdateKanbanChecklistEntry :one
+UPDATE kanban_checklist_entries
+SET
+    title = COALESCE(sqlc.narg('title'), title),
+    done = COALESCE(sqlc.narg('done'), done),
+    done_at = CASE
+        WHEN sqlc.narg('done')::boolean IS NULL THEN done_at
+        WHEN sqlc.narg('done')::boolean AND NOT done THEN now()
+        WHEN sqlc.narg('done')::boolean THEN done_at
+        ELSE NULL
+    END,
+    assignee_id = CASE WHEN sqlc.arg('set_assignee_id')::boolean THEN sqlc.narg('assignee_id') ELSE assignee_id END,
+    due_date = CASE WHEN sqlc.arg('set_due_date')::boolean THEN sqlc.narg('due_date') ELSE due_date END,
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: SetKanbanChecklistEntryPromoted :one
+UPDATE kanban_checklist_entries
+SET promoted_item_id = sqlc.arg('promoted_item_id'), updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteKanbanChecklistEntry :execrows
+DELETE FROM kanban_checklist_entries
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id');
+
+-- name: LastKanbanChecklistRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_checklist_entries
+WHERE item_id = sqlc.arg('item_id');
+
+-- name: NextKanbanChecklistRank :one
+SELECT COALESCE(MIN(rank), '')::text FROM kanban_checklist_entries
+WHERE item_id = sqlc.arg('item_id') AND rank > sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: PrevKanbanChecklistRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_checklist_entries
+WHERE item_id = sqlc.arg('item_id') AND rank < sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: SetKanbanChecklistRank :exec
+UPDATE kanban_checklist_entries SET rank = sqlc.arg('rank') WHERE id = sqlc.arg('id');
+
+-- name: ListKanbanChecklistRanks :many
+SELECT id, rank FROM kanban_checklist_entries
+WHERE item_id = sqlc.arg('item_id')
+ORDER BY rank, created_at;
+
+-- name: ListKanbanChecklistProgress :many
+-- Done and total checklist entries per card of a kanban
+SELECT
+    e.item_id,
+    COUNT(*) FILTER (WHERE e.done) AS done,
+    COUNT(*) AS total
+FROM kanban_checklist_entries AS e
+JOIN kanban_items AS i ON i.id = e.item_id
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id')
+GROUP BY e.item_id;
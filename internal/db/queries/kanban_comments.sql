@@ -0,0 +1,95 @@
+-- name: CreateKanbanItemComment :one
+INSERT INTO kanban_item_comments (id, item_id, parent_id, author_id, body)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('item_id'),
+    sqlc.narg('parent_id'),
+    sqlc.arg('author_id'),
+    sqlc.arg('body')
+)
+RETURNING *;
+
+-- name: GetKanbanItemComment :one
+SELECT * FROM kanban_item_comments
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id');
+
+-- name: ListKanbanItemComments :many
+-- Deleted comments keep their place in the thread without a body
+SELECT
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edited_at,
+    c.deleted_at,
+    c.item_id,
+    c.parent_id,
+    c.author_id,
+    (CASE WHEN c.deleted_at IS NULL THEN c.body ELSE '' END)::text AS body,
+    u.username AS author_username,
+    u.avatar AS author_avatar
+FROM kanban_item_comments AS c
+LEFT JOIN users AS u ON u.id = c.author_id
+WHERE c.item_id = sqlc.arg('item_id')
+ORDER BY c.created_at, c.id;
+
+-- name: UpdateKanbanItemComment :one
+UPDATE kanban_item_comments
+SET
+    body = sqlc.arg('body'),
+    edited_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteKanbanItemComment :one
+UPDATE kanban_item_comments
+SET deleted_at = now(), updated_at = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateKanbanItemCommentRevision :exec
+INSERT INTO kanban_item_comment_revisions (id, comment_id, editor_id, action, body)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('comment_id'),
+    sqlc.arg('editor_id'),
+    sqlc.arg('action'),
+    sqlc.arg('body')
+);
+
+-- name: ListKanbanItemCommentRevisions :many
+SELECT
+    r.*,
+    u.username AS editor_username
+FROM kanban_item_comment_revisions AS r
+LEFT JOIN users AS u ON u.id = r.editor_id
+WHERE r.comment_id = sqlc.arg('comment_id')
+ORDER BY r.created_at;
+
+-- name: AddKanbanItemCommentMentions :many
+-- Returns only the users that were not mentioned before
+INSERT INTO kanban_item_comment_mentions (comment_id, user_id)
+SELECT sqlc.arg('comment_id'), unnest(sqlc.arg('user_ids')::text[])
+ON CONFLICT DO NOTHING
+RETURNING user_id;
+
+-- name: RemoveKanbanItemCommentMentions :exec
+-- Drops mentions that are no longer in the comment body
+DELETE FROM kanban_item_comment_mentions
+WHERE comment_id = sqlc.arg('comment_id')
+  AND NOT (user_id = ANY(sqlc.arg('keep_user_ids')::text[]));
+
+-- name: ListKanbanItemCommentMentions :many
+SELECT m.comment_id, m.user_id
+FROM kanban_item_comment_mentions AS m
+JOIN kanban_item_comments AS c ON c.id = m.comment_id
+WHERE c.item_id = sqlc.arg('item_id');
+
+-- name: ResolveOrganisationUsernames :many
+-- Maps usernames to members of an organisation, unknown names are left out
+SELECT u.id, u.username
+FROM users AS u
+JOIN organisation_members AS m ON m.user_id = u.id
+WHERE m.organisation_id = sqlc.arg('organisation_id')
+  AND lower(u.username) = ANY(sqlc.arg('usernames')::text[])
+  AND u.deleted_at IS NULL;
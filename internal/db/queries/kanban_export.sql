@@ -0,0 +1,29 @@
+-- name: ListKanbanExportCategories :many
+SELECT * FROM kanban_categories
+WHERE kanban_id = sqlc.arg('kanban_id')
+  AND (sqlc.arg('include_archived')::boolean OR deleted_at IS NULL)
+ORDER BY rank, created_at;
+
+-- name: ListKanbanExportItems :many
+-- One page of the items of a category in board order, pages continue after
+-- the rank and id of the last item of the previous one
+SELECT * FROM kanban_items
+WHERE kanban_category_id = sqlc.arg('kanban_category_id')
+  AND (sqlc.arg('include_archived')::boolean OR deleted_at IS NULL)
+  AND (rank, id) > (sqlc.arg('after_rank')::varchar, sqlc.arg('after_id')::varchar)
+ORDER BY rank, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListKanbanExportItemLabels :many
+SELECT il.item_id, l.name, l.color
+FROM kanban_item_labels AS il
+JOIN kanban_labels AS l ON l.id = il.label_id
+WHERE il.item_id = ANY(sqlc.arg('item_ids')::text[])
+ORDER BY il.created_at;
+
+-- name: ListKanbanExportItemAssignees :many
+SELECT a.item_id, u.username, u.email
+FROM kanban_item_assignees AS a
+JOIN users AS u ON u.id = a.user_id
+WHERE a.item_id = ANY(sqlc.arg('item_ids')::text[])
+ORDER BY a.created_at;
@@ -0,0 +1,46 @@
+-- name: CreateKanbanImport :one
+INSERT INTO kanban_imports (
+    id,
+    project_id,
+    kanban_id,
+    source,
+    file_name,
+    dry_run,
+    row_count,
+    imported_count,
+    issues,
+    created_by
+) VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('project_id'),
+    sqlc.narg('kanban_id'),
+    sqlc.arg('source'),
+    sqlc.arg('file_name'),
+    sqlc.arg('dry_run'),
+    sqlc.arg('row_count'),
+    sqlc.arg('imported_count'),
+    sqlc.arg('issues'),
+    sqlc.narg('created_by')
+)
+RETURNING *;
+
+-- name: GetKanbanImport :one
+SELECT * FROM kanban_imports
+WHERE id = sqlc.arg('id') AND project_id = sqlc.arg('project_id');
+
+-- name: CreateKanbanItemInvitation :execrows
+INSERT INTO kanban_item_invitations (item_id, email, project_id, invited_by)
+VALUES (sqlc.arg('item_id'), lower(sqlc.arg('email')), sqlc.arg('project_id'), sqlc.narg('invited_by'))
+ON CONFLICT DO NOTHING;
+
+-- name: AcceptKanbanItemInvitations :execrows
+-- Turns the invitations of a user who joined a project into assignees
+WITH accepted AS (
+    DELETE FROM kanban_item_invitations
+    WHERE project_id = sqlc.arg('project_id')
+      AND email = (SELECT lower(u.email) FROM users AS u WHERE u.id = sqlc.arg('user_id'))
+    RETURNING item_id
+)
+INSERT INTO kanban_item_assignees (item_id, user_id)
+SELECT item_id, sqlc.arg('user_id')::varchar FROM accepted
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,77 @@
+-- name: CreateKanbanLabel :one
+INSERT INTO kanban_labels (id, organisation_id, kanban_id, name, color)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('organisation_id'),
+    sqlc.narg('kanban_id'),
+    sqlc.arg('name'),
+    sqlc.arg('color')
+)
+RETURNING *;
+
+-- name: GetKanbanLabel :one
+SELECT * FROM kanban_labels
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
+
+-- name: ListOrganisationLabels :many
+SELECT * FROM kanban_labels
+WHERE organisation_id = sqlc.arg('organisation_id') AND kanban_id IS NULL
+ORDER BY lower(name);
+
+-- name: ListBoardLabels :many
+-- Labels usable on a kanban, the organisation wide ones first
+SELECT l.* FROM kanban_labels AS l
+JOIN kanbans AS k ON k.id = sqlc.arg('kanban_id')
+JOIN projects AS p ON p.id = k.project_id
+WHERE l.organisation_id = p.organisation_id
+  AND (l.kanban_id IS NULL OR l.kanban_id = k.id)
+ORDER BY l.kanban_id NULLS FIRST, lower(l.name);
+
+-- name: GetBoardLabel :one
+SELECT l.* FROM kanban_labels AS l
+JOIN kanbans AS k ON k.id = sqlc.arg('kanban_id')
+JOIN projects AS p ON p.id = k.project_id
+WHERE l.id = sqlc.arg('id')
+  AND l.organisation_id = p.organisation_id
+  AND (l.kanban_id IS NULL OR l.kanban_id = k.id);
+
+-- name: UpdateKanbanLabel :one
+UPDATE kanban_labels
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    color = COALESCE(sqlc.narg('color'), color),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')
+RETURNING *;
+
+-- name: DeleteKanbanLabel :execrows
+DELETE FROM kanban_labels
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
+
+-- name: AddKanbanItemLabel :execrows
+INSERT INTO kanban_item_labels (item_id, label_id)
+VALUES (sqlc.arg('item_id'), sqlc.arg('label_id'))
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveKanbanItemLabel :execrows
+DELETE FROM kanban_item_labels
+WHERE item_id = sqlc.arg('item_id') AND label_id = sqlc.arg('label_id');
+
+-- name: ListKanbanItemLabels :many
+SELECT l.* FROM kanban_labels AS l
+JOIN kanban_item_labels AS il ON il.label_id = l.id
+WHERE il.item_id = sqlc.arg('item_id')
+ORDER BY lower(l.name);
+
+-- name: ListKanbanLabelLinks :many
+SELECT il.item_id, il.label_id
+FROM kanban_item_labels AS il
+JOIN kanban_items AS i ON i.id = il.item_id
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id')
+ORDER BY il.created_at;
+
+-- name: ListOrganisationKanbanIDs :many
+SELECT k.id FROM kanbans AS k
+JOIN projects AS p ON p.id = k.project_id
+WHERE p.organisation_id = sqlc.arg('organisation_id');
@@ -0,0 +1,56 @@
+-- name: CreateKanbanLane :one
+INSERT INTO kanban_lanes (id, kanban_id, name, color, rank)
+VALUES (sqlc.arg('id'), sqlc.arg('kanban_id'), sqlc.arg('name'), sqlc.narg('color'), sqlc.arg('rank'))
+RETURNING *;
+
+-- name: GetKanbanLane :one
+SELECT * FROM kanban_lanes
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: ListKanbanLanes :many
+SELECT * FROM kanban_lanes
+WHERE kanban_id = sqlc.arg('kanban_id')
+ORDER BY rank, created_at;
+
+-- name: UpdateKanbanLane :one
+UPDATE kanban_lanes
+SET
+    name       = COALESCE(sqlc.narg('name'), name),
+    color      = CASE WHEN sqlc.arg('set_color')::boolean THEN sqlc.narg('color')::varchar ELSE color END,
+    version    = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id')
+  AND (sqlc.narg('expected_version')::integer IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING *;
+
+-- name: DeleteKanbanLane :execrows
+-- Cards of the lane are left without one
+DELETE FROM kanban_lanes
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: LastKanbanLaneRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_lanes
+WHERE kanban_id = sqlc.arg('kanban_id');
+
+-- name: NextKanbanLaneRank :one
+SELECT COALESCE(MIN(rank), '')::text FROM kanban_lanes
+WHERE kanban_id = sqlc.arg('kanban_id') AND rank > sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: PrevKanbanLaneRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM kanban_lanes
+WHERE kanban_id = sqlc.arg('kanban_id') AND rank < sqlc.arg('rank')::text AND id <> sqlc.arg('exclude_id');
+
+-- name: SetKanbanLaneRank :one
+UPDATE kanban_lanes
+SET rank = sqlc.arg('rank'), updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ListKanbanLaneRanks :many
+SELECT id, rank FROM kanban_lanes
+WHERE kanban_id = sqlc.arg('kanban_id')
+ORDER BY rank, created_at;
+
+-- name: ListKanbanLaneItemIDs :many
+SELECT id FROM kanban_items
+WHERE lane_id = sqlc.arg('lane_id') AND deleted_at IS NULL;
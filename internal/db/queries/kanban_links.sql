@@ -0,0 +1,103 @@
+-- name: GetProjectKanbanItem :one
+-- A card of any kanban in the project, used for links across kanbans
+SELECT i.*, c.kanban_id, c.done AS category_done
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+JOIN kanbans AS k ON k.id = c.kanban_id
+WHERE i.id = sqlc.arg('id') AND k.project_id = sqlc.arg('project_id');
+
+-- name: LockKanbanItemLinks :exec
+-- Serialises link changes within a project so two concurrent links can't form a cycle
+SELECT pg_advisory_xact_lock(hashtextextended('kanban_item_links:' || sqlc.arg('project_id')::text, 0));
+
+-- name: KanbanItemBlockPathExists :one
+-- Whether from blocks to, directly or through other cards
+WITH RECURSIVE reach(id) AS (
+    SELECT l.linked_item_id FROM kanban_item_links AS l
+    WHERE l.item_id = sqlc.arg('from_item_id') AND l.type = 'blocks'
+    UNION
+    SELECT l.linked_item_id FROM kanban_item_links AS l
+    JOIN reach AS r ON l.item_id = r.id
+    WHERE l.type = 'blocks'
+)
+SELECT EXISTS (SELECT 1 FROM reach WHERE id = sqlc.arg('to_item_id')::text);
+
+-- name: CreateKanbanItemLink :one
+INSERT INTO kanban_item_links (id, project_id, item_id, linked_item_id, type, created_by)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('project_id'),
+    sqlc.arg('item_id'),
+    sqlc.arg('linked_item_id'),
+    sqlc.arg('type'),
+    sqlc.narg('created_by')
+)
+RETURNING *;
+
+-- name: GetKanbanItemLink :one
+-- A link with the card on either end
+SELECT * FROM kanban_item_links
+WHERE id = sqlc.arg('id') AND (item_id = sqlc.arg('item_id') OR linked_item_id = sqlc.arg('item_id'));
+
+-- name: DeleteKanbanItemLink :execrows
+DELETE FROM kanban_item_links WHERE id = sqlc.arg('id');
+
+-- name: ListKanbanItemLinks :many
+-- Links of a card seen from that card, relation is blocks, blocked_by or relates
+SELECT
+    l.id,
+    l.created_at,
+    l.created_by,
+    (CASE
+        WHEN l.type = 'relates' THEN 'relates'
+        WHEN l.item_id = sqlc.arg('item_id') THEN 'blocks'
+        ELSE 'blocked_by'
+    END)::text AS relation,
+    o.id AS linked_item_id,
+    o.title AS linked_title,
+    c.kanban_id AS linked_kanban_id,
+    o.kanban_category_id AS linked_category_id,
+    (c.done OR o.deleted_at IS NOT NULL OR c.deleted_at IS NOT NULL)::boolean AS linked_finished
+FROM kanban_item_links AS l
+JOIN kanban_items AS o ON o.id = CASE WHEN l.item_id = sqlc.arg('item_id') THEN l.linked_item_id ELSE l.item_id END
+JOIN kanban_categories AS c ON c.id = o.kanban_category_id
+WHERE l.item_id = sqlc.arg('item_id') OR l.linked_item_id = sqlc.arg('item_id')
+ORDER BY l.created_at;
+
+-- name: ListKanbanItemBlockers :many
+-- Unfinished cards blocking a card, archived cards and cards in a done column don't block
+SELECT b.id, b.title, bc.kanban_id
+FROM kanban_item_links AS l
+JOIN kanban_items AS b ON b.id = l.item_id
+JOIN kanban_categories AS bc ON bc.id = b.kanban_category_id
+WHERE l.linked_item_id = sqlc.arg('item_id') AND l.type = 'blocks'
+  AND b.deleted_at IS NULL AND bc.deleted_at IS NULL AND NOT bc.done
+ORDER BY b.title;
+
+-- name: ListBlockedKanbanItems :many
+-- Cards of a kanban with at least one unfinished blocker
+SELECT DISTINCT l.linked_item_id
+FROM kanban_item_links AS l
+JOIN kanban_items AS t ON t.id = l.linked_item_id
+JOIN kanban_categories AS tc ON tc.id = t.kanban_category_id
+JOIN kanban_items AS b ON b.id = l.item_id
+JOIN kanban_categories AS bc ON bc.id = b.kanban_category_id
+WHERE l.type = 'blocks' AND tc.kanban_id = sqlc.arg('kanban_id')
+  AND b.deleted_at IS NULL AND bc.deleted_at IS NULL AND NOT bc.done;
+
+-- name: ListKanbanItemDependents :many
+-- Cards blocked by a card with their current blocked state
+SELECT
+    t.id,
+    tc.kanban_id,
+    EXISTS (
+        SELECT 1 FROM kanban_item_links AS l2
+        JOIN kanban_items AS b ON b.id = l2.item_id
+        JOIN kanban_categories AS bc ON bc.id = b.kanban_category_id
+        WHERE l2.linked_item_id = t.id AND l2.type = 'blocks'
+          AND b.deleted_at IS NULL AND bc.deleted_at IS NULL AND NOT bc.done
+    ) AS blocked
+FROM kanban_item_links AS l
+JOIN kanban_items AS t ON t.id = l.linked_item_id
+JOIN kanban_categories AS tc ON tc.id = t.kanban_category_id
+WHERE l.item_id = sqlc.arg('item_id') AND l.type = 'blocks';
@@ -0,0 +1,53 @@
+-- name: CreateKanbanItemTransition :exec
+INSERT INTO kanban_item_transitions (id, kanban_id, item_id, from_category_id, to_category_id)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_id'),
+    sqlc.arg('item_id'),
+    sqlc.narg('from_category_id'),
+    sqlc.arg('to_category_id')
+);
+
+-- name: ListKanbanCompletedItems :many
+-- Cards of a kanban completed within the range. Work on a card starts when it
+-- first enters a category after the first column of the board.
+SELECT
+    i.id,
+    i.title,
+    i.created_at,
+    i.completed_at,
+    (
+        SELECT MIN(t.moved_at) FROM kanban_item_transitions AS t
+        WHERE t.item_id = i.id AND t.to_category_id <> (
+            SELECT f.id FROM kanban_categories AS f
+            WHERE f.kanban_id = c.kanban_id AND f.deleted_at IS NULL
+            ORDER BY f.rank, f.created_at
+            LIMIT 1
+        )
+    )::timestamptz AS started_at
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE c.kanban_id = sqlc.arg('kanban_id')
+  AND i.deleted_at IS NULL
+  AND i.completed_at >= sqlc.arg('from')::timestamptz
+  AND i.completed_at < sqlc.arg('to')::timestamptz
+ORDER BY i.completed_at;
+
+-- name: KanbanCumulativeFlow :many
+-- Cards per category at the end of every day of the range
+SELECT
+    d.day::date AS day,
+    s.to_category_id AS category_id,
+    COUNT(*)::integer AS items
+FROM generate_series(sqlc.arg('from')::date, sqlc.arg('to')::date, interval '1 day') AS d(day)
+JOIN LATERAL (
+    SELECT DISTINCT ON (t.item_id) t.item_id, t.to_category_id
+    FROM kanban_item_transitions AS t
+    JOIN kanban_items AS i ON i.id = t.item_id
+    WHERE t.kanban_id = sqlc.arg('kanban_id')
+      AND t.moved_at < d.day + interval '1 day'
+      AND (i.deleted_at IS NULL OR i.deleted_at >= d.day + interval '1 day')
+    ORDER BY t.item_id, t.moved_at DESC, t.id DESC
+) AS s ON true
+GROUP BY d.day, s.to_category_id
+ORDER BY d.day, s.to_category_id;
@@ -0,0 +1,92 @@
+-- name: CreateKanbanRecurrence :one
+INSERT INTO kanban_recurrences (
+    id,
+    kanban_id,
+    category_id,
+    title,
+    description,
+    priority,
+    estimated_time,
+    rrule,
+    starts_at,
+    until,
+    next_run_at,
+    enabled,
+    created_by
+) VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_id'),
+    sqlc.arg('category_id'),
+    sqlc.arg('title'),
+    sqlc.narg('description'),
+    sqlc.arg('priority'),
+    sqlc.narg('estimated_time'),
+    sqlc.arg('rrule'),
+    sqlc.arg('starts_at'),
+    sqlc.narg('until'),
+    sqlc.narg('next_run_at'),
+    sqlc.arg('enabled'),
+    sqlc.narg('created_by')
+)
+RETURNING *;
+
+-- name: GetKanbanRecurrence :one
+SELECT * FROM kanban_recurrences
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: ListKanbanRecurrences :many
+SELECT * FROM kanban_recurrences
+WHERE kanban_id = sqlc.arg('kanban_id')
+ORDER BY created_at, id;
+
+-- name: UpdateKanbanRecurrence :one
+-- The schedule is recomputed by the service, so next_run_at is always written
+UPDATE kanban_recurrences
+SET
+    category_id = COALESCE(sqlc.narg('category_id'), category_id),
+    title = COALESCE(sqlc.narg('title'), title),
+    description = CASE WHEN sqlc.arg('set_description')::boolean THEN sqlc.narg('description')::text ELSE description END,
+    priority = COALESCE(sqlc.narg('priority'), priority),
+    estimated_time = CASE WHEN sqlc.arg('set_estimated_time')::boolean THEN sqlc.narg('estimated_time')::integer ELSE estimated_time END,
+    rrule = COALESCE(sqlc.narg('rrule'), rrule),
+    starts_at = COALESCE(sqlc.narg('starts_at'), starts_at),
+    until = CASE WHEN sqlc.arg('set_until')::boolean THEN sqlc.narg('until')::timestamptz ELSE until END,
+    next_run_at = sqlc.narg('next_run_at'),
+    enabled = COALESCE(sqlc.narg('enabled'), enabled),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id')
+RETURNING *;
+
+-- name: DeleteKanbanRecurrence :execrows
+DELETE FROM kanban_recurrences
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: ListDueKanbanRecurrenceIDs :many
+SELECT id FROM kanban_recurrences
+WHERE enabled AND next_run_at <= now()
+ORDER BY next_run_at
+LIMIT sqlc.arg('limit');
+
+-- name: LockDueKanbanRecurrence :one
+-- Takes a due recurrence for this transaction, other instances skip it
+SELECT r.*, k.project_id
+FROM kanban_recurrences AS r
+JOIN kanbans AS k ON k.id = r.kanban_id
+WHERE r.id = sqlc.arg('id') AND r.enabled AND r.next_run_at <= now()
+FOR UPDATE OF r SKIP LOCKED;
+
+-- name: ClaimKanbanRecurrenceRun :execrows
+-- Zero rows when the occurrence was already generated
+INSERT INTO kanban_recurrence_runs (recurrence_id, scheduled_at)
+VALUES (sqlc.arg('recurrence_id'), sqlc.arg('scheduled_at'))
+ON CONFLICT DO NOTHING;
+
+-- name: SetKanbanRecurrenceRunItem :exec
+UPDATE kanban_recurrence_runs
+SET item_id = sqlc.arg('item_id')
+WHERE recurrence_id = sqlc.arg('recurrence_id') AND scheduled_at = sqlc.arg('scheduled_at');
+
+-- name: SetKanbanRecurrenceNextRun :exec
+UPDATE kanban_recurrences
+SET next_run_at = sqlc.narg('next_run_at')
+WHERE id = sqlc.arg('id');
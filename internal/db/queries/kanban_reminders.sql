@@ -0,0 +1,108 @@
+-- name: TryKanbanReminderLock :one
+-- Takes a lock held until the transaction ends, false when another
+-- transaction holds it
+SELECT pg_try_advisory_xact_lock(sqlc.arg('key')::bigint)::boolean AS locked;
+
+-- name: ListDueKanbanReminders :many
+-- Open cards with a reminder offset (in seconds) reached and not sent yet.
+-- Only the offset closest to the due date is due, and cards more than
+-- grace_seconds past their due date are left to the overdue digest.
+SELECT
+    p.organisation_id,
+    k.project_id,
+    k.id AS kanban_id,
+    i.id AS item_id,
+    i.title,
+    i.due_date::timestamptz AS due_date,
+    o.offset_seconds::integer AS offset_seconds
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id AND c.deleted_at IS NULL
+JOIN kanbans AS k ON k.id = c.kanban_id
+JOIN projects AS p ON p.id = k.project_id
+CROSS JOIN LATERAL (
+    SELECT MIN(s) AS offset_seconds
+    FROM unnest(sqlc.arg('offsets')::integer[]) AS s
+    WHERE i.due_date - make_interval(secs => s) <= now()
+) AS o
+WHERE i.deleted_at IS NULL
+  AND i.completed_at IS NULL
+  AND i.due_date > now() - make_interval(secs => sqlc.arg('grace_seconds')::integer)
+  AND o.offset_seconds IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM kanban_due_reminders AS r
+      WHERE r.item_id = i.id AND r.due_date = i.due_date AND r.offset_seconds = o.offset_seconds
+  )
+ORDER BY i.due_date
+LIMIT sqlc.arg('limit');
+
+-- name: ClaimKanbanDueReminder :execrows
+-- Marks a reminder as sent, zero rows when it already was
+INSERT INTO kanban_due_reminders (item_id, due_date, offset_seconds)
+VALUES (sqlc.arg('item_id'), sqlc.arg('due_date'), sqlc.arg('offset_seconds'))
+ON CONFLICT DO NOTHING;
+
+-- name: ListKanbanItemReminderRecipients :many
+-- Assignees and watchers of a card
+SELECT u.id AS user_id, u.username, u.email
+FROM users AS u
+WHERE u.deleted_at IS NULL
+  AND (
+      EXISTS (SELECT 1 FROM kanban_item_assignees AS a WHERE a.item_id = sqlc.arg('item_id') AND a.user_id = u.id)
+      OR EXISTS (SELECT 1 FROM kanban_item_watchers AS w WHERE w.item_id = sqlc.arg('item_id') AND w.user_id = u.id)
+  )
+ORDER BY u.username;
+
+-- name: ListKanbanDigestUsers :many
+-- Users assigned to or watching an overdue open card that have not had
+-- their digest for the day
+SELECT u.id AS user_id, u.username, u.email
+FROM users AS u
+WHERE u.deleted_at IS NULL
+  AND EXISTS (
+      SELECT 1
+      FROM kanban_items AS i
+      JOIN kanban_categories AS c ON c.id = i.kanban_category_id AND c.deleted_at IS NULL
+      WHERE i.deleted_at IS NULL
+        AND i.completed_at IS NULL
+        AND i.due_date < now()
+        AND (
+            EXISTS (SELECT 1 FROM kanban_item_assignees AS a WHERE a.item_id = i.id AND a.user_id = u.id)
+            OR EXISTS (SELECT 1 FROM kanban_item_watchers AS w WHERE w.item_id = i.id AND w.user_id = u.id)
+        )
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM kanban_due_digests AS d
+      WHERE d.user_id = u.id AND d.day = sqlc.arg('day')::date
+  )
+ORDER BY u.id
+LIMIT sqlc.arg('limit');
+
+-- name: ClaimKanbanDueDigest :execrows
+-- Marks the digest of a user as sent for the day, zero rows when it already was
+INSERT INTO kanban_due_digests (user_id, day)
+VALUES (sqlc.arg('user_id'), sqlc.arg('day')::date)
+ON CONFLICT DO NOTHING;
+
+-- name: ListUserOverdueKanbanItems :many
+-- Overdue open cards a user is assigned to or watches, longest overdue first
+SELECT
+    p.organisation_id,
+    k.project_id,
+    k.id AS kanban_id,
+    k.name AS kanban_name,
+    i.id AS item_id,
+    i.title,
+    i.due_date::timestamptz AS due_date
+FROM kanban_items AS i
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id AND c.deleted_at IS NULL
+JOIN kanbans AS k ON k.id = c.kanban_id
+JOIN projects AS p ON p.id = k.project_id
+WHERE i.deleted_at IS NULL
+  AND i.completed_at IS NULL
+  AND i.due_date < now()
+  AND (
+      EXISTS (SELECT 1 FROM kanban_item_assignees AS a WHERE a.item_id = i.id AND a.user_id = sqlc.arg('user_id'))
+      OR EXISTS (SELECT 1 FROM kanban_item_watchers AS w WHERE w.item_id = i.id AND w.user_id = sqlc.arg('user_id'))
+  )
+ORDER BY i.due_date, i.id
+LIMIT sqlc.arg('limit');
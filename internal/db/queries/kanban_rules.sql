@@ -0,0 +1,78 @@
+-- name: CreateKanbanRule :one
+INSERT INTO kanban_rules (id, kanban_id, name, enabled, event, category_id, actions, created_by)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('kanban_id'),
+    sqlc.arg('name'),
+    sqlc.arg('enabled'),
+    sqlc.arg('event'),
+    sqlc.narg('category_id'),
+    sqlc.arg('actions'),
+    sqlc.narg('created_by')
+)
+RETURNING *;
+
+-- name: GetKanbanRule :one
+SELECT * FROM kanban_rules
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: ListKanbanRules :many
+SELECT * FROM kanban_rules
+WHERE kanban_id = sqlc.arg('kanban_id')
+ORDER BY created_at, id;
+
+-- name: ListEnabledKanbanRules :many
+-- Rules listening for an event, in the order they were created
+SELECT * FROM kanban_rules
+WHERE kanban_id = sqlc.arg('kanban_id')
+  AND event = sqlc.arg('event')
+  AND enabled
+ORDER BY created_at, id;
+
+-- name: UpdateKanbanRule :one
+UPDATE kanban_rules
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    enabled = COALESCE(sqlc.narg('enabled'), enabled),
+    event = COALESCE(sqlc.narg('event'), event),
+    category_id = CASE WHEN sqlc.arg('set_category_id')::boolean THEN sqlc.narg('category_id')::text ELSE category_id END,
+    actions = COALESCE(sqlc.narg('actions'), actions),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id')
+RETURNING *;
+
+-- name: DeleteKanbanRule :execrows
+DELETE FROM kanban_rules
+WHERE id = sqlc.arg('id') AND kanban_id = sqlc.arg('kanban_id');
+
+-- name: ListOverdueKanbanRuleItems :many
+-- Open cards past their due date that an enabled overdue rule has not fired for yet
+SELECT
+    r.id AS rule_id,
+    k.project_id,
+    k.id AS kanban_id,
+    i.id AS item_id,
+    i.kanban_category_id AS category_id,
+    i.due_date::timestamptz AS due_date
+FROM kanban_rules AS r
+JOIN kanbans AS k ON k.id = r.kanban_id
+JOIN kanban_categories AS c ON c.kanban_id = k.id AND c.deleted_at IS NULL
+JOIN kanban_items AS i ON i.kanban_category_id = c.id
+WHERE r.enabled
+  AND r.event = 'item.overdue'
+  AND (r.category_id IS NULL OR r.category_id = c.id)
+  AND i.deleted_at IS NULL
+  AND i.completed_at IS NULL
+  AND i.due_date < now()
+  AND NOT EXISTS (
+      SELECT 1 FROM kanban_rule_runs AS rr
+      WHERE rr.rule_id = r.id AND rr.item_id = i.id AND rr.due_date = i.due_date
+  )
+ORDER BY i.due_date, r.created_at
+LIMIT sqlc.arg('limit');
+
+-- name: ClaimKanbanRuleRun :execrows
+-- Marks an overdue rule as fired for a card, zero rows when it already was
+INSERT INTO kanban_rule_runs (rule_id, item_id, due_date)
+VALUES (sqlc.arg('rule_id'), sqlc.arg('item_id'), sqlc.arg('due_date'))
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,101 @@
+-- name: StartKanbanTimer :one
+INSERT INTO kanban_time_entries (id, project_id, item_id, user_id, started_at, note)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('project_id'),
+    sqlc.arg('item_id'),
+    sqlc.arg('user_id'),
+    now(),
+    sqlc.narg('note')
+)
+RETURNING *;
+
+-- name: StopKanbanTimer :one
+UPDATE kanban_time_entries
+SET ended_at = now(), updated_at = now()
+WHERE user_id = sqlc.arg('user_id') AND ended_at IS NULL
+RETURNING *;
+
+-- name: GetRunningKanbanTimer :one
+SELECT * FROM kanban_time_entries
+WHERE user_id = sqlc.arg('user_id') AND ended_at IS NULL;
+
+-- name: CreateKanbanTimeEntry :one
+INSERT INTO kanban_time_entries (id, project_id, item_id, user_id, started_at, ended_at, note)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('project_id'),
+    sqlc.arg('item_id'),
+    sqlc.arg('user_id'),
+    sqlc.arg('started_at'),
+    sqlc.arg('ended_at'),
+    sqlc.narg('note')
+)
+RETURNING *;
+
+-- name: GetKanbanTimeEntry :one
+SELECT * FROM kanban_time_entries
+WHERE id = sqlc.arg('id') AND item_id = sqlc.arg('item_id');
+
+-- name: DeleteKanbanTimeEntry :execrows
+DELETE FROM kanban_time_entries
+WHERE id = sqlc.arg('id');
+
+-- name: ListKanbanTimeEntries :many
+-- Entries of a project started within the range, running timers count up to now
+SELECT
+    t.id,
+    t.item_id,
+    t.user_id,
+    t.started_at,
+    t.ended_at,
+    t.note,
+    EXTRACT(EPOCH FROM COALESCE(t.ended_at, now()) - t.started_at)::bigint AS seconds,
+    u.username,
+    i.title AS item_title,
+    k.id AS kanban_id,
+    k.name AS kanban_name
+FROM kanban_time_entries AS t
+JOIN users AS u ON u.id = t.user_id
+JOIN kanban_items AS i ON i.id = t.item_id
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+JOIN kanbans AS k ON k.id = c.kanban_id
+WHERE t.project_id = sqlc.arg('project_id')
+  AND (sqlc.narg('item_id')::text IS NULL OR t.item_id = sqlc.narg('item_id'))
+  AND (sqlc.narg('user_id')::text IS NULL OR t.user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('from')::timestamptz IS NULL OR t.started_at >= sqlc.narg('from'))
+  AND (sqlc.narg('to')::timestamptz IS NULL OR t.started_at < sqlc.narg('to'))
+ORDER BY t.started_at, t.id;
+
+-- name: KanbanTimeTotalsByUser :many
+SELECT
+    t.user_id,
+    u.username,
+    SUM(EXTRACT(EPOCH FROM COALESCE(t.ended_at, now()) - t.started_at))::bigint AS seconds
+FROM kanban_time_entries AS t
+JOIN users AS u ON u.id = t.user_id
+WHERE t.project_id = sqlc.arg('project_id')
+  AND (sqlc.narg('item_id')::text IS NULL OR t.item_id = sqlc.narg('item_id'))
+  AND (sqlc.narg('user_id')::text IS NULL OR t.user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('from')::timestamptz IS NULL OR t.started_at >= sqlc.narg('from'))
+  AND (sqlc.narg('to')::timestamptz IS NULL OR t.started_at < sqlc.narg('to'))
+GROUP BY t.user_id, u.username
+ORDER BY seconds DESC, u.username;
+
+-- name: KanbanTimeTotalsByItem :many
+SELECT
+    t.item_id,
+    i.title,
+    c.kanban_id,
+    i.estimated_time,
+    SUM(EXTRACT(EPOCH FROM COALESCE(t.ended_at, now()) - t.started_at))::bigint AS seconds
+FROM kanban_time_entries AS t
+JOIN kanban_items AS i ON i.id = t.item_id
+JOIN kanban_categories AS c ON c.id = i.kanban_category_id
+WHERE t.project_id = sqlc.arg('project_id')
+  AND (sqlc.narg('item_id')::text IS NULL OR t.item_id = sqlc.narg('item_id'))
+  AND (sqlc.narg('user_id')::text IS NULL OR t.user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('from')::timestamptz IS NULL OR t.started_at >= sqlc.narg('from'))
+  AND (sqlc.narg('to')::timestamptz IS NULL OR t.started_at < sqlc.narg('to'))
+GROUP BY t.item_id, i.title, c.kanban_id, i.estimated_time
+ORDER BY seconds DESC, i.title;
@@ -0,0 +1,35 @@
+-- name: CreateNotification :exec
+INSERT INTO notifications (id, user_id, organisation_id, actor_id, type, data)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('user_id'),
+    sqlc.arg('organisation_id'),
+    sqlc.narg('actor_id'),
+    sqlc.arg('type'),
+    sqlc.arg('data')
+);
+
+-- name: ListNotifications :many
+SELECT
+    n.*,
+    u.username AS actor_username
+FROM notifications AS n
+LEFT JOIN users AS u ON u.id = n.actor_id
+WHERE n.user_id = sqlc.arg('user_id')
+  AND (NOT sqlc.arg('unread_only')::boolean OR n.read_at IS NULL)
+ORDER BY n.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = sqlc.arg('user_id') AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id');
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = sqlc.arg('user_id') AND read_at IS NULL;
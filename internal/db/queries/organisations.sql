@@ -59,6 +59,9 @@ SET
     is_active   = COALESCE(sqlc.narg('is_active'), is_active),
     archived_at = COALESCE(sqlc.narg('archived_at'), archived_at),
     default_role_id = COALESCE(sqlc.narg('default_role_id'), default_role_id),
+    kanban_archive_retention_days = CASE WHEN sqlc.arg('set_kanban_archive_retention_days')::boolean THEN sqlc.narg('kanban_archive_retention_days')::integer ELSE kanban_archive_retention_days END,
+    attachment_max_size = CASE WHEN sqlc.arg('set_attachment_max_size')::boolean THEN sqlc.narg('attachment_max_size')::bigint ELSE attachment_max_size END,
+    attachment_types = CASE WHEN sqlc.arg('set_attachment_types')::boolean THEN sqlc.narg('attachment_types')::text[] ELSE attachment_types END,
     updated_at  = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
@@ -0,0 +1,36 @@
+-- name: AddProjectMember :one
+INSERT INTO project_members (project_id, user_id, role)
+VALUES (sqlc.arg('project_id'), sqlc.arg('user_id'), sqlc.arg('role'))
+RETURNING *;
+
+-- name: GetProjectMember :one
+SELECT * FROM project_members
+WHERE project_id = sqlc.arg('project_id') AND user_id = sqlc.arg('user_id');
+
+-- name: ListProjectMembers :many
+SELECT
+    pm.*,
+    u.username,
+    u.avatar
+FROM project_members AS pm
+JOIN users AS u ON u.id = pm.user_id
+WHERE pm.project_id = sqlc.arg('project_id')
+ORDER BY u.username;
+
+-- name: UpdateProjectMemberRole :one
+UPDATE project_members
+SET role = sqlc.arg('role')
+WHERE project_id = sqlc.arg('project_id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: RemoveProjectMember :execrows
+DELETE FROM project_members
+WHERE project_id = sqlc.arg('project_id') AND user_id = sqlc.arg('user_id');
+
+-- name: LockProjectMembers :exec
+-- Serialises role changes and removals in a project so the last admin stays
+SELECT id FROM projects WHERE id = sqlc.arg('project_id') FOR UPDATE;
+
+-- name: CountProjectAdmins :one
+SELECT COUNT(*) FROM project_members
+WHERE project_id = sqlc.arg('project_id') AND role = 'Admin';
@@ -0,0 +1,35 @@
+-- name: CreateProjectTemplate :one
+INSERT INTO project_templates (
+    id,
+    organisation_id,
+    created_by,
+    name,
+    description,
+    include_items,
+    include_whiteboards,
+    content
+) VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('organisation_id'),
+    sqlc.arg('created_by'),
+    sqlc.arg('name'),
+    sqlc.narg('description'),
+    sqlc.arg('include_items'),
+    sqlc.arg('include_whiteboards'),
+    sqlc.arg('content')
+)
+RETURNING *;
+
+-- name: GetProjectTemplate :one
+SELECT * FROM project_templates
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
+
+-- name: ListProjectTemplates :many
+SELECT * FROM project_templates
+WHERE organisation_id = sqlc.arg('organisation_id')
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: DeleteProjectTemplate :execrows
+DELETE FROM project_templates
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
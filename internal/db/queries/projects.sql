@@ -0,0 +1,39 @@
+-- name: CreateProject :one
+INSERT INTO projects (id, organisation_id, name, status)
+VALUES (sqlc.arg('id'), sqlc.arg('organisation_id'), sqlc.arg('name'), 'Active')
+RETURNING *;
+
+-- name: GetProject :one
+SELECT * FROM projects
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
+
+-- name: GetProjectByID :one
+SELECT * FROM projects WHERE id = sqlc.arg('id');
+
+-- name: ListProjects :many
+SELECT * FROM projects
+WHERE organisation_id = sqlc.arg('organisation_id')
+  AND (sqlc.arg('status')::text = '' OR status::text = sqlc.arg('status')::text)
+  AND (sqlc.arg('search')::text = '' OR name ILIKE '%' || sqlc.arg('search')::text || '%')
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateProject :one
+UPDATE projects
+SET
+    name       = COALESCE(sqlc.narg('name'), name),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')
+RETURNING *;
+
+-- name: UpdateProjectStatus :one
+UPDATE projects
+SET
+    status     = sqlc.arg('status'),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id')
+RETURNING *;
+
+-- name: DeleteProject :execrows
+DELETE FROM projects
+WHERE id = sqlc.arg('id') AND organisation_id = sqlc.arg('organisation_id');
@@ -23,4 +23,9 @@ WHERE email = $1;
 -- name: GetByID :one
 SELECT *
 FROM users
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetByUsername :one
+SELECT *
+FROM users
+WHERE username = $1;
@@ -0,0 +1,36 @@
+-- name: CreateWhiteboard :one
+INSERT INTO whiteboard_rooms (id, project_id, name)
+VALUES (sqlc.arg('id'), sqlc.arg('project_id'), sqlc.arg('name'))
+RETURNING *;
+
+-- name: ListWhiteboardsByProject :many
+SELECT * FROM whiteboard_rooms
+WHERE project_id = sqlc.arg('project_id')
+ORDER BY created_at;
+
+-- name: CreateLineData :one
+INSERT INTO line_data (id, whiteboard_id, stroke, stroke_width, tool, text_content)
+VALUES (
+    sqlc.arg('id'),
+    sqlc.arg('whiteboard_id'),
+    sqlc.narg('stroke'),
+    sqlc.narg('stroke_width'),
+    sqlc.narg('tool'),
+    sqlc.narg('text_content')
+)
+RETURNING *;
+
+-- name: ListWhiteboardLines :many
+SELECT * FROM line_data
+WHERE whiteboard_id = sqlc.arg('whiteboard_id')
+ORDER BY created_at;
+
+-- name: CreateLinePoints :copyfrom
+INSERT INTO line_points (line_data_id, point)
+VALUES (sqlc.arg('line_data_id'), sqlc.arg('point'));
+
+-- name: ListWhiteboardPoints :many
+SELECT p.* FROM line_points AS p
+JOIN line_data AS l ON l.id = p.line_data_id
+WHERE l.whiteboard_id = sqlc.arg('whiteboard_id')
+ORDER BY p.id;
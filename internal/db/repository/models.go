@@ -9,11 +9,41 @@ import (
 )
 
 type Announcement struct {
-	ID              string             `json:"id"`
-	CreatedAt       pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
-	AnnouncmentText pgtype.Text        `json:"announcment_text"`
-	OrganisationID  pgtype.Text        `json:"organisation_id"`
+	ID             string             `json:"id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Body           string             `json:"body"`
+	OrganisationID pgtype.Text        `json:"organisation_id"`
+	AuthorID       pgtype.Text        `json:"author_id"`
+	Pinned         bool               `json:"pinned"`
+	PublishAt      pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+type AnnouncementRead struct {
+	AnnouncementID string             `json:"announcement_id"`
+	UserID         string             `json:"user_id"`
+	ReadAt         pgtype.Timestamptz `json:"read_at"`
+}
+
+type Attachment struct {
+	ID             string             `json:"id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	OrganisationID string             `json:"organisation_id"`
+	ItemID         pgtype.Text        `json:"item_id"`
+	ChatMessageID  pgtype.Text        `json:"chat_message_id"`
+	UploadedBy     pgtype.Text        `json:"uploaded_by"`
+	FileName       string             `json:"file_name"`
+	ContentType    string             `json:"content_type"`
+	Size           int64              `json:"size"`
+	Checksum       string             `json:"checksum"`
+	StorageKey     string             `json:"storage_key"`
+	ScanStatus     string             `json:"scan_status"`
+}
+
+type AttachmentDeletion struct {
+	StorageKey string             `json:"storage_key"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type ChatMember struct {
@@ -43,12 +73,28 @@ type ChatRoom struct {
 }
 
 type Kanban struct {
-	ID        string             `json:"id"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
-	ProjectID string             `json:"project_id"`
-	Name      string             `json:"name"`
-	Status    interface{}        `json:"status"`
+	ID                string             `json:"id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	ProjectID         string             `json:"project_id"`
+	Name              string             `json:"name"`
+	Status            string             `json:"status"`
+	BlockedMovePolicy string             `json:"blocked_move_policy"`
+	WipPolicy         string             `json:"wip_policy"`
+	Version           int32              `json:"version"`
+}
+
+type KanbanActivity struct {
+	ID         string             `json:"id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	KanbanID   string             `json:"kanban_id"`
+	ActorID    pgtype.Text        `json:"actor_id"`
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	ItemID     pgtype.Text        `json:"item_id"`
+	Action     string             `json:"action"`
+	Changes    []byte             `json:"changes"`
+	RuleID     pgtype.Text        `json:"rule_id"`
 }
 
 type KanbanCategory struct {
@@ -58,6 +104,51 @@ type KanbanCategory struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 	KanbanID  string             `json:"kanban_id"`
 	Name      pgtype.Text        `json:"name"`
+	Rank      string             `json:"rank"`
+	Done      bool               `json:"done"`
+	WipLimit  pgtype.Int4        `json:"wip_limit"`
+	Version   int32              `json:"version"`
+}
+
+type KanbanChecklistEntry struct {
+	ID             string             `json:"id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ItemID         string             `json:"item_id"`
+	Title          string             `json:"title"`
+	Done           bool               `json:"done"`
+	DoneAt         pgtype.Timestamptz `json:"done_at"`
+	AssigneeID     pgtype.Text        `json:"assignee_id"`
+	DueDate        pgtype.Timestamptz `json:"due_date"`
+	Rank           string             `json:"rank"`
+	PromotedItemID pgtype.Text        `json:"promoted_item_id"`
+}
+
+type KanbanDueDigest struct {
+	UserID    string             `json:"user_id"`
+	Day       pgtype.Date        `json:"day"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanDueReminder struct {
+	ItemID        string             `json:"item_id"`
+	DueDate       pgtype.Timestamptz `json:"due_date"`
+	OffsetSeconds int32              `json:"offset_seconds"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanImport struct {
+	ID            string             `json:"id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ProjectID     string             `json:"project_id"`
+	KanbanID      pgtype.Text        `json:"kanban_id"`
+	Source        string             `json:"source"`
+	FileName      string             `json:"file_name"`
+	DryRun        bool               `json:"dry_run"`
+	RowCount      int32              `json:"row_count"`
+	ImportedCount int32              `json:"imported_count"`
+	Issues        []byte             `json:"issues"`
+	CreatedBy     pgtype.Text        `json:"created_by"`
 }
 
 type KanbanItem struct {
@@ -66,11 +157,165 @@ type KanbanItem struct {
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 	KanbanCategoryID string             `json:"kanban_category_id"`
 	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
-	Priority         interface{}        `json:"priority"`
+	Priority         string             `json:"priority"`
 	DueDate          pgtype.Timestamptz `json:"due_date"`
 	EstimatedTime    pgtype.Int4        `json:"estimated_time"`
 	Title            string             `json:"title"`
 	Description      pgtype.Text        `json:"description"`
+	Rank             string             `json:"rank"`
+	ParentItemID     pgtype.Text        `json:"parent_item_id"`
+	CompletedAt      pgtype.Timestamptz `json:"completed_at"`
+	Version          int32              `json:"version"`
+	LaneID           pgtype.Text        `json:"lane_id"`
+}
+
+type KanbanItemAssignee struct {
+	ItemID    string             `json:"item_id"`
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanItemComment struct {
+	ID        string             `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	EditedAt  pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	ItemID    string             `json:"item_id"`
+	ParentID  pgtype.Text        `json:"parent_id"`
+	AuthorID  pgtype.Text        `json:"author_id"`
+	Body      string             `json:"body"`
+}
+
+type KanbanItemCommentMention struct {
+	CommentID string `json:"comment_id"`
+	UserID    string `json:"user_id"`
+}
+
+type KanbanItemCommentRevision struct {
+	ID        string             `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	CommentID string             `json:"comment_id"`
+	EditorID  pgtype.Text        `json:"editor_id"`
+	Action    string             `json:"action"`
+	Body      string             `json:"body"`
+}
+
+type KanbanItemInvitation struct {
+	ItemID    string             `json:"item_id"`
+	Email     string             `json:"email"`
+	ProjectID string             `json:"project_id"`
+	InvitedBy pgtype.Text        `json:"invited_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanItemLabel struct {
+	ItemID    string             `json:"item_id"`
+	LabelID   string             `json:"label_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanItemLink struct {
+	ID           string             `json:"id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ProjectID    string             `json:"project_id"`
+	ItemID       string             `json:"item_id"`
+	LinkedItemID string             `json:"linked_item_id"`
+	Type         string             `json:"type"`
+	CreatedBy    pgtype.Text        `json:"created_by"`
+}
+
+type KanbanItemTransition struct {
+	ID             string             `json:"id"`
+	MovedAt        pgtype.Timestamptz `json:"moved_at"`
+	KanbanID       string             `json:"kanban_id"`
+	ItemID         string             `json:"item_id"`
+	FromCategoryID pgtype.Text        `json:"from_category_id"`
+	ToCategoryID   string             `json:"to_category_id"`
+}
+
+type KanbanItemWatcher struct {
+	ItemID    string             `json:"item_id"`
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanLabel struct {
+	ID             string             `json:"id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	OrganisationID string             `json:"organisation_id"`
+	KanbanID       pgtype.Text        `json:"kanban_id"`
+	Name           string             `json:"name"`
+	Color          string             `json:"color"`
+}
+
+type KanbanLane struct {
+	ID        string             `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	KanbanID  string             `json:"kanban_id"`
+	Name      string             `json:"name"`
+	Color     pgtype.Text        `json:"color"`
+	Rank      string             `json:"rank"`
+	Version   int32              `json:"version"`
+}
+
+type KanbanRecurrence struct {
+	ID            string             `json:"id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	KanbanID      string             `json:"kanban_id"`
+	CategoryID    string             `json:"category_id"`
+	Title         string             `json:"title"`
+	Description   pgtype.Text        `json:"description"`
+	Priority      string             `json:"priority"`
+	EstimatedTime pgtype.Int4        `json:"estimated_time"`
+	Rrule         string             `json:"rrule"`
+	StartsAt      pgtype.Timestamptz `json:"starts_at"`
+	Until         pgtype.Timestamptz `json:"until"`
+	NextRunAt     pgtype.Timestamptz `json:"next_run_at"`
+	Enabled       bool               `json:"enabled"`
+	CreatedBy     pgtype.Text        `json:"created_by"`
+}
+
+type KanbanRecurrenceRun struct {
+	RecurrenceID string             `json:"recurrence_id"`
+	ScheduledAt  pgtype.Timestamptz `json:"scheduled_at"`
+	ItemID       pgtype.Text        `json:"item_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanRule struct {
+	ID         string             `json:"id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	KanbanID   string             `json:"kanban_id"`
+	Name       string             `json:"name"`
+	Enabled    bool               `json:"enabled"`
+	Event      string             `json:"event"`
+	CategoryID pgtype.Text        `json:"category_id"`
+	Actions    []byte             `json:"actions"`
+	CreatedBy  pgtype.Text        `json:"created_by"`
+}
+
+type KanbanRuleRun struct {
+	RuleID    string             `json:"rule_id"`
+	ItemID    string             `json:"item_id"`
+	DueDate   pgtype.Timestamptz `json:"due_date"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KanbanTimeEntry struct {
+	ID        string             `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ProjectID string             `json:"project_id"`
+	ItemID    string             `json:"item_id"`
+	UserID    string             `json:"user_id"`
+	StartedAt pgtype.Timestamptz `json:"started_at"`
+	EndedAt   pgtype.Timestamptz `json:"ended_at"`
+	Note      pgtype.Text        `json:"note"`
 }
 
 type LineDatum struct {
@@ -90,22 +335,36 @@ type LinePoint struct {
 	LineDataID string  `json:"line_data_id"`
 }
 
+type Notification struct {
+	ID             string             `json:"id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UserID         string             `json:"user_id"`
+	OrganisationID string             `json:"organisation_id"`
+	ActorID        pgtype.Text        `json:"actor_id"`
+	Type           string             `json:"type"`
+	Data           []byte             `json:"data"`
+	ReadAt         pgtype.Timestamptz `json:"read_at"`
+}
+
 type Organisation struct {
-	ID            string             `json:"id"`
-	Name          string             `json:"name"`
-	Slug          string             `json:"slug"`
-	Description   pgtype.Text        `json:"description"`
-	OwnerID       string             `json:"owner_id"`
-	Website       pgtype.Text        `json:"website"`
-	LogoUrl       pgtype.Text        `json:"logo_url"`
-	Location      pgtype.Text        `json:"location"`
-	Timezone      pgtype.Text        `json:"timezone"`
-	IsActive      pgtype.Bool        `json:"is_active"`
-	ArchivedAt    pgtype.Timestamptz `json:"archived_at"`
-	Settings      []byte             `json:"settings"`
-	CreatedAt     pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
-	DefaultRoleID pgtype.Text        `json:"default_role_id"`
+	ID                         string             `json:"id"`
+	Name                       string             `json:"name"`
+	Slug                       string             `json:"slug"`
+	Description                pgtype.Text        `json:"description"`
+	OwnerID                    string             `json:"owner_id"`
+	Website                    pgtype.Text        `json:"website"`
+	LogoUrl                    pgtype.Text        `json:"logo_url"`
+	Location                   pgtype.Text        `json:"location"`
+	Timezone                   pgtype.Text        `json:"timezone"`
+	IsActive                   pgtype.Bool        `json:"is_active"`
+	ArchivedAt                 pgtype.Timestamptz `json:"archived_at"`
+	Settings                   []byte             `json:"settings"`
+	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
+	DefaultRoleID              pgtype.Text        `json:"default_role_id"`
+	KanbanArchiveRetentionDays pgtype.Int4        `json:"kanban_archive_retention_days"`
+	AttachmentMaxSize          pgtype.Int8        `json:"attachment_max_size"`
+	AttachmentTypes            []string           `json:"attachment_types"`
 }
 
 type OrganisationMember struct {
@@ -121,14 +380,27 @@ type Project struct {
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 	Name           pgtype.Text        `json:"name"`
 	OrganisationID string             `json:"organisation_id"`
-	Status         interface{}        `json:"status"`
+	Status         string             `json:"status"`
 }
 
 type ProjectMember struct {
-	ID        int32       `json:"id"`
-	ProjectID string      `json:"project_id"`
-	UserID    string      `json:"user_id"`
-	Role      interface{} `json:"role"`
+	ID        int32  `json:"id"`
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+}
+
+type ProjectTemplate struct {
+	ID                 string             `json:"id"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	OrganisationID     string             `json:"organisation_id"`
+	CreatedBy          pgtype.Text        `json:"created_by"`
+	Name               string             `json:"name"`
+	Description        pgtype.Text        `json:"description"`
+	IncludeItems       bool               `json:"include_items"`
+	IncludeWhiteboards bool               `json:"include_whiteboards"`
+	Content            []byte             `json:"content"`
 }
 
 type Role struct {
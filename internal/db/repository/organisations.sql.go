@@ -25,7 +25,7 @@ func (q *Queries) CountOrganisations(ctx context.Context) (int64, error) {
 const createOrganisation = `-- name: CreateOrganisation :one
 INSERT INTO organisations (id, name, slug, owner_id)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id
+RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types
 `
 
 type CreateOrganisationParams struct {
@@ -59,6 +59,9 @@ func (q *Queries) CreateOrganisation(ctx context.Context, arg CreateOrganisation
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DefaultRoleID,
+		&i.KanbanArchiveRetentionDays,
+		&i.AttachmentMaxSize,
+		&i.AttachmentTypes,
 	)
 	return i, err
 }
@@ -73,7 +76,7 @@ func (q *Queries) DeleteOrganisation(ctx context.Context, id string) error {
 }
 
 const getOrganisationByID = `-- name: GetOrganisationByID :one
-SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id FROM organisations WHERE id = $1
+SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types FROM organisations WHERE id = $1
 `
 
 func (q *Queries) GetOrganisationByID(ctx context.Context, id string) (Organisation, error) {
@@ -95,12 +98,15 @@ func (q *Queries) GetOrganisationByID(ctx context.Context, id string) (Organisat
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DefaultRoleID,
+		&i.KanbanArchiveRetentionDays,
+		&i.AttachmentMaxSize,
+		&i.AttachmentTypes,
 	)
 	return i, err
 }
 
 const getOrganisationBySlug = `-- name: GetOrganisationBySlug :one
-SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id FROM organisations WHERE slug = $1
+SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types FROM organisations WHERE slug = $1
 `
 
 func (q *Queries) GetOrganisationBySlug(ctx context.Context, slug string) (Organisation, error) {
@@ -122,12 +128,15 @@ func (q *Queries) GetOrganisationBySlug(ctx context.Context, slug string) (Organ
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DefaultRoleID,
+		&i.KanbanArchiveRetentionDays,
+		&i.AttachmentMaxSize,
+		&i.AttachmentTypes,
 	)
 	return i, err
 }
 
 const getOrganisationsByOwner = `-- name: GetOrganisationsByOwner :many
-SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id FROM organisations
+SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types FROM organisations
 WHERE owner_id = $1
 ORDER BY created_at DESC
 LIMIT $3
@@ -165,6 +174,9 @@ func (q *Queries) GetOrganisationsByOwner(ctx context.Context, arg GetOrganisati
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DefaultRoleID,
+			&i.KanbanArchiveRetentionDays,
+			&i.AttachmentMaxSize,
+			&i.AttachmentTypes,
 		); err != nil {
 			return nil, err
 		}
@@ -177,7 +189,7 @@ func (q *Queries) GetOrganisationsByOwner(ctx context.Context, arg GetOrganisati
 }
 
 const getUserOrganisations = `-- name: GetUserOrganisations :many
-SELECT DISTINCT o.id, o.name, o.slug, o.description, o.owner_id, o.website, o.logo_url, o.location, o.timezone, o.is_active, o.archived_at, o.settings, o.created_at, o.updated_at, o.default_role_id
+SELECT DISTINCT o.id, o.name, o.slug, o.description, o.owner_id, o.website, o.logo_url, o.location, o.timezone, o.is_active, o.archived_at, o.settings, o.created_at, o.updated_at, o.default_role_id, o.kanban_archive_retention_days, o.attachment_max_size, o.attachment_types
 FROM organisations o
 LEFT JOIN organisation_members m
   ON m.organisation_id = o.id
@@ -231,6 +243,9 @@ func (q *Queries) GetUserOrganisations(ctx context.Context, arg GetUserOrganisat
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DefaultRoleID,
+			&i.KanbanArchiveRetentionDays,
+			&i.AttachmentMaxSize,
+			&i.AttachmentTypes,
 		); err != nil {
 			return nil, err
 		}
@@ -243,7 +258,7 @@ func (q *Queries) GetUserOrganisations(ctx context.Context, arg GetUserOrganisat
 }
 
 const searchOrganisations = `-- name: SearchOrganisations :many
-SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id FROM organisations
+SELECT id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types FROM organisations
 WHERE (
     $1::text = '' 
     OR name ILIKE '%' || $1::text || '%' 
@@ -284,6 +299,9 @@ func (q *Queries) SearchOrganisations(ctx context.Context, arg SearchOrganisatio
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DefaultRoleID,
+			&i.KanbanArchiveRetentionDays,
+			&i.AttachmentMaxSize,
+			&i.AttachmentTypes,
 		); err != nil {
 			return nil, err
 		}
@@ -308,23 +326,32 @@ SET
     is_active   = COALESCE($8, is_active),
     archived_at = COALESCE($9, archived_at),
     default_role_id = COALESCE($10, default_role_id),
+    kanban_archive_retention_days = CASE WHEN $11::boolean THEN $12::integer ELSE kanban_archive_retention_days END,
+    attachment_max_size = CASE WHEN $13::boolean THEN $14::bigint ELSE attachment_max_size END,
+    attachment_types = CASE WHEN $15::boolean THEN $16::text[] ELSE attachment_types END,
     updated_at  = NOW()
-WHERE id = $11
-RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id
+WHERE id = $17
+RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types
 `
 
 type UpdateOrganisationParams struct {
-	Name          pgtype.Text        `json:"name"`
-	Slug          pgtype.Text        `json:"slug"`
-	Description   pgtype.Text        `json:"description"`
-	Website       pgtype.Text        `json:"website"`
-	LogoUrl       pgtype.Text        `json:"logo_url"`
-	Location      pgtype.Text        `json:"location"`
-	Timezone      pgtype.Text        `json:"timezone"`
-	IsActive      pgtype.Bool        `json:"is_active"`
-	ArchivedAt    pgtype.Timestamptz `json:"archived_at"`
-	DefaultRoleID pgtype.Text        `json:"default_role_id"`
-	ID            string             `json:"id"`
+	Name                          pgtype.Text        `json:"name"`
+	Slug                          pgtype.Text        `json:"slug"`
+	Description                   pgtype.Text        `json:"description"`
+	Website                       pgtype.Text        `json:"website"`
+	LogoUrl                       pgtype.Text        `json:"logo_url"`
+	Location                      pgtype.Text        `json:"location"`
+	Timezone                      pgtype.Text        `json:"timezone"`
+	IsActive                      pgtype.Bool        `json:"is_active"`
+	ArchivedAt                    pgtype.Timestamptz `json:"archived_at"`
+	DefaultRoleID                 pgtype.Text        `json:"default_role_id"`
+	SetKanbanArchiveRetentionDays bool               `json:"set_kanban_archive_retention_days"`
+	KanbanArchiveRetentionDays    pgtype.Int4        `json:"kanban_archive_retention_days"`
+	SetAttachmentMaxSize          bool               `json:"set_attachment_max_size"`
+	AttachmentMaxSize             pgtype.Int8        `json:"attachment_max_size"`
+	SetAttachmentTypes            bool               `json:"set_attachment_types"`
+	AttachmentTypes               []string           `json:"attachment_types"`
+	ID                            string             `json:"id"`
 }
 
 func (q *Queries) UpdateOrganisation(ctx context.Context, arg UpdateOrganisationParams) (Organisation, error) {
@@ -339,6 +366,12 @@ func (q *Queries) UpdateOrganisation(ctx context.Context, arg UpdateOrganisation
 		arg.IsActive,
 		arg.ArchivedAt,
 		arg.DefaultRoleID,
+		arg.SetKanbanArchiveRetentionDays,
+		arg.KanbanArchiveRetentionDays,
+		arg.SetAttachmentMaxSize,
+		arg.AttachmentMaxSize,
+		arg.SetAttachmentTypes,
+		arg.AttachmentTypes,
 		arg.ID,
 	)
 	var i Organisation
@@ -358,6 +391,9 @@ func (q *Queries) UpdateOrganisation(ctx context.Context, arg UpdateOrganisation
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DefaultRoleID,
+		&i.KanbanArchiveRetentionDays,
+		&i.AttachmentMaxSize,
+		&i.AttachmentTypes,
 	)
 	return i, err
 }
@@ -366,7 +402,7 @@ const updateOrganisationDefaultRole = `-- name: UpdateOrganisationDefaultRole :o
 UPDATE organisations
 SET default_role_id = $2
 WHERE id = $1
-RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id
+RETURNING id, name, slug, description, owner_id, website, logo_url, location, timezone, is_active, archived_at, settings, created_at, updated_at, default_role_id, kanban_archive_retention_days, attachment_max_size, attachment_types
 `
 
 type UpdateOrganisationDefaultRoleParams struct {
@@ -393,6 +429,9 @@ func (q *Queries) UpdateOrganisationDefaultRole(ctx context.Context, arg UpdateO
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DefaultRoleID,
+		&i.KanbanArchiveRetentionDays,
+		&i.AttachmentMaxSize,
+		&i.AttachmentTypes,
 	)
 	return i, err
 }
@@ -11,33 +11,303 @@ import (
 )
 
 type Querier interface {
+	// Turns the invitations of a user who joined a project into assignees
+	AcceptKanbanItemInvitations(ctx context.Context, arg AcceptKanbanItemInvitationsParams) (int64, error)
+	// Only members of the organisation of the room are added, and only once
+	AddChatMember(ctx context.Context, arg AddChatMemberParams) (int64, error)
+	AddKanbanItemAssignee(ctx context.Context, arg AddKanbanItemAssigneeParams) (int64, error)
+	// Returns only the users that were not mentioned before
+	AddKanbanItemCommentMentions(ctx context.Context, arg AddKanbanItemCommentMentionsParams) ([]string, error)
+	AddKanbanItemLabel(ctx context.Context, arg AddKanbanItemLabelParams) (int64, error)
+	AddKanbanItemWatcher(ctx context.Context, arg AddKanbanItemWatcherParams) (int64, error)
+	AddProjectMember(ctx context.Context, arg AddProjectMemberParams) (ProjectMember, error)
+	// Marks the digest of a user as sent for the day, zero rows when it already was
+	ClaimKanbanDueDigest(ctx context.Context, arg ClaimKanbanDueDigestParams) (int64, error)
+	// Marks a reminder as sent, zero rows when it already was
+	ClaimKanbanDueReminder(ctx context.Context, arg ClaimKanbanDueReminderParams) (int64, error)
+	// Zero rows when the occurrence was already generated
+	ClaimKanbanRecurrenceRun(ctx context.Context, arg ClaimKanbanRecurrenceRunParams) (int64, error)
+	// Marks an overdue rule as fired for a card, zero rows when it already was
+	ClaimKanbanRuleRun(ctx context.Context, arg ClaimKanbanRuleRunParams) (int64, error)
+	CountDeletedKanbanCategories(ctx context.Context, kanbanID string) (int64, error)
+	CountDeletedKanbanItems(ctx context.Context, kanbanID string) (int64, error)
+	// Active cards in a category, other than the one being moved
+	CountKanbanCategoryItems(ctx context.Context, arg CountKanbanCategoryItemsParams) (int64, error)
 	CountOrganisations(ctx context.Context) (int64, error)
+	CountProjectAdmins(ctx context.Context, projectID string) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int64, error)
+	CreateAnnouncement(ctx context.Context, arg CreateAnnouncementParams) (Announcement, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
+	CreateChatMessage(ctx context.Context, arg CreateChatMessageParams) (ChatMessage, error)
+	CreateChatRoom(ctx context.Context, arg CreateChatRoomParams) (ChatRoom, error)
+	// Policies left out take the column defaults
+	CreateKanban(ctx context.Context, arg CreateKanbanParams) (Kanban, error)
+	CreateKanbanActivity(ctx context.Context, arg CreateKanbanActivityParams) (KanbanActivity, error)
+	CreateKanbanCategory(ctx context.Context, arg CreateKanbanCategoryParams) (KanbanCategory, error)
+	CreateKanbanChecklistEntry(ctx context.Context, arg CreateKanbanChecklistEntryParams) (KanbanChecklistEntry, error)
+	CreateKanbanImport(ctx context.Context, arg CreateKanbanImportParams) (KanbanImport, error)
+	CreateKanbanItem(ctx context.Context, arg CreateKanbanItemParams) (KanbanItem, error)
+	CreateKanbanItemComment(ctx context.Context, arg CreateKanbanItemCommentParams) (KanbanItemComment, error)
+	CreateKanbanItemCommentRevision(ctx context.Context, arg CreateKanbanItemCommentRevisionParams) error
+	CreateKanbanItemInvitation(ctx context.Context, arg CreateKanbanItemInvitationParams) (int64, error)
+	CreateKanbanItemLink(ctx context.Context, arg CreateKanbanItemLinkParams) (KanbanItemLink, error)
+	CreateKanbanItemTransition(ctx context.Context, arg CreateKanbanItemTransitionParams) error
+	CreateKanbanLabel(ctx context.Context, arg CreateKanbanLabelParams) (KanbanLabel, error)
+	CreateKanbanLane(ctx context.Context, arg CreateKanbanLaneParams) (KanbanLane, error)
+	CreateKanbanRecurrence(ctx context.Context, arg CreateKanbanRecurrenceParams) (KanbanRecurrence, error)
+	CreateKanbanRule(ctx context.Context, arg CreateKanbanRuleParams) (KanbanRule, error)
+	CreateKanbanTimeEntry(ctx context.Context, arg CreateKanbanTimeEntryParams) (KanbanTimeEntry, error)
+	CreateLineData(ctx context.Context, arg CreateLineDataParams) (LineDatum, error)
+	CreateLinePoints(ctx context.Context, arg []CreateLinePointsParams) (int64, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) error
 	CreateOrganisation(ctx context.Context, arg CreateOrganisationParams) (Organisation, error)
 	CreateOrganisationMember(ctx context.Context, arg CreateOrganisationMemberParams) (OrganisationMember, error)
+	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectTemplate(ctx context.Context, arg CreateProjectTemplateParams) (ProjectTemplate, error)
 	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
 	CreateRolePermission(ctx context.Context, arg CreateRolePermissionParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWhiteboard(ctx context.Context, arg CreateWhiteboardParams) (WhiteboardRoom, error)
+	DeleteAnnouncement(ctx context.Context, arg DeleteAnnouncementParams) (int64, error)
+	// The stored file is queued for removal by a trigger
+	DeleteAttachment(ctx context.Context, id string) (int64, error)
+	DeleteAttachmentDeletion(ctx context.Context, storageKey string) error
+	DeleteKanban(ctx context.Context, arg DeleteKanbanParams) (int64, error)
+	DeleteKanbanChecklistEntry(ctx context.Context, arg DeleteKanbanChecklistEntryParams) (int64, error)
+	DeleteKanbanItemLink(ctx context.Context, id string) (int64, error)
+	DeleteKanbanLabel(ctx context.Context, arg DeleteKanbanLabelParams) (int64, error)
+	// Cards of the lane are left without one
+	DeleteKanbanLane(ctx context.Context, arg DeleteKanbanLaneParams) (int64, error)
+	DeleteKanbanRecurrence(ctx context.Context, arg DeleteKanbanRecurrenceParams) (int64, error)
+	DeleteKanbanRule(ctx context.Context, arg DeleteKanbanRuleParams) (int64, error)
+	DeleteKanbanTimeEntry(ctx context.Context, id string) (int64, error)
 	DeleteOrganisation(ctx context.Context, id string) error
+	DeleteProject(ctx context.Context, arg DeleteProjectParams) (int64, error)
+	DeleteProjectTemplate(ctx context.Context, arg DeleteProjectTemplateParams) (int64, error)
+	GetAnnouncement(ctx context.Context, arg GetAnnouncementParams) (Announcement, error)
+	GetBoardLabel(ctx context.Context, arg GetBoardLabelParams) (KanbanLabel, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
 	GetByID(ctx context.Context, id string) (User, error)
+	GetByUsername(ctx context.Context, username string) (User, error)
+	GetChatMessage(ctx context.Context, arg GetChatMessageParams) (ChatMessage, error)
+	GetChatMessageAttachment(ctx context.Context, arg GetChatMessageAttachmentParams) (Attachment, error)
 	GetDefaultRole(ctx context.Context, id string) (Role, error)
 	GetGlobalRoles(ctx context.Context) ([]Role, error)
+	GetItemAttachment(ctx context.Context, arg GetItemAttachmentParams) (Attachment, error)
+	GetKanban(ctx context.Context, arg GetKanbanParams) (Kanban, error)
+	GetKanbanByID(ctx context.Context, id string) (Kanban, error)
+	GetKanbanCategory(ctx context.Context, arg GetKanbanCategoryParams) (KanbanCategory, error)
+	GetKanbanChecklistEntry(ctx context.Context, arg GetKanbanChecklistEntryParams) (KanbanChecklistEntry, error)
+	GetKanbanImport(ctx context.Context, arg GetKanbanImportParams) (KanbanImport, error)
+	GetKanbanItem(ctx context.Context, arg GetKanbanItemParams) (KanbanItem, error)
+	GetKanbanItemActivity(ctx context.Context, arg GetKanbanItemActivityParams) (KanbanActivity, error)
+	GetKanbanItemComment(ctx context.Context, arg GetKanbanItemCommentParams) (KanbanItemComment, error)
+	// A link with the card on either end
+	GetKanbanItemLink(ctx context.Context, arg GetKanbanItemLinkParams) (KanbanItemLink, error)
+	GetKanbanLabel(ctx context.Context, arg GetKanbanLabelParams) (KanbanLabel, error)
+	GetKanbanLane(ctx context.Context, arg GetKanbanLaneParams) (KanbanLane, error)
+	GetKanbanRecurrence(ctx context.Context, arg GetKanbanRecurrenceParams) (KanbanRecurrence, error)
+	GetKanbanRule(ctx context.Context, arg GetKanbanRuleParams) (KanbanRule, error)
+	GetKanbanTimeEntry(ctx context.Context, arg GetKanbanTimeEntryParams) (KanbanTimeEntry, error)
 	GetMemberByOrg(ctx context.Context, arg GetMemberByOrgParams) (OrganisationMember, error)
+	GetOrganisationAttachmentLimits(ctx context.Context, id string) (GetOrganisationAttachmentLimitsRow, error)
 	GetOrganisationByID(ctx context.Context, id string) (Organisation, error)
 	GetOrganisationBySlug(ctx context.Context, slug string) (Organisation, error)
 	GetOrganisationsByOwner(ctx context.Context, arg GetOrganisationsByOwnerParams) ([]Organisation, error)
 	GetPermissionsForRole(ctx context.Context, roleID string) ([]RolePermission, error)
+	GetProject(ctx context.Context, arg GetProjectParams) (Project, error)
+	GetProjectByID(ctx context.Context, id string) (Project, error)
+	// A card of any kanban in the project, used for links across kanbans
+	GetProjectKanbanItem(ctx context.Context, arg GetProjectKanbanItemParams) (GetProjectKanbanItemRow, error)
+	GetProjectMember(ctx context.Context, arg GetProjectMemberParams) (ProjectMember, error)
+	GetProjectTemplate(ctx context.Context, arg GetProjectTemplateParams) (ProjectTemplate, error)
 	GetRoleByID(ctx context.Context, arg GetRoleByIDParams) (Role, error)
 	GetRoleByName(ctx context.Context, arg GetRoleByNameParams) (Role, error)
 	GetRolesForOrg(ctx context.Context, organisationID pgtype.Text) ([]Role, error)
+	GetRunningKanbanTimer(ctx context.Context, userID string) (KanbanTimeEntry, error)
+	// A room is only visible to its members while they are in the organisation
+	GetUserChatRoom(ctx context.Context, arg GetUserChatRoomParams) (ChatRoom, error)
 	GetUserOrganisations(ctx context.Context, arg GetUserOrganisationsParams) ([]Organisation, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	HasPermission(ctx context.Context, arg HasPermissionParams) (bool, error)
 	IsOrganisationOwner(ctx context.Context, arg IsOrganisationOwnerParams) (bool, error)
+	// Cards per category at the end of every day of the range
+	KanbanCumulativeFlow(ctx context.Context, arg KanbanCumulativeFlowParams) ([]KanbanCumulativeFlowRow, error)
+	// Whether from blocks to, directly or through other cards
+	KanbanItemBlockPathExists(ctx context.Context, arg KanbanItemBlockPathExistsParams) (bool, error)
+	KanbanTimeTotalsByItem(ctx context.Context, arg KanbanTimeTotalsByItemParams) ([]KanbanTimeTotalsByItemRow, error)
+	KanbanTimeTotalsByUser(ctx context.Context, arg KanbanTimeTotalsByUserParams) ([]KanbanTimeTotalsByUserRow, error)
+	LastKanbanCategoryRank(ctx context.Context, kanbanID string) (string, error)
+	LastKanbanChecklistRank(ctx context.Context, itemID string) (string, error)
+	LastKanbanItemRank(ctx context.Context, kanbanCategoryID string) (string, error)
+	LastKanbanLaneRank(ctx context.Context, kanbanID string) (string, error)
+	ListAnnouncements(ctx context.Context, arg ListAnnouncementsParams) ([]ListAnnouncementsRow, error)
+	// Locked until the transaction ends so instances sweep different files
+	ListAttachmentDeletions(ctx context.Context, limit int32) ([]string, error)
+	// Cards of a kanban with at least one unfinished blocker
+	ListBlockedKanbanItems(ctx context.Context, kanbanID string) ([]string, error)
+	// Labels usable on a kanban, the organisation wide ones first
+	ListBoardLabels(ctx context.Context, kanbanID string) ([]KanbanLabel, error)
+	ListChatMessageAttachments(ctx context.Context, messageIds []string) ([]Attachment, error)
+	ListChatMessages(ctx context.Context, arg ListChatMessagesParams) ([]ChatMessage, error)
+	ListDeletedKanbanCategories(ctx context.Context, arg ListDeletedKanbanCategoriesParams) ([]KanbanCategory, error)
+	ListDeletedKanbanItems(ctx context.Context, arg ListDeletedKanbanItemsParams) ([]KanbanItem, error)
+	ListDueKanbanRecurrenceIDs(ctx context.Context, limit int32) ([]string, error)
+	// Open cards with a reminder offset (in seconds) reached and not sent yet.
+	// Only the offset closest to the due date is due, and cards more than
+	// grace_seconds past their due date are left to the overdue digest.
+	ListDueKanbanReminders(ctx context.Context, arg ListDueKanbanRemindersParams) ([]ListDueKanbanRemindersRow, error)
+	// Rules listening for an event, in the order they were created
+	ListEnabledKanbanRules(ctx context.Context, arg ListEnabledKanbanRulesParams) ([]KanbanRule, error)
+	// Archived categories kept longer than their organisation's retention, locked
+	// so several instances of the API can purge at the same time
+	ListExpiredKanbanCategories(ctx context.Context, limit int32) ([]KanbanCategory, error)
+	// Archived cards kept longer than their organisation's retention
+	ListExpiredKanbanItems(ctx context.Context, limit int32) ([]ListExpiredKanbanItemsRow, error)
+	ListItemAttachments(ctx context.Context, itemID pgtype.Text) ([]Attachment, error)
+	ListKanbanActivity(ctx context.Context, arg ListKanbanActivityParams) ([]ListKanbanActivityRow, error)
+	ListKanbanAssignees(ctx context.Context, kanbanID string) ([]ListKanbanAssigneesRow, error)
+	ListKanbanCategories(ctx context.Context, kanbanID string) ([]KanbanCategory, error)
+	ListKanbanCategoryRanks(ctx context.Context, kanbanID string) ([]ListKanbanCategoryRanksRow, error)
+	ListKanbanChecklistEntries(ctx context.Context, itemID string) ([]KanbanChecklistEntry, error)
+	// Done and total checklist entries per card of a kanban
+	ListKanbanChecklistProgress(ctx context.Context, kanbanID string) ([]ListKanbanChecklistProgressRow, error)
+	ListKanbanChecklistRanks(ctx context.Context, itemID string) ([]ListKanbanChecklistRanksRow, error)
+	// Cards of a kanban completed within the range. Work on a card starts when it
+	// first enters a category after the first column of the board.
+	ListKanbanCompletedItems(ctx context.Context, arg ListKanbanCompletedItemsParams) ([]ListKanbanCompletedItemsRow, error)
+	// Users assigned to or watching an overdue open card that have not had
+	// their digest for the day
+	ListKanbanDigestUsers(ctx context.Context, arg ListKanbanDigestUsersParams) ([]ListKanbanDigestUsersRow, error)
+	ListKanbanExportCategories(ctx context.Context, arg ListKanbanExportCategoriesParams) ([]KanbanCategory, error)
+	ListKanbanExportItemAssignees(ctx context.Context, itemIds []string) ([]ListKanbanExportItemAssigneesRow, error)
+	ListKanbanExportItemLabels(ctx context.Context, itemIds []string) ([]ListKanbanExportItemLabelsRow, error)
+	// One page of the items of a category in board order, pages continue after
+	// the rank and id of the last item of the previous one
+	ListKanbanExportItems(ctx context.Context, arg ListKanbanExportItemsParams) ([]KanbanItem, error)
+	ListKanbanItemActivity(ctx context.Context, arg ListKanbanItemActivityParams) ([]ListKanbanItemActivityRow, error)
+	// Changes to an item made after an entry, newest first, used to revert it
+	ListKanbanItemActivitySince(ctx context.Context, arg ListKanbanItemActivitySinceParams) ([]KanbanActivity, error)
+	ListKanbanItemAssignees(ctx context.Context, itemID string) ([]ListKanbanItemAssigneesRow, error)
+	// Unfinished cards blocking a card, archived cards and cards in a done column don't block
+	ListKanbanItemBlockers(ctx context.Context, itemID string) ([]ListKanbanItemBlockersRow, error)
+	ListKanbanItemCommentMentions(ctx context.Context, itemID string) ([]KanbanItemCommentMention, error)
+	ListKanbanItemCommentRevisions(ctx context.Context, commentID string) ([]ListKanbanItemCommentRevisionsRow, error)
+	// Deleted comments keep their place in the thread without a body
+	ListKanbanItemComments(ctx context.Context, itemID string) ([]ListKanbanItemCommentsRow, error)
+	// Cards blocked by a card with their current blocked state
+	ListKanbanItemDependents(ctx context.Context, itemID string) ([]ListKanbanItemDependentsRow, error)
+	ListKanbanItemLabels(ctx context.Context, itemID string) ([]KanbanLabel, error)
+	// Links of a card seen from that card, relation is blocks, blocked_by or relates
+	ListKanbanItemLinks(ctx context.Context, itemID string) ([]ListKanbanItemLinksRow, error)
+	ListKanbanItemRanks(ctx context.Context, kanbanCategoryID string) ([]ListKanbanItemRanksRow, error)
+	// Assignees and watchers of a card
+	ListKanbanItemReminderRecipients(ctx context.Context, itemID string) ([]ListKanbanItemReminderRecipientsRow, error)
+	ListKanbanItemWatchers(ctx context.Context, itemID string) ([]ListKanbanItemWatchersRow, error)
+	ListKanbanItems(ctx context.Context, kanbanID string) ([]KanbanItem, error)
+	ListKanbanLabelLinks(ctx context.Context, kanbanID string) ([]ListKanbanLabelLinksRow, error)
+	ListKanbanLaneItemIDs(ctx context.Context, laneID pgtype.Text) ([]string, error)
+	ListKanbanLaneRanks(ctx context.Context, kanbanID string) ([]ListKanbanLaneRanksRow, error)
+	ListKanbanLanes(ctx context.Context, kanbanID string) ([]KanbanLane, error)
+	ListKanbanRecurrences(ctx context.Context, kanbanID string) ([]KanbanRecurrence, error)
+	ListKanbanRules(ctx context.Context, kanbanID string) ([]KanbanRule, error)
+	// Entries of a project started within the range, running timers count up to now
+	ListKanbanTimeEntries(ctx context.Context, arg ListKanbanTimeEntriesParams) ([]ListKanbanTimeEntriesRow, error)
+	ListKanbansByProject(ctx context.Context, projectID string) ([]Kanban, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]ListNotificationsRow, error)
+	ListOrganisationKanbanIDs(ctx context.Context, organisationID string) ([]string, error)
+	ListOrganisationLabels(ctx context.Context, organisationID string) ([]KanbanLabel, error)
+	// Open cards past their due date that an enabled overdue rule has not fired for yet
+	ListOverdueKanbanRuleItems(ctx context.Context, limit int32) ([]ListOverdueKanbanRuleItemsRow, error)
+	// Active cards across all kanbans of a project, every filter is optional
+	ListProjectKanbanItems(ctx context.Context, arg ListProjectKanbanItemsParams) ([]ListProjectKanbanItemsRow, error)
+	ListProjectMembers(ctx context.Context, projectID string) ([]ListProjectMembersRow, error)
+	ListProjectTemplates(ctx context.Context, arg ListProjectTemplatesParams) ([]ProjectTemplate, error)
+	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	ListUnreadAnnouncements(ctx context.Context, arg ListUnreadAnnouncementsParams) ([]ListUnreadAnnouncementsRow, error)
+	ListUserChatRooms(ctx context.Context, arg ListUserChatRoomsParams) ([]ChatRoom, error)
+	// Overdue open cards a user is assigned to or watches, longest overdue first
+	ListUserOverdueKanbanItems(ctx context.Context, arg ListUserOverdueKanbanItemsParams) ([]ListUserOverdueKanbanItemsRow, error)
+	ListWhiteboardLines(ctx context.Context, whiteboardID string) ([]LineDatum, error)
+	ListWhiteboardPoints(ctx context.Context, whiteboardID string) ([]LinePoint, error)
+	ListWhiteboardsByProject(ctx context.Context, projectID pgtype.Text) ([]WhiteboardRoom, error)
+	// Takes a due recurrence for this transaction, other instances skip it
+	LockDueKanbanRecurrence(ctx context.Context, id string) (LockDueKanbanRecurrenceRow, error)
+	// Ordering. Neighbour lookups include archived rows so restored rows keep a unique rank.
+	LockKanban(ctx context.Context, id string) error
+	LockKanbanCategory(ctx context.Context, id string) error
+	LockKanbanItem(ctx context.Context, id string) error
+	// Serialises link changes within a project so two concurrent links can't form a cycle
+	LockKanbanItemLinks(ctx context.Context, projectID string) error
+	// Serialises role changes and removals in a project so the last admin stays
+	LockProjectMembers(ctx context.Context, projectID string) error
+	MarkAllAnnouncementsRead(ctx context.Context, arg MarkAllAnnouncementsReadParams) (int64, error)
+	MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error)
+	MarkAnnouncementRead(ctx context.Context, arg MarkAnnouncementReadParams) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	// Moving into a done column stamps the completion time, moving between done
+	// columns keeps it and moving out of them clears it. The lane is only
+	// changed when set_lane is true.
+	MoveKanbanItem(ctx context.Context, arg MoveKanbanItemParams) (KanbanItem, error)
+	NextKanbanCategoryRank(ctx context.Context, arg NextKanbanCategoryRankParams) (string, error)
+	NextKanbanChecklistRank(ctx context.Context, arg NextKanbanChecklistRankParams) (string, error)
+	NextKanbanItemRank(ctx context.Context, arg NextKanbanItemRankParams) (string, error)
+	NextKanbanLaneRank(ctx context.Context, arg NextKanbanLaneRankParams) (string, error)
 	OrganisationMemberExists(ctx context.Context, arg OrganisationMemberExistsParams) (bool, error)
+	PermaDeleteKanbanCategory(ctx context.Context, arg PermaDeleteKanbanCategoryParams) (int64, error)
+	PermaDeleteKanbanItem(ctx context.Context, id string) (int64, error)
+	PrevKanbanCategoryRank(ctx context.Context, arg PrevKanbanCategoryRankParams) (string, error)
+	PrevKanbanChecklistRank(ctx context.Context, arg PrevKanbanChecklistRankParams) (string, error)
+	PrevKanbanItemRank(ctx context.Context, arg PrevKanbanItemRankParams) (string, error)
+	PrevKanbanLaneRank(ctx context.Context, arg PrevKanbanLaneRankParams) (string, error)
+	RemoveKanbanItemAssignee(ctx context.Context, arg RemoveKanbanItemAssigneeParams) (int64, error)
+	// Drops mentions that are no longer in the comment body
+	RemoveKanbanItemCommentMentions(ctx context.Context, arg RemoveKanbanItemCommentMentionsParams) error
+	RemoveKanbanItemLabel(ctx context.Context, arg RemoveKanbanItemLabelParams) (int64, error)
+	RemoveKanbanItemWatcher(ctx context.Context, arg RemoveKanbanItemWatcherParams) (int64, error)
+	RemoveProjectMember(ctx context.Context, arg RemoveProjectMemberParams) (int64, error)
+	// Maps usernames to members of an organisation, unknown names are left out
+	ResolveOrganisationUsernames(ctx context.Context, arg ResolveOrganisationUsernamesParams) ([]ResolveOrganisationUsernamesRow, error)
+	RestoreKanbanCategory(ctx context.Context, arg RestoreKanbanCategoryParams) (KanbanCategory, error)
+	RestoreKanbanItem(ctx context.Context, id string) (KanbanItem, error)
 	SearchOrganisations(ctx context.Context, arg SearchOrganisationsParams) ([]Organisation, error)
+	SetChatMemberLastMessage(ctx context.Context, arg SetChatMemberLastMessageParams) error
+	// Stamps or clears the completion time of every card after a category
+	// became or stopped being a done column
+	SetKanbanCategoryItemsCompleted(ctx context.Context, arg SetKanbanCategoryItemsCompletedParams) error
+	SetKanbanCategoryRank(ctx context.Context, arg SetKanbanCategoryRankParams) (KanbanCategory, error)
+	SetKanbanChecklistEntryPromoted(ctx context.Context, arg SetKanbanChecklistEntryPromotedParams) (KanbanChecklistEntry, error)
+	SetKanbanChecklistRank(ctx context.Context, arg SetKanbanChecklistRankParams) error
+	SetKanbanItemParent(ctx context.Context, arg SetKanbanItemParentParams) (KanbanItem, error)
+	SetKanbanItemRank(ctx context.Context, arg SetKanbanItemRankParams) error
+	SetKanbanLaneRank(ctx context.Context, arg SetKanbanLaneRankParams) (KanbanLane, error)
+	SetKanbanRecurrenceNextRun(ctx context.Context, arg SetKanbanRecurrenceNextRunParams) error
+	SetKanbanRecurrenceRunItem(ctx context.Context, arg SetKanbanRecurrenceRunItemParams) error
+	SoftDeleteKanbanCategory(ctx context.Context, arg SoftDeleteKanbanCategoryParams) (KanbanCategory, error)
+	SoftDeleteKanbanItem(ctx context.Context, id string) (KanbanItem, error)
+	SoftDeleteKanbanItemComment(ctx context.Context, id string) (KanbanItemComment, error)
+	StartKanbanTimer(ctx context.Context, arg StartKanbanTimerParams) (KanbanTimeEntry, error)
+	StopKanbanTimer(ctx context.Context, userID string) (KanbanTimeEntry, error)
+	// Takes a lock held until the transaction ends, false when another
+	// transaction holds it
+	TryKanbanReminderLock(ctx context.Context, key int64) (bool, error)
+	UpdateAnnouncement(ctx context.Context, arg UpdateAnnouncementParams) (Announcement, error)
+	// A set expected_version only matches the kanban while it is at that version
+	UpdateKanban(ctx context.Context, arg UpdateKanbanParams) (Kanban, error)
+	UpdateKanbanCategory(ctx context.Context, arg UpdateKanbanCategoryParams) (KanbanCategory, error)
+	UpdateKanbanChecklistEntry(ctx context.Context, arg UpdateKanbanChecklistEntryParams) (KanbanChecklistEntry, error)
+	UpdateKanbanItem(ctx context.Context, arg UpdateKanbanItemParams) (KanbanItem, error)
+	UpdateKanbanItemComment(ctx context.Context, arg UpdateKanbanItemCommentParams) (KanbanItemComment, error)
+	UpdateKanbanLabel(ctx context.Context, arg UpdateKanbanLabelParams) (KanbanLabel, error)
+	UpdateKanbanLane(ctx context.Context, arg UpdateKanbanLaneParams) (KanbanLane, error)
+	// The schedule is recomputed by the service, so next_run_at is always written
+	UpdateKanbanRecurrence(ctx context.Context, arg UpdateKanbanRecurrenceParams) (KanbanRecurrence, error)
+	UpdateKanbanRule(ctx context.Context, arg UpdateKanbanRuleParams) (KanbanRule, error)
 	UpdateOrganisation(ctx context.Context, arg UpdateOrganisationParams) (Organisation, error)
 	UpdateOrganisationDefaultRole(ctx context.Context, arg UpdateOrganisationDefaultRoleParams) (Organisation, error)
+	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateProjectMemberRole(ctx context.Context, arg UpdateProjectMemberRoleParams) (ProjectMember, error)
+	UpdateProjectStatus(ctx context.Context, arg UpdateProjectStatusParams) (Project, error)
 }
 
 var _ Querier = (*Queries)(nil)
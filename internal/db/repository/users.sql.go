@@ -90,6 +90,28 @@ func (q *Queries) GetByID(ctx context.Context, id string) (User, error) {
 	return i, err
 }
 
+const getByUsername = `-- name: GetByUsername :one
+SELECT id, created_at, updated_at, deleted_at, username, email, password, avatar
+FROM users
+WHERE username = $1
+`
+
+func (q *Queries) GetByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, getByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.Avatar,
+	)
+	return i, err
+}
+
 const getUsers = `-- name: GetUsers :many
 SELECT
     id,
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+)
+
+// CreateChatRoomInput creates a room with its creator and the given members
+// of the organisation
+type CreateChatRoomInput struct {
+	Name    *string  `json:"name" binding:"omitempty,max=255"`
+	Members []string `json:"members" binding:"max=100"`
+}
+
+type CreateChatMessageInput struct {
+	Message string `json:"message" binding:"required,max=4000"`
+}
+
+// ChatRoom is a room with the ids of its members
+type ChatRoom struct {
+	repository.ChatRoom
+	Members []string `json:"members"`
+}
+
+// ChatMessage is a message with the files attached to it
+type ChatMessage struct {
+	repository.ChatMessage
+	Attachments []repository.Attachment `json:"attachments"`
+}
+
+type GetChatRoomsResponse struct {
+	Rooms []repository.ChatRoom `json:"rooms"`
+}
+
+type ChatRoomResponse struct {
+	Room ChatRoom `json:"room"`
+}
+
+type GetChatMessagesResponse struct {
+	Messages []ChatMessage `json:"messages"`
+	Page     int           `json:"page"`
+	Limit    int           `json:"limit"`
+}
+
+type ChatMessageResponse struct {
+	Message ChatMessage `json:"message"`
+}
+
+type ChatAttachmentResponse struct {
+	Attachment repository.Attachment `json:"attachment"`
+}
+
+// ChatAttachmentDownloadResponse is a signed link to the file of an
+// attachment, it stops working at ExpiresAt
+type ChatAttachmentDownloadResponse struct {
+	Attachment repository.Attachment `json:"attachment"`
+	URL        string                `json:"url"`
+	ExpiresAt  time.Time             `json:"expiresAt"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+)
+
+type CreateAnnouncementInput struct {
+	Body      string     `json:"body" binding:"required,max=10000"`
+	Pinned    bool       `json:"pinned"`
+	PublishAt *time.Time `json:"publishAt"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CreateAnnouncementResponse struct {
+	Announcement repository.Announcement `json:"announcement"`
+}
@@ -0,0 +1,121 @@
+package dto
+
+import "time"
+
+type CreateKanbanInput struct {
+	Name   string       `json:"name" binding:"required,max=50"`
+	Status KanbanStatus `json:"status" binding:"omitempty,oneof=Planning 'In Progress' Done Archived"`
+}
+
+// CreateKanbanCategoryInput is a new column, done columns mark their cards
+// as completed and a WIP limit caps the cards moved into it
+type CreateKanbanCategoryInput struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Done     bool   `json:"done"`
+	WipLimit *int32 `json:"wipLimit" binding:"omitempty,min=1"`
+}
+
+// CreateKanbanItemInput creates a card, a due date in the past is refused
+type CreateKanbanItemInput struct {
+	CategoryID    string         `json:"categoryId" binding:"required"`
+	Title         string         `json:"title" binding:"required,max=40"`
+	Description   *string        `json:"description"`
+	Priority      KanbanPriority `json:"priority" binding:"omitempty,oneof=Extreme High Medium Low None"`
+	DueDate       *time.Time     `json:"dueDate"`
+	EstimatedTime *int32         `json:"estimatedTime" binding:"omitempty,min=0"`
+	LaneID        *string        `json:"laneId"`
+}
+
+// CreateKanbanLaneInput is a new swimlane, it is added below the others
+type CreateKanbanLaneInput struct {
+	Name  string  `json:"name" binding:"required,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type CreateKanbanLabelInput struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"required,hexcolor,len=7"`
+}
+
+// KanbanItemUserInput adds an assignee or watcher to an item
+type KanbanItemUserInput struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
+type KanbanItemLabelInput struct {
+	LabelID string `json:"labelId" binding:"required"`
+}
+
+// CreateKanbanItemLinkInput links an item to another card of the project
+type CreateKanbanItemLinkInput struct {
+	Type   KanbanLinkType `json:"type" binding:"required,oneof=blocks blocked_by relates"`
+	ItemID string         `json:"itemId" binding:"required"`
+}
+
+// CreateKanbanCommentInput is a markdown comment, replies name the comment they answer
+type CreateKanbanCommentInput struct {
+	Body     string  `json:"body" binding:"required,max=10000"`
+	ParentID *string `json:"parentId"`
+}
+
+type CreateKanbanChecklistEntryInput struct {
+	Title      string     `json:"title" binding:"required,max=200"`
+	AssigneeID *string    `json:"assigneeId"`
+	DueDate    *time.Time `json:"dueDate"`
+}
+
+// PromoteKanbanChecklistEntryInput turns an entry into a card, by default in
+// the category of the card it belongs to
+type PromoteKanbanChecklistEntryInput struct {
+	CategoryID *string `json:"categoryId"`
+}
+
+type StartKanbanTimerInput struct {
+	Note *string `json:"note" binding:"omitempty,max=500"`
+}
+
+// CreateKanbanTimeEntryInput logs time spent on a card after the fact
+type CreateKanbanTimeEntryInput struct {
+	StartedAt time.Time `json:"startedAt" binding:"required"`
+	EndedAt   time.Time `json:"endedAt" binding:"required"`
+	Note      *string   `json:"note" binding:"omitempty,max=500"`
+}
+
+// CreateKanbanRuleInput creates an automation rule, a category limits it to
+// cards created in, moved into or sitting in that column
+type CreateKanbanRuleInput struct {
+	Name       string             `json:"name" binding:"required,max=100"`
+	Enabled    *bool              `json:"enabled"`
+	Event      KanbanRuleEvent    `json:"event" binding:"required,oneof=item.created item.moved item.overdue"`
+	CategoryID *string            `json:"categoryId"`
+	Actions    []KanbanRuleAction `json:"actions" binding:"required,min=1,max=10,dive"`
+}
+
+// CreateKanbanRecurrenceInput schedules a card to be created in a category.
+// RRule is a subset of RFC 5545, such as "FREQ=WEEKLY;BYDAY=MO,TH" or
+// "FREQ=MONTHLY;BYMONTHDAY=1", and occurrences keep the time of day of StartsAt.
+type CreateKanbanRecurrenceInput struct {
+	CategoryID    string         `json:"categoryId" binding:"required"`
+	Title         string         `json:"title" binding:"required,max=40"`
+	Description   *string        `json:"description"`
+	Priority      KanbanPriority `json:"priority" binding:"omitempty,oneof=Extreme High Medium Low None"`
+	EstimatedTime *int32         `json:"estimatedTime" binding:"omitempty,min=0"`
+	RRule         string         `json:"rrule" binding:"required,max=100"`
+	StartsAt      time.Time      `json:"startsAt" binding:"required"`
+	Until         *time.Time     `json:"until"`
+	Enabled       *bool          `json:"enabled"`
+}
+
+type CreateKanbanResponse struct {
+	Board KanbanBoard `json:"board"`
+}
+
+// ImportKanbanInput is the form sent along with the file of a board import.
+// The format is guessed from the file when not given and the kanban is named
+// after the board or the file unless a name is sent.
+type ImportKanbanInput struct {
+	Format       KanbanImportSource `form:"format" binding:"omitempty,oneof=trello csv json"`
+	Name         string             `form:"name" binding:"omitempty,max=50"`
+	DryRun       bool               `form:"dryRun"`
+	UnknownUsers KanbanImportUsers  `form:"unknownUsers" binding:"omitempty,oneof=invite unassigned"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+
+type CreateProjectMemberInput struct {
+	UserID string      `json:"userId" binding:"required"`
+	Role   ProjectRole `json:"role" binding:"required,oneof=Admin Edit View"`
+}
+
+type CreateProjectInput struct {
+	Name    string                     `json:"name" binding:"required,max=255"`
+	Members []CreateProjectMemberInput `json:"members" binding:"dive"`
+}
+
+type CreateProjectResponse struct {
+	Project repository.Project `json:"project"`
+}
+
+type CreateProjectMemberResponse struct {
+	Member repository.ProjectMember `json:"member"`
+}
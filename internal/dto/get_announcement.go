@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+
+type GetAnnouncementsResponse struct {
+	Announcements []repository.ListAnnouncementsRow `json:"announcements"`
+	Page          int                               `json:"page"`
+	Limit         int                               `json:"limit"`
+}
+
+type GetAnnouncementResponse struct {
+	Announcement repository.Announcement `json:"announcement"`
+}
+
+type GetUnreadAnnouncementsResponse struct {
+	Announcements []repository.ListUnreadAnnouncementsRow `json:"announcements"`
+	Count         int                                     `json:"count"`
+}
+
+type MarkAnnouncementsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
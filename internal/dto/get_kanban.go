@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+)
+
+type GetKanbansResponse struct {
+	Kanbans []repository.Kanban `json:"kanbans"`
+}
+
+type GetKanbanResponse struct {
+	Board KanbanBoard `json:"board"`
+}
+
+// GetKanbanArchiveResponse is one page of archived categories and items, a
+// page holds up to limit of each and the totals tell when both are exhausted
+type GetKanbanArchiveResponse struct {
+	Categories    []repository.KanbanCategory `json:"categories"`
+	Items         []repository.KanbanItem     `json:"items"`
+	CategoryTotal int64                       `json:"categoryTotal"`
+	ItemTotal     int64                       `json:"itemTotal"`
+	Page          int                         `json:"page"`
+	Limit         int                         `json:"limit"`
+}
+
+type GetKanbanLanesResponse struct {
+	Lanes []repository.KanbanLane `json:"lanes"`
+}
+
+type KanbanLaneResponse struct {
+	Lane repository.KanbanLane `json:"lane"`
+}
+
+type GetKanbanAttachmentsResponse struct {
+	Attachments []repository.Attachment `json:"attachments"`
+}
+
+type KanbanAttachmentResponse struct {
+	Attachment repository.Attachment `json:"attachment"`
+}
+
+// KanbanAttachmentDownloadResponse is a signed link to the file of an
+// attachment, it stops working at ExpiresAt
+type KanbanAttachmentDownloadResponse struct {
+	Attachment repository.Attachment `json:"attachment"`
+	URL        string                `json:"url"`
+	ExpiresAt  time.Time             `json:"expiresAt"`
+}
+
+type GetKanbanLabelsResponse struct {
+	Labels []repository.KanbanLabel `json:"labels"`
+}
+
+type GetKanbanItemsResponse struct {
+	Items []repository.ListProjectKanbanItemsRow `json:"items"`
+	Page  int                                    `json:"page"`
+	Limit int                                    `json:"limit"`
+}
+
+type GetKanbanItemAssigneesResponse struct {
+	Assignees []repository.ListKanbanItemAssigneesRow `json:"assignees"`
+}
+
+type GetKanbanItemWatchersResponse struct {
+	Watchers []repository.ListKanbanItemWatchersRow `json:"watchers"`
+}
+
+type GetKanbanCommentsResponse struct {
+	Comments []KanbanComment `json:"comments"`
+}
+
+type GetKanbanCommentHistoryResponse struct {
+	Revisions []repository.ListKanbanItemCommentRevisionsRow `json:"revisions"`
+}
+
+type GetKanbanItemLinksResponse struct {
+	Links   []repository.ListKanbanItemLinksRow `json:"links"`
+	Blocked bool                                `json:"blocked"`
+}
+
+type GetKanbanActivityResponse struct {
+	Activity []KanbanActivity `json:"activity"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+}
+
+type GetKanbanRecurrencesResponse struct {
+	Recurrences []KanbanRecurrence `json:"recurrences"`
+}
+
+type GetKanbanRulesResponse struct {
+	Rules []KanbanRule `json:"rules"`
+}
+
+// GetKanbanItemTimeResponse is the time logged on a card, seconds are totals
+// with running timers counted up to now
+type GetKanbanItemTimeResponse struct {
+	Entries []repository.ListKanbanTimeEntriesRow  `json:"entries"`
+	Users   []repository.KanbanTimeTotalsByUserRow `json:"users"`
+	Seconds int64                                  `json:"seconds"`
+}
+
+// GetKanbanTimeReportResponse totals the time logged in a project by user and by card
+type GetKanbanTimeReportResponse struct {
+	Users   []repository.KanbanTimeTotalsByUserRow `json:"users"`
+	Items   []repository.KanbanTimeTotalsByItemRow `json:"items"`
+	Seconds int64                                  `json:"seconds"`
+}
+
+// GetKanbanMetricsResponse holds the flow metrics of a kanban over a range of
+// days, lead time runs from creation and cycle time from the start of work
+// to completion
+type GetKanbanMetricsResponse struct {
+	From           string                      `json:"from"`
+	To             string                      `json:"to"`
+	LeadTime       KanbanDurationStats         `json:"lead_time"`
+	CycleTime      KanbanDurationStats         `json:"cycle_time"`
+	Throughput     []KanbanThroughputWeek      `json:"throughput"`
+	CumulativeFlow []KanbanFlowDay             `json:"cumulative_flow"`
+	Categories     []repository.KanbanCategory `json:"categories"`
+}
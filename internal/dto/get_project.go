@@ -0,0 +1,17 @@
+package dto
+
+import "github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+
+type GetProjectsResponse struct {
+	Projects []repository.Project `json:"projects"`
+	Page     int                  `json:"page"`
+	Limit    int                  `json:"limit"`
+}
+
+type GetProjectResponse struct {
+	Project repository.Project `json:"project"`
+}
+
+type GetProjectMembersResponse struct {
+	Members []repository.ListProjectMembersRow `json:"members"`
+}
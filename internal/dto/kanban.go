@@ -0,0 +1,629 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+type KanbanStatus string
+
+const (
+	KanbanPlanning   KanbanStatus = "Planning"
+	KanbanInProgress KanbanStatus = "In Progress"
+	KanbanDone       KanbanStatus = "Done"
+	KanbanArchived   KanbanStatus = "Archived"
+)
+
+type KanbanPriority string
+
+const (
+	PriorityExtreme KanbanPriority = "Extreme"
+	PriorityHigh    KanbanPriority = "High"
+	PriorityMedium  KanbanPriority = "Medium"
+	PriorityLow     KanbanPriority = "Low"
+	PriorityNone    KanbanPriority = "None"
+)
+
+// KanbanMovePolicy decides what happens when a move breaks a board rule
+type KanbanMovePolicy string
+
+const (
+	MovePolicyWarn   KanbanMovePolicy = "warn"
+	MovePolicyReject KanbanMovePolicy = "reject"
+)
+
+// KanbanLinkType is a link between two cards as seen from one of them,
+// blocked_by is stored as a blocks link the other way round
+type KanbanLinkType string
+
+const (
+	LinkBlocks    KanbanLinkType = "blocks"
+	LinkBlockedBy KanbanLinkType = "blocked_by"
+	LinkRelates   KanbanLinkType = "relates"
+)
+
+// KanbanEntityType is the kind of row an activity entry is about
+type KanbanEntityType string
+
+const (
+	KanbanEntityKanban   KanbanEntityType = "kanban"
+	KanbanEntityCategory KanbanEntityType = "category"
+	KanbanEntityItem     KanbanEntityType = "item"
+	KanbanEntityRule     KanbanEntityType = "rule"
+	KanbanEntityLane     KanbanEntityType = "lane"
+)
+
+type KanbanActivityAction string
+
+const (
+	ActivityCreate      KanbanActivityAction = "create"
+	ActivityUpdate      KanbanActivityAction = "update"
+	ActivityMove        KanbanActivityAction = "move"
+	ActivityDelete      KanbanActivityAction = "delete"
+	ActivityRestore     KanbanActivityAction = "restore"
+	ActivityPermaDelete KanbanActivityAction = "perma_delete"
+	ActivityRevert      KanbanActivityAction = "revert"
+	ActivityRun         KanbanActivityAction = "run"
+)
+
+// KanbanRuleEvent is what makes an automation rule fire
+type KanbanRuleEvent string
+
+const (
+	RuleItemCreated KanbanRuleEvent = "item.created"
+	RuleItemMoved   KanbanRuleEvent = "item.moved"
+	RuleItemOverdue KanbanRuleEvent = "item.overdue"
+)
+
+// KanbanRuleActionType is what a rule does to the card it fired for
+type KanbanRuleActionType string
+
+const (
+	RuleSetPriority    KanbanRuleActionType = "set_priority"
+	RuleAssign         KanbanRuleActionType = "assign"
+	RuleAddLabel       KanbanRuleActionType = "add_label"
+	RuleMove           KanbanRuleActionType = "move"
+	RuleSetBoardStatus KanbanRuleActionType = "set_board_status"
+	RuleNotifyWatchers KanbanRuleActionType = "notify_watchers"
+)
+
+// KanbanBulkAction is what a bulk request does to every card it names
+type KanbanBulkAction string
+
+const (
+	BulkMove     KanbanBulkAction = "move"
+	BulkLabel    KanbanBulkAction = "label"
+	BulkUnlabel  KanbanBulkAction = "unlabel"
+	BulkAssign   KanbanBulkAction = "assign"
+	BulkUnassign KanbanBulkAction = "unassign"
+	BulkArchive  KanbanBulkAction = "archive"
+	BulkDelete   KanbanBulkAction = "delete"
+)
+
+// KanbanImportSource is the kind of file a board is imported from
+type KanbanImportSource string
+
+const (
+	ImportTrello KanbanImportSource = "trello"
+	ImportCSV    KanbanImportSource = "csv"
+	// A board exported by this API as JSON
+	ImportJSON KanbanImportSource = "json"
+)
+
+// KanbanExportFormat is the file format of a board export
+type KanbanExportFormat string
+
+const (
+	ExportJSON     KanbanExportFormat = "json"
+	ExportCSV      KanbanExportFormat = "csv"
+	ExportMarkdown KanbanExportFormat = "markdown"
+)
+
+// KanbanExportName marks JSON exports of this API so imports can tell them
+// apart from Trello boards
+const (
+	KanbanExportName    = "didlydoodash"
+	KanbanExportVersion = 1
+)
+
+// KanbanImportUsers tells what an import does with assignees that are not
+// members of the project
+type KanbanImportUsers string
+
+const (
+	ImportInviteUsers     KanbanImportUsers = "invite"
+	ImportUnassignedUsers KanbanImportUsers = "unassigned"
+)
+
+// KanbanImportIssueLevel is error for rows that were skipped and warning for
+// rows imported with a change
+type KanbanImportIssueLevel string
+
+const (
+	ImportIssueError   KanbanImportIssueLevel = "error"
+	ImportIssueWarning KanbanImportIssueLevel = "warning"
+)
+
+// KanbanLaneGrouping picks what the lanes of a loaded board are, the custom
+// lanes of the kanban or one lane per assignee or priority
+type KanbanLaneGrouping string
+
+const (
+	LaneByCustom   KanbanLaneGrouping = "lane"
+	LaneByAssignee KanbanLaneGrouping = "assignee"
+	LaneByPriority KanbanLaneGrouping = "priority"
+)
+
+// DefaultKanbanCategory is created together with every new kanban
+const DefaultKanbanCategory = "Not assigned"
+
+// DefaultKanbanArchiveLimit is the page size of the archive when none is asked for
+const DefaultKanbanArchiveLimit = 50
+
+// KanbanBoard is a kanban with its active categories and items, together
+// with every label that can be put on its cards. Lanes group the same cards
+// by lane and category, by id.
+type KanbanBoard struct {
+	Kanban     repository.Kanban        `json:"kanban"`
+	Categories []KanbanBoardCategory    `json:"categories"`
+	Labels     []repository.KanbanLabel `json:"labels"`
+	LaneBy     KanbanLaneGrouping       `json:"laneBy"`
+	Lanes      []KanbanBoardLane        `json:"lanes"`
+}
+
+// KanbanBoardLane is one row of the board. Key is the lane id, assignee id or
+// priority the lane stands for, the lane of cards without one has an empty
+// key and comes last. Custom lanes carry the lane itself.
+type KanbanBoardLane struct {
+	Key        string                    `json:"key"`
+	Lane       *repository.KanbanLane    `json:"lane,omitempty"`
+	Categories []KanbanBoardLaneCategory `json:"categories"`
+}
+
+// KanbanBoardLaneCategory lists the cards of a category within a lane in
+// board order
+type KanbanBoardLaneCategory struct {
+	CategoryID string   `json:"categoryId"`
+	ItemIDs    []string `json:"itemIds"`
+}
+
+type KanbanBoardCategory struct {
+	repository.KanbanCategory
+	Items []KanbanCard `json:"items"`
+}
+
+// KanbanCard is an item on the board with the ids of its assignees and
+// labels, the progress of its checklist and whether unfinished cards block it
+type KanbanCard struct {
+	repository.KanbanItem
+	Assignees []string                `json:"assignees"`
+	Labels    []string                `json:"labels"`
+	Checklist KanbanChecklistProgress `json:"checklist"`
+	Blocked   bool                    `json:"blocked"`
+}
+
+// KanbanChecklistProgress is the roll-up shown on a card, e.g. 3/5 done
+type KanbanChecklistProgress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
+
+type KanbanChecklistResponse struct {
+	Entries  []repository.KanbanChecklistEntry `json:"entries"`
+	Progress KanbanChecklistProgress           `json:"progress"`
+}
+
+// PromoteKanbanChecklistEntryResponse is the card created from a checklist
+// entry together with the entry now linking to it
+type PromoteKanbanChecklistEntryResponse struct {
+	Item  repository.KanbanItem           `json:"item"`
+	Entry repository.KanbanChecklistEntry `json:"entry"`
+}
+
+// KanbanItemDetail is a single item with its people, labels and links resolved
+type KanbanItemDetail struct {
+	Item      repository.KanbanItem                   `json:"item"`
+	Assignees []repository.ListKanbanItemAssigneesRow `json:"assignees"`
+	Watchers  []repository.ListKanbanItemWatchersRow  `json:"watchers"`
+	Labels    []repository.KanbanLabel                `json:"labels"`
+	Links     []repository.ListKanbanItemLinksRow     `json:"links"`
+	Blocked   bool                                    `json:"blocked"`
+}
+
+// KanbanComment is a comment in an item thread with the ids of mentioned users
+type KanbanComment struct {
+	repository.ListKanbanItemCommentsRow
+	Mentions []string `json:"mentions"`
+}
+
+type KanbanCommentResponse struct {
+	Comment  repository.KanbanItemComment `json:"comment"`
+	Mentions []string                     `json:"mentions"`
+}
+
+type KanbanLabelResponse struct {
+	Label repository.KanbanLabel `json:"label"`
+}
+
+// KanbanFieldChange is one field of a row before and after a change, null
+// when the row didn't exist yet or no longer exists
+type KanbanFieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// KanbanActivity is an entry of the activity log of a kanban
+type KanbanActivity struct {
+	ID            string               `json:"id"`
+	KanbanID      string               `json:"kanban_id"`
+	ActorID       *string              `json:"actor_id,omitempty"`
+	ActorUsername *string              `json:"actor_username,omitempty"`
+	RuleID        *string              `json:"rule_id,omitempty"`
+	RuleName      *string              `json:"rule_name,omitempty"`
+	EntityType    KanbanEntityType     `json:"entity_type"`
+	EntityID      string               `json:"entity_id"`
+	ItemID        *string              `json:"item_id,omitempty"`
+	Action        KanbanActivityAction `json:"action"`
+	Changes       []KanbanFieldChange  `json:"changes"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+func NewKanbanActivity(a repository.ListKanbanActivityRow) KanbanActivity {
+	changes := []KanbanFieldChange{}
+	_ = json.Unmarshal(a.Changes, &changes)
+
+	return KanbanActivity{
+		ID:            a.ID,
+		KanbanID:      a.KanbanID,
+		ActorID:       utils.PgTextToPtr(a.ActorID),
+		ActorUsername: utils.PgTextToPtr(a.ActorUsername),
+		RuleID:        utils.PgTextToPtr(a.RuleID),
+		RuleName:      utils.PgTextToPtr(a.RuleName),
+		EntityType:    KanbanEntityType(a.EntityType),
+		EntityID:      a.EntityID,
+		ItemID:        utils.PgTextToPtr(a.ItemID),
+		Action:        KanbanActivityAction(a.Action),
+		Changes:       changes,
+		CreatedAt:     a.CreatedAt.Time,
+	}
+}
+
+// KanbanDurationStats summarises how long cards took, in seconds
+type KanbanDurationStats struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+	P50     float64 `json:"p50"`
+	P85     float64 `json:"p85"`
+	P95     float64 `json:"p95"`
+}
+
+// KanbanThroughputWeek is the number of cards completed in the week starting on Monday
+type KanbanThroughputWeek struct {
+	Week  string `json:"week"`
+	Items int    `json:"items"`
+}
+
+// KanbanFlowDay counts the cards in each category at the end of a day
+type KanbanFlowDay struct {
+	Day        string           `json:"day"`
+	Categories map[string]int32 `json:"categories"`
+}
+
+type KanbanCategoryResponse struct {
+	Category repository.KanbanCategory `json:"category"`
+}
+
+type KanbanItemResponse struct {
+	Item repository.KanbanItem `json:"item"`
+}
+
+// Realtime event payloads, userId is the user that made the change
+
+type KanbanEvent struct {
+	Kanban   repository.Kanban `json:"kanban"`
+	SenderID string            `json:"userId"`
+}
+
+type KanbanDeletedEvent struct {
+	ID       string `json:"id"`
+	SenderID string `json:"userId"`
+}
+
+type KanbanCategoryEvent struct {
+	Category repository.KanbanCategory `json:"category"`
+	SenderID string                    `json:"userId"`
+}
+
+type KanbanItemEvent struct {
+	Item     repository.KanbanItem `json:"item"`
+	SenderID string                `json:"userId"`
+}
+
+type KanbanItemMoveEvent struct {
+	ItemID        string                `json:"itemId"`
+	OldCategoryID string                `json:"oldCategoryId"`
+	NewCategoryID string                `json:"newCategoryId"`
+	OldLaneID     *string               `json:"oldLaneId"`
+	NewLaneID     *string               `json:"newLaneId"`
+	Item          repository.KanbanItem `json:"item"`
+	SenderID      string                `json:"userId"`
+}
+
+type KanbanLaneEvent struct {
+	Lane     repository.KanbanLane `json:"lane"`
+	SenderID string                `json:"userId"`
+}
+
+// KanbanLaneDeleteEvent names the cards that were left without a lane
+type KanbanLaneDeleteEvent struct {
+	LaneID   string   `json:"laneId"`
+	ItemIDs  []string `json:"itemIds"`
+	SenderID string   `json:"userId"`
+}
+
+type KanbanRank struct {
+	ID   string `json:"id"`
+	Rank string `json:"rank"`
+}
+
+// KanbanRebalanceEvent carries new ranks after a category or the columns were rebalanced
+type KanbanRebalanceEvent struct {
+	CategoryID string       `json:"categoryId,omitempty"`
+	Ranks      []KanbanRank `json:"ranks"`
+	SenderID   string       `json:"userId"`
+}
+
+// KanbanItemUsersEvent carries the full list of assignees or watchers of an item
+type KanbanItemUsersEvent struct {
+	ItemID   string   `json:"itemId"`
+	UserIDs  []string `json:"userIds"`
+	SenderID string   `json:"userId"`
+}
+
+// KanbanItemLabelsEvent carries the full list of labels on an item
+type KanbanItemLabelsEvent struct {
+	ItemID   string   `json:"itemId"`
+	LabelIDs []string `json:"labelIds"`
+	SenderID string   `json:"userId"`
+}
+
+// KanbanImportIssue is a problem with one row of an imported file, rows are
+// numbered as in the file
+type KanbanImportIssue struct {
+	Row     int                    `json:"row"`
+	Column  string                 `json:"column"`
+	Title   string                 `json:"title"`
+	Level   KanbanImportIssueLevel `json:"level"`
+	Message string                 `json:"message"`
+}
+
+type KanbanImportColumn struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+// KanbanExport is a board exported as JSON, it is written as a stream and
+// can be imported again. Cards name their labels and assignees, users are
+// matched by email on import.
+type KanbanExport struct {
+	Format     string                 `json:"format"`
+	Version    int                    `json:"version"`
+	ExportedAt time.Time              `json:"exportedAt"`
+	Kanban     KanbanExportKanban     `json:"kanban"`
+	Labels     []KanbanExportLabel    `json:"labels"`
+	Categories []KanbanExportCategory `json:"categories"`
+}
+
+type KanbanExportKanban struct {
+	Name   string       `json:"name"`
+	Status KanbanStatus `json:"status"`
+}
+
+type KanbanExportLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type KanbanExportCategory struct {
+	Name     string             `json:"name"`
+	Done     bool               `json:"done"`
+	WipLimit *int32             `json:"wipLimit"`
+	Archived bool               `json:"archived"`
+	Items    []KanbanExportItem `json:"items"`
+}
+
+type KanbanExportItem struct {
+	Title         string             `json:"title"`
+	Description   *string            `json:"description"`
+	Priority      KanbanPriority     `json:"priority"`
+	DueDate       *time.Time         `json:"dueDate"`
+	EstimatedTime *int32             `json:"estimatedTime"`
+	CompletedAt   *time.Time         `json:"completedAt"`
+	Archived      bool               `json:"archived"`
+	Labels        []string           `json:"labels"`
+	Assignees     []KanbanExportUser `json:"assignees"`
+}
+
+type KanbanExportUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// KanbanImportResult describes an import or the preview of a dry run, the
+// kanban is only set when the board was created
+type KanbanImportResult struct {
+	ID          string               `json:"id"`
+	Source      KanbanImportSource   `json:"source"`
+	DryRun      bool                 `json:"dryRun"`
+	Name        string               `json:"name"`
+	Kanban      *repository.Kanban   `json:"kanban,omitempty"`
+	Columns     []KanbanImportColumn `json:"columns"`
+	Labels      []string             `json:"labels"`
+	Invitations []string             `json:"invitations"`
+	Rows        int                  `json:"rows"`
+	Imported    int                  `json:"imported"`
+	Skipped     int                  `json:"skipped"`
+	Issues      []KanbanImportIssue  `json:"issues"`
+}
+
+// KanbanItemsBulkEvent is the outcome of a bulk request, sent once for the
+// whole batch. Items holds the cards as they are after the change, labels and
+// assignees the full lists of the touched cards keyed by item ID.
+type KanbanItemsBulkEvent struct {
+	Action         KanbanBulkAction        `json:"action"`
+	Items          []repository.KanbanItem `json:"items"`
+	OldCategoryIDs map[string]string       `json:"oldCategoryIds,omitempty"`
+	Labels         map[string][]string     `json:"labels,omitempty"`
+	Assignees      map[string][]string     `json:"assignees,omitempty"`
+	SenderID       string                  `json:"userId"`
+}
+
+// KanbanArchivePurgeEvent lists the archived categories and items removed
+// once the retention of their organisation ran out
+type KanbanArchivePurgeEvent struct {
+	CategoryIDs []string `json:"categoryIds"`
+	ItemIDs     []string `json:"itemIds"`
+}
+
+type KanbanLabelEvent struct {
+	Label    repository.KanbanLabel `json:"label"`
+	SenderID string                 `json:"userId"`
+}
+
+type KanbanCommentEvent struct {
+	Comment  repository.KanbanItemComment `json:"comment"`
+	Mentions []string                     `json:"mentions"`
+	SenderID string                       `json:"userId"`
+}
+
+type KanbanAttachmentEvent struct {
+	ItemID     string                `json:"itemId"`
+	Attachment repository.Attachment `json:"attachment"`
+	SenderID   string                `json:"userId"`
+}
+
+type KanbanAttachmentDeleteEvent struct {
+	ItemID       string `json:"itemId"`
+	AttachmentID string `json:"attachmentId"`
+	SenderID     string `json:"userId"`
+}
+
+// KanbanChecklistEvent carries the whole checklist of an item after a change
+type KanbanChecklistEvent struct {
+	ItemID   string                            `json:"itemId"`
+	Entries  []repository.KanbanChecklistEntry `json:"entries"`
+	Progress KanbanChecklistProgress           `json:"progress"`
+	SenderID string                            `json:"userId"`
+}
+
+type KanbanItemLinkEvent struct {
+	Link     repository.KanbanItemLink `json:"link"`
+	SenderID string                    `json:"userId"`
+}
+
+// KanbanItemBlockedEvent is sent when a card becomes blocked or unblocked
+type KanbanItemBlockedEvent struct {
+	ItemID   string `json:"itemId"`
+	Blocked  bool   `json:"blocked"`
+	SenderID string `json:"userId"`
+}
+
+// KanbanRuleAction is one step of a rule, only the fields of its type are used
+type KanbanRuleAction struct {
+	Type       KanbanRuleActionType `json:"type" binding:"required,oneof=set_priority assign add_label move set_board_status notify_watchers"`
+	Priority   *KanbanPriority      `json:"priority,omitempty" binding:"omitempty,oneof=Extreme High Medium Low None"`
+	UserID     *string              `json:"userId,omitempty"`
+	LabelID    *string              `json:"labelId,omitempty"`
+	CategoryID *string              `json:"categoryId,omitempty"`
+	Status     *KanbanStatus        `json:"status,omitempty" binding:"omitempty,oneof=Planning 'In Progress' Done Archived"`
+	Message    *string              `json:"message,omitempty" binding:"omitempty,max=500"`
+}
+
+// KanbanRule is an automation rule of a board
+type KanbanRule struct {
+	ID         string             `json:"id"`
+	KanbanID   string             `json:"kanban_id"`
+	Name       string             `json:"name"`
+	Enabled    bool               `json:"enabled"`
+	Event      KanbanRuleEvent    `json:"event"`
+	CategoryID *string            `json:"category_id,omitempty"`
+	Actions    []KanbanRuleAction `json:"actions"`
+	CreatedBy  *string            `json:"created_by,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// KanbanRuleRun is logged every time a rule fires, with the actions that
+// went through and the ones that failed
+type KanbanRuleRun struct {
+	Event   KanbanRuleEvent         `json:"event"`
+	Applied []KanbanRuleActionType  `json:"applied"`
+	Failed  []KanbanRuleActionError `json:"failed,omitempty"`
+}
+
+type KanbanRuleActionError struct {
+	Type    KanbanRuleActionType `json:"type"`
+	Message string               `json:"message"`
+}
+
+func NewKanbanRule(r repository.KanbanRule) KanbanRule {
+	actions := []KanbanRuleAction{}
+	_ = json.Unmarshal(r.Actions, &actions)
+
+	return KanbanRule{
+		ID:         r.ID,
+		KanbanID:   r.KanbanID,
+		Name:       r.Name,
+		Enabled:    r.Enabled,
+		Event:      KanbanRuleEvent(r.Event),
+		CategoryID: utils.PgTextToPtr(r.CategoryID),
+		Actions:    actions,
+		CreatedBy:  utils.PgTextToPtr(r.CreatedBy),
+		CreatedAt:  r.CreatedAt.Time,
+		UpdatedAt:  r.UpdatedAt.Time,
+	}
+}
+
+// KanbanRecurrence is a card created on a schedule, NextRunAt is empty once
+// the schedule has ended
+type KanbanRecurrence struct {
+	ID            string         `json:"id"`
+	KanbanID      string         `json:"kanban_id"`
+	CategoryID    string         `json:"category_id"`
+	Title         string         `json:"title"`
+	Description   *string        `json:"description,omitempty"`
+	Priority      KanbanPriority `json:"priority"`
+	EstimatedTime *int32         `json:"estimated_time,omitempty"`
+	RRule         string         `json:"rrule"`
+	StartsAt      time.Time      `json:"starts_at"`
+	Until         *time.Time     `json:"until,omitempty"`
+	NextRunAt     *time.Time     `json:"next_run_at,omitempty"`
+	Enabled       bool           `json:"enabled"`
+	CreatedBy     *string        `json:"created_by,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+func NewKanbanRecurrence(r repository.KanbanRecurrence) KanbanRecurrence {
+	return KanbanRecurrence{
+		ID:            r.ID,
+		KanbanID:      r.KanbanID,
+		CategoryID:    r.CategoryID,
+		Title:         r.Title,
+		Description:   utils.PgTextToPtr(r.Description),
+		Priority:      KanbanPriority(r.Priority),
+		EstimatedTime: utils.PgInt4ToPtr(r.EstimatedTime),
+		RRule:         r.Rrule,
+		StartsAt:      r.StartsAt.Time,
+		Until:         utils.PgTimestamptzToPtr(r.Until),
+		NextRunAt:     utils.PgTimestamptzToPtr(r.NextRunAt),
+		Enabled:       r.Enabled,
+		CreatedBy:     utils.PgTextToPtr(r.CreatedBy),
+		CreatedAt:     r.CreatedAt.Time,
+		UpdatedAt:     r.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+type NotificationType string
+
+const (
+	NotificationCommentMention NotificationType = "kanban.comment.mention"
+	NotificationRule           NotificationType = "kanban.rule"
+	NotificationDueReminder    NotificationType = "kanban.item.due"
+	NotificationOverdueDigest  NotificationType = "kanban.overdue.digest"
+)
+
+// KanbanCommentNotification is the data of a notification about a comment
+type KanbanCommentNotification struct {
+	ProjectID string `json:"projectId"`
+	KanbanID  string `json:"kanbanId"`
+	ItemID    string `json:"itemId"`
+	CommentID string `json:"commentId"`
+}
+
+// KanbanRuleNotification is the data of a notification sent by an automation rule
+type KanbanRuleNotification struct {
+	ProjectID string  `json:"projectId"`
+	KanbanID  string  `json:"kanbanId"`
+	ItemID    string  `json:"itemId"`
+	RuleID    string  `json:"ruleId"`
+	RuleName  string  `json:"ruleName"`
+	Message   *string `json:"message,omitempty"`
+}
+
+// KanbanDueNotification is the data of a reminder that a card is coming due,
+// OffsetSeconds is how long before the due date it was sent for
+type KanbanDueNotification struct {
+	ProjectID     string    `json:"projectId"`
+	KanbanID      string    `json:"kanbanId"`
+	ItemID        string    `json:"itemId"`
+	Title         string    `json:"title"`
+	DueDate       time.Time `json:"dueDate"`
+	OffsetSeconds int32     `json:"offsetSeconds"`
+}
+
+// KanbanOverdueDigestNotification is the data of the daily digest of the
+// overdue cards a user is assigned to or watches in an organisation
+type KanbanOverdueDigestNotification struct {
+	Items []KanbanOverdueItem `json:"items"`
+}
+
+type KanbanOverdueItem struct {
+	ProjectID  string    `json:"projectId"`
+	KanbanID   string    `json:"kanbanId"`
+	KanbanName string    `json:"kanbanName"`
+	ItemID     string    `json:"itemId"`
+	Title      string    `json:"title"`
+	DueDate    time.Time `json:"dueDate"`
+}
+
+type Notification struct {
+	ID             string           `json:"id"`
+	OrganisationID string           `json:"organisation_id"`
+	ActorID        *string          `json:"actor_id,omitempty"`
+	ActorUsername  *string          `json:"actor_username,omitempty"`
+	Type           NotificationType `json:"type"`
+	Data           json.RawMessage  `json:"data"`
+	ReadAt         *time.Time       `json:"read_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+func NewNotification(n repository.ListNotificationsRow) Notification {
+	return Notification{
+		ID:             n.ID,
+		OrganisationID: n.OrganisationID,
+		ActorID:        utils.PgTextToPtr(n.ActorID),
+		ActorUsername:  utils.PgTextToPtr(n.ActorUsername),
+		Type:           NotificationType(n.Type),
+		Data:           json.RawMessage(n.Data),
+		ReadAt:         utils.PgTimestamptzToPtr(n.ReadAt),
+		CreatedAt:      n.CreatedAt.Time,
+	}
+}
+
+type GetNotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type ProjectStatus string
+
+const (
+	ProjectActive    ProjectStatus = "Active"
+	ProjectCompleted ProjectStatus = "Completed"
+	ProjectArchived  ProjectStatus = "Archived"
+)
+
+type ProjectRole string
+
+const (
+	ProjectAdmin ProjectRole = "Admin"
+	ProjectEdit  ProjectRole = "Edit"
+	ProjectView  ProjectRole = "View"
+)
+
+// CanTransition reports whether a project may move from one status to another
+func (s ProjectStatus) CanTransition(to ProjectStatus) bool {
+	switch s {
+	case ProjectActive:
+		return to == ProjectCompleted || to == ProjectArchived
+	case ProjectCompleted:
+		return to == ProjectActive || to == ProjectArchived
+	case ProjectArchived:
+		return to == ProjectActive
+	}
+	return false
+}
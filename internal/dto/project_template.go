@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// ProjectTemplateContent is the snapshot of a project stored in a template.
+// IDs are the ids of the source rows and are remapped when instantiated.
+type ProjectTemplateContent struct {
+	Kanbans     []TemplateKanban     `json:"kanbans"`
+	Whiteboards []TemplateWhiteboard `json:"whiteboards,omitempty"`
+}
+
+type TemplateKanban struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Status            string             `json:"status"`
+	BlockedMovePolicy *string            `json:"blockedMovePolicy,omitempty"`
+	WipPolicy         *string            `json:"wipPolicy,omitempty"`
+	Lanes             []TemplateLane     `json:"lanes,omitempty"`
+	Categories        []TemplateCategory `json:"categories"`
+}
+
+type TemplateLane struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+}
+
+type TemplateCategory struct {
+	ID       string         `json:"id"`
+	Name     *string        `json:"name"`
+	Done     bool           `json:"done,omitempty"`
+	WipLimit *int32         `json:"wipLimit,omitempty"`
+	Items    []TemplateItem `json:"items,omitempty"`
+}
+
+type TemplateItem struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Description   *string    `json:"description,omitempty"`
+	Priority      string     `json:"priority"`
+	DueDate       *time.Time `json:"dueDate,omitempty"`
+	EstimatedTime *int32     `json:"estimatedTime,omitempty"`
+	LaneID        *string    `json:"laneId,omitempty"`
+}
+
+type TemplateWhiteboard struct {
+	ID    string         `json:"id"`
+	Name  *string        `json:"name"`
+	Lines []TemplateLine `json:"lines"`
+}
+
+type TemplateLine struct {
+	ID          string    `json:"id"`
+	Stroke      *string   `json:"stroke,omitempty"`
+	StrokeWidth *int32    `json:"strokeWidth,omitempty"`
+	Tool        *string   `json:"tool,omitempty"`
+	TextContent *string   `json:"textContent,omitempty"`
+	Points      []float64 `json:"points"`
+}
+
+type ProjectTemplate struct {
+	ID                 string          `json:"id"`
+	OrganisationID     string          `json:"organisation_id"`
+	CreatedBy          *string         `json:"created_by,omitempty"`
+	Name               string          `json:"name"`
+	Description        *string         `json:"description,omitempty"`
+	IncludeItems       bool            `json:"include_items"`
+	IncludeWhiteboards bool            `json:"include_whiteboards"`
+	Content            json.RawMessage `json:"content,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+func NewProjectTemplate(t repository.ProjectTemplate, withContent bool) ProjectTemplate {
+	template := ProjectTemplate{
+		ID:                 t.ID,
+		OrganisationID:     t.OrganisationID,
+		CreatedBy:          utils.PgTextToPtr(t.CreatedBy),
+		Name:               t.Name,
+		Description:        utils.PgTextToPtr(t.Description),
+		IncludeItems:       t.IncludeItems,
+		IncludeWhiteboards: t.IncludeWhiteboards,
+		CreatedAt:          t.CreatedAt.Time,
+		UpdatedAt:          t.UpdatedAt.Time,
+	}
+	if withContent {
+		template.Content = json.RawMessage(t.Content)
+	}
+	return template
+}
+
+// ---- Request Structs ----
+type CreateProjectTemplateInput struct {
+	ProjectID          string  `json:"projectId" binding:"required"`
+	Name               string  `json:"name" binding:"required,max=255"`
+	Description        *string `json:"description"`
+	IncludeItems       bool    `json:"includeItems"`
+	IncludeWhiteboards bool    `json:"includeWhiteboards"`
+}
+
+type InstantiateProjectTemplateInput struct {
+	Name    string                     `json:"name" binding:"required,max=255"`
+	Members []CreateProjectMemberInput `json:"members" binding:"dive"`
+}
+
+type CloneProjectInput struct {
+	Name               string `json:"name" binding:"required,max=255"`
+	IncludeItems       bool   `json:"includeItems"`
+	IncludeWhiteboards bool   `json:"includeWhiteboards"`
+	IncludeMembers     bool   `json:"includeMembers"`
+}
+
+// ---- Response Structs ----
+type CreateProjectTemplateResponse struct {
+	Template ProjectTemplate `json:"template"`
+}
+
+type GetProjectTemplatesResponse struct {
+	Templates []ProjectTemplate `json:"templates"`
+	Page      int               `json:"page"`
+	Limit     int               `json:"limit"`
+}
+
+type GetProjectTemplateResponse struct {
+	Template ProjectTemplate `json:"template"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+)
+
+type UpdateAnnouncementInput struct {
+	Body           *string    `json:"body" binding:"omitempty,max=10000"`
+	Pinned         *bool      `json:"pinned"`
+	PublishAt      *time.Time `json:"publishAt"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	ClearExpiresAt bool       `json:"clearExpiresAt"`
+}
+
+type UpdateAnnouncementResponse struct {
+	Announcement repository.Announcement `json:"announcement"`
+}
@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+type UpdateKanbanInput struct {
+	Name              *string           `json:"name" binding:"omitempty,max=50"`
+	Status            *KanbanStatus     `json:"status" binding:"omitempty,oneof=Planning 'In Progress' Done Archived"`
+	BlockedMovePolicy *KanbanMovePolicy `json:"blockedMovePolicy" binding:"omitempty,oneof=warn reject"`
+	WipPolicy         *KanbanMovePolicy `json:"wipPolicy" binding:"omitempty,oneof=warn reject"`
+}
+
+// UpdateKanbanCategoryInput renames a category, marks it as a done column or
+// changes its WIP limit, a null limit removes it
+type UpdateKanbanCategoryInput struct {
+	Name     *string               `json:"name" binding:"omitempty,max=50"`
+	Done     *bool                 `json:"done"`
+	WipLimit utils.Optional[int32] `json:"wipLimit"`
+}
+
+// UpdateKanbanItemInput is a partial update, nullable fields are
+// cleared when sent as null and left untouched when omitted. A due date in
+// the past is refused unless it is the one the card already has.
+type UpdateKanbanItemInput struct {
+	Title         *string                   `json:"title" binding:"omitempty,max=40"`
+	Priority      *KanbanPriority           `json:"priority" binding:"omitempty,oneof=Extreme High Medium Low None"`
+	Description   utils.Optional[string]    `json:"description"`
+	DueDate       utils.Optional[time.Time] `json:"dueDate"`
+	EstimatedTime utils.Optional[int32]     `json:"estimatedTime"`
+}
+
+// MoveKanbanItemInput places an item in a category, after and before are
+// ids of items in that category, without either the item goes last. The
+// lane is kept when omitted and cleared when sent as null.
+type MoveKanbanItemInput struct {
+	CategoryID string                 `json:"categoryId" binding:"required"`
+	LaneID     utils.Optional[string] `json:"laneId"`
+	AfterID    *string                `json:"afterId"`
+	BeforeID   *string                `json:"beforeId"`
+}
+
+// UpdateKanbanLaneInput renames a lane or changes its color, a null color
+// removes it
+type UpdateKanbanLaneInput struct {
+	Name  *string                `json:"name" binding:"omitempty,max=50"`
+	Color utils.Optional[string] `json:"color"`
+}
+
+// MoveKanbanLaneInput places a lane after or before another one
+type MoveKanbanLaneInput struct {
+	AfterID  *string `json:"afterId"`
+	BeforeID *string `json:"beforeId"`
+}
+
+// MoveKanbanCategoryInput places a category after or before another one
+type MoveKanbanCategoryInput struct {
+	AfterID  *string `json:"afterId"`
+	BeforeID *string `json:"beforeId"`
+}
+
+type UpdateKanbanLabelInput struct {
+	Name  *string `json:"name" binding:"omitempty,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type UpdateKanbanCommentInput struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// UpdateKanbanChecklistEntryInput is a partial update, assignee and due date
+// are cleared when sent as null
+type UpdateKanbanChecklistEntryInput struct {
+	Title      *string                   `json:"title" binding:"omitempty,max=200"`
+	Done       *bool                     `json:"done"`
+	AssigneeID utils.Optional[string]    `json:"assigneeId"`
+	DueDate    utils.Optional[time.Time] `json:"dueDate"`
+}
+
+type MoveKanbanChecklistEntryInput struct {
+	AfterID  *string `json:"afterId"`
+	BeforeID *string `json:"beforeId"`
+}
+
+type UpdateKanbanResponse struct {
+	Kanban repository.Kanban `json:"kanban"`
+}
+
+// MoveKanbanItemResponse is the moved item with any board rules the move broke
+// that the board only warns about
+type MoveKanbanItemResponse struct {
+	Item     repository.KanbanItem `json:"item"`
+	Warnings []utils.APIError      `json:"warnings"`
+}
+
+// BulkKanbanItemsInput applies one action to a set of cards at once, either
+// every card changes or none does. Move appends the cards to the category in
+// the given order, label and assign name the label or user to add or remove
+// and delete removes cards that are already archived.
+type BulkKanbanItemsInput struct {
+	Action     KanbanBulkAction `json:"action" binding:"required,oneof=move label unlabel assign unassign archive delete"`
+	ItemIDs    []string         `json:"itemIds" binding:"required,min=1,max=100,unique,dive,required"`
+	CategoryID *string          `json:"categoryId"`
+	LabelID    *string          `json:"labelId"`
+	UserID     *string          `json:"userId"`
+}
+
+// BulkKanbanItemsResponse is the changed cards with any board rules a bulk
+// move broke that the board only warns about
+type BulkKanbanItemsResponse struct {
+	Items    []repository.KanbanItem `json:"items"`
+	Warnings []utils.APIError        `json:"warnings"`
+}
+
+// UpdateKanbanRuleInput is a partial update, a null category makes the rule
+// apply to every column and sent actions replace the old ones
+type UpdateKanbanRuleInput struct {
+	Name       *string                `json:"name" binding:"omitempty,max=100"`
+	Enabled    *bool                  `json:"enabled"`
+	Event      *KanbanRuleEvent       `json:"event" binding:"omitempty,oneof=item.created item.moved item.overdue"`
+	CategoryID utils.Optional[string] `json:"categoryId"`
+	Actions    *[]KanbanRuleAction    `json:"actions" binding:"omitempty,min=1,max=10,dive"`
+}
+
+// UpdateKanbanRecurrenceInput is a partial update, changing the rule or its
+// start reschedules the next card
+type UpdateKanbanRecurrenceInput struct {
+	CategoryID    *string                   `json:"categoryId"`
+	Title         *string                   `json:"title" binding:"omitempty,max=40"`
+	Description   utils.Optional[string]    `json:"description"`
+	Priority      *KanbanPriority           `json:"priority" binding:"omitempty,oneof=Extreme High Medium Low None"`
+	EstimatedTime utils.Optional[int32]     `json:"estimatedTime"`
+	RRule         *string                   `json:"rrule" binding:"omitempty,max=100"`
+	StartsAt      *time.Time                `json:"startsAt"`
+	Until         utils.Optional[time.Time] `json:"until"`
+	Enabled       *bool                     `json:"enabled"`
+}
@@ -2,17 +2,26 @@ package dto
 
 import (
 	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
 )
 
+// UpdateOrganisationInput is a partial update, archived kanban categories and
+// cards are purged after KanbanArchiveRetentionDays and kept forever when it
+// is sent as null. AttachmentMaxSize lowers the server upload limit in bytes
+// and AttachmentTypes limits uploads to MIME types like image/png or image/*,
+// null lifts either limit.
 type UpdateOrganisationInput struct {
-	Name          *string `json:"name"`
-	Description   *string `json:"description"`
-	Website       *string `json:"website"`
-	LogoUrl       *string `json:"logoUrl"`
-	Location      *string `json:"location"`
-	Timezone      *string `json:"timezone"`
-	IsActive      *bool   `json:"isActive"`
-	DefaultRoleID *string `json:"defaultRoleId"`
+	Name                       *string                  `json:"name"`
+	Description                *string                  `json:"description"`
+	Website                    *string                  `json:"website"`
+	LogoUrl                    *string                  `json:"logoUrl"`
+	Location                   *string                  `json:"location"`
+	Timezone                   *string                  `json:"timezone"`
+	IsActive                   *bool                    `json:"isActive"`
+	DefaultRoleID              *string                  `json:"defaultRoleId"`
+	KanbanArchiveRetentionDays utils.Optional[int32]    `json:"kanbanArchiveRetentionDays"`
+	AttachmentMaxSize          utils.Optional[int64]    `json:"attachmentMaxSize"`
+	AttachmentTypes            utils.Optional[[]string] `json:"attachmentTypes"`
 }
 
 type UpdateOrganisationResponse struct {
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+
+type UpdateProjectInput struct {
+	Name *string `json:"name" binding:"omitempty,max=255"`
+}
+
+type UpdateProjectStatusInput struct {
+	Status ProjectStatus `json:"status" binding:"required,oneof=Active Completed Archived"`
+}
+
+type UpdateProjectMemberInput struct {
+	Role ProjectRole `json:"role" binding:"required,oneof=Admin Edit View"`
+}
+
+type UpdateProjectResponse struct {
+	Project repository.Project `json:"project"`
+}
+
+type UpdateProjectMemberResponse struct {
+	Member repository.ProjectMember `json:"member"`
+}
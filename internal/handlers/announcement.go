@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AnnouncementHandlerServices struct {
+	Announcement *services.AnnouncementService
+	Checker      *services.Checker
+}
+
+type AnnouncementHandler struct {
+	services *AnnouncementHandlerServices
+	cfg      *config.EnvConfig
+}
+
+// Create a new announcement handler
+func NewAnnouncementHandler(services AnnouncementHandlerServices, cfg *config.EnvConfig) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		services: &services,
+		cfg:      cfg,
+	}
+}
+
+func (h *AnnouncementHandler) Routes(rg *gin.RouterGroup) {
+	announcements := rg.Group("/organisations/:id/announcements")
+	announcements.Use(middleware.AuthMiddleware(h.cfg))
+
+	view := middleware.RequirePermission(h.services.Checker, permissions.AnnouncementView)
+
+	announcements.GET("", view, h.GetAll)
+	announcements.GET("/unread", view, h.GetUnread)
+	announcements.POST("/read", view, h.MarkAllRead)
+	announcements.POST("", middleware.RequirePermission(h.services.Checker, permissions.AnnouncementCreate), h.Create)
+	announcements.GET("/:announcementId", view, h.Get)
+	announcements.POST("/:announcementId/read", view, h.MarkRead)
+	announcements.PUT("/:announcementId", middleware.RequirePermission(h.services.Checker, permissions.AnnouncementEdit), h.Update)
+	announcements.DELETE("/:announcementId", middleware.RequirePermission(h.services.Checker, permissions.AnnouncementDelete), h.Delete)
+}
+
+// POST /organisations/{id}/announcements
+func (h *AnnouncementHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	var body dto.CreateAnnouncementInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("trying to create announcement")
+
+	announcement, err := h.services.Announcement.Create(ctx, orgID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create announcement")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("announcement successfully created")
+	c.JSON(http.StatusCreated, dto.CreateAnnouncementResponse{
+		Announcement: *announcement,
+	})
+}
+
+// GET /organisations/{id}/announcements
+func (h *AnnouncementHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 10)
+	offset := (page - 1) * limit
+
+	// Scheduled and expired announcements are only visible to editors
+	includeUnpublished := utils.ParseBoolDefault(c.Query("all"), false)
+	if includeUnpublished {
+		if err := h.services.Checker.Check(ctx, userID, orgID, permissions.AnnouncementEdit); err != nil {
+			logger.WithError(err).Warn("user not allowed to see unpublished announcements")
+			c.Error(err)
+			return
+		}
+	}
+
+	logger.Info("trying to fetch announcements")
+
+	list, err := h.services.Announcement.List(ctx, orgID, userID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}, includeUnpublished)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get announcements")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("announcements successfully fetched")
+	c.JSON(http.StatusOK, dto.GetAnnouncementsResponse{
+		Announcements: list,
+		Page:          page,
+		Limit:         limit,
+	})
+}
+
+// GET /organisations/{id}/announcements/unread
+func (h *AnnouncementHandler) GetUnread(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	list, err := h.services.Announcement.Unread(ctx, orgID, userID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get unread announcements")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetUnreadAnnouncementsResponse{
+		Announcements: list,
+		Count:         len(list),
+	})
+}
+
+// GET /organisations/{id}/announcements/{announcementId}
+func (h *AnnouncementHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	announcementID := c.Param("announcementId")
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": announcementID,
+	})
+
+	// Scheduled and expired announcements are only visible to editors
+	editor := h.services.Checker.Check(ctx, utils.GetUserID(c), orgID, permissions.AnnouncementEdit) == nil
+
+	announcement, err := h.services.Announcement.Get(ctx, announcementID, orgID, editor)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get announcement")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetAnnouncementResponse{
+		Announcement: *announcement,
+	})
+}
+
+// PUT /organisations/{id}/announcements/{announcementId}
+func (h *AnnouncementHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	announcementID := c.Param("announcementId")
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": announcementID,
+	})
+
+	var body dto.UpdateAnnouncementInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to parse json")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("attempting to update announcement")
+
+	updated, err := h.services.Announcement.Update(ctx, announcementID, orgID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update announcement")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("announcement updated successfully")
+	c.JSON(http.StatusOK, dto.UpdateAnnouncementResponse{
+		Announcement: *updated,
+	})
+}
+
+// DELETE /organisations/{id}/announcements/{announcementId}
+func (h *AnnouncementHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	announcementID := c.Param("announcementId")
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": announcementID,
+	})
+
+	if err := h.services.Announcement.Delete(ctx, announcementID, orgID); err != nil {
+		logger.WithError(err).Warn("failed to delete announcement")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("announcement deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// POST /organisations/{id}/announcements/{announcementId}/read
+func (h *AnnouncementHandler) MarkRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+	announcementID := c.Param("announcementId")
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"user_id":         userID,
+		"announcement_id": announcementID,
+	})
+
+	// Scheduled and expired announcements are only visible to editors
+	editor := h.services.Checker.Check(ctx, userID, orgID, permissions.AnnouncementEdit) == nil
+
+	if err := h.services.Announcement.MarkRead(ctx, announcementID, orgID, userID, editor); err != nil {
+		logger.WithError(err).Warn("failed to mark announcement as read")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /organisations/{id}/announcements/read
+func (h *AnnouncementHandler) MarkAllRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	marked, err := h.services.Announcement.MarkAllRead(ctx, orgID, userID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to mark announcements as read")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MarkAnnouncementsReadResponse{
+		Marked: marked,
+	})
+}
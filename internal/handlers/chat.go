@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ChatHandler struct {
+	service *services.ChatService
+	cfg     *config.EnvConfig
+}
+
+// Create a new chat handler
+func NewChatHandler(service *services.ChatService, cfg *config.EnvConfig) *ChatHandler {
+	return &ChatHandler{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+// Rooms are checked against the organisation and their members by the
+// service, so the routes need no permission
+func (h *ChatHandler) Routes(rg *gin.RouterGroup) {
+	chats := rg.Group("/organisations/:id/chats")
+	chats.Use(middleware.AuthMiddleware(h.cfg))
+
+	chats.GET("", h.GetAll)
+	chats.POST("", h.Create)
+	chats.GET("/:chatId/messages", h.GetMessages)
+	chats.POST("/:chatId/messages", h.SendMessage)
+	chats.POST("/:chatId/messages/:messageId/attachments", h.CreateAttachment)
+	chats.GET("/:chatId/messages/:messageId/attachments/:attachmentId", h.GetAttachment)
+	chats.DELETE("/:chatId/messages/:messageId/attachments/:attachmentId", h.DeleteAttachment)
+}
+
+// GET /organisations/{id}/chats
+func (h *ChatHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	rooms, err := h.service.Rooms(ctx, orgID, userID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get chats")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetChatRoomsResponse{
+		Rooms: rooms,
+	})
+}
+
+// POST /organisations/{id}/chats
+func (h *ChatHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	var body dto.CreateChatRoomInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	room, err := h.service.CreateRoom(ctx, orgID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create chat")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ChatRoomResponse{
+		Room: *room,
+	})
+}
+
+// GET /organisations/{id}/chats/{chatId}/messages?page=1&limit=50
+func (h *ChatHandler) GetMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	chatID := c.Param("chatId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"chat_id": chatID,
+	})
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 50)
+	offset := (page - 1) * limit
+
+	messages, err := h.service.Messages(ctx, orgID, chatID, userID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get messages")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetChatMessagesResponse{
+		Messages: messages,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// POST /organisations/{id}/chats/{chatId}/messages
+func (h *ChatHandler) SendMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	chatID := c.Param("chatId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"chat_id": chatID,
+	})
+
+	var body dto.CreateChatMessageInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	message, err := h.service.SendMessage(ctx, orgID, chatID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to send message")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ChatMessageResponse{
+		Message: *message,
+	})
+}
+
+// POST /organisations/{id}/chats/{chatId}/messages/{messageId}/attachments
+//
+// Multipart form with the upload in "file"
+func (h *ChatHandler) CreateAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	chatID := c.Param("chatId")
+	messageID := c.Param("messageId")
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"chat_id":    chatID,
+		"message_id": messageID,
+	})
+
+	// Room for the multipart framing, the service enforces the exact limit
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.AttachmentMaxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger.WithError(err).Warn("missing attachment file")
+		c.Error(utils.NewFieldError("file", "is required"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(utils.NewError(http.StatusBadRequest, "failed to read file", err))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.CreateAttachment(ctx, orgID, chatID, messageID, utils.GetUserID(c), header.Filename, file)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create attachment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ChatAttachmentResponse{
+		Attachment: *attachment,
+	})
+}
+
+// GET /organisations/{id}/chats/{chatId}/messages/{messageId}/attachments/{attachmentId}
+//
+// Answers with a signed download link that expires after a while
+func (h *ChatHandler) GetAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	chatID := c.Param("chatId")
+	messageID := c.Param("messageId")
+	attachmentID := c.Param("attachmentId")
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":        orgID,
+		"chat_id":       chatID,
+		"message_id":    messageID,
+		"attachment_id": attachmentID,
+	})
+
+	download, err := h.service.DownloadAttachment(ctx, orgID, chatID, messageID, attachmentID, utils.GetUserID(c))
+	if err != nil {
+		logger.WithError(err).Warn("failed to get attachment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, download)
+}
+
+// DELETE /organisations/{id}/chats/{chatId}/messages/{messageId}/attachments/{attachmentId}
+func (h *ChatHandler) DeleteAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	chatID := c.Param("chatId")
+	messageID := c.Param("messageId")
+	attachmentID := c.Param("attachmentId")
+
+	logger := logging.WithLayer(ctx, "handler", "chat").WithFields(logrus.Fields{
+		"org_id":        orgID,
+		"chat_id":       chatID,
+		"message_id":    messageID,
+		"attachment_id": attachmentID,
+	})
+
+	if err := h.service.DeleteAttachment(ctx, orgID, chatID, messageID, attachmentID, utils.GetUserID(c)); err != nil {
+		logger.WithError(err).Warn("failed to delete attachment")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
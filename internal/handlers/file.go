@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/storage"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves files kept in local storage. The route is public, the
+// signed link handed out by the attachment endpoints is the authorisation.
+type FileHandler struct {
+	local *storage.Local
+}
+
+// Create a new file handler, local is nil when files live elsewhere
+func NewFileHandler(local *storage.Local) *FileHandler {
+	return &FileHandler{
+		local: local,
+	}
+}
+
+func (h *FileHandler) Routes(rg *gin.RouterGroup) {
+	if h.local == nil {
+		return
+	}
+	rg.GET("/files/*key", h.Download)
+}
+
+// GET /files/{key}?name&type&expires&signature
+func (h *FileHandler) Download(c *gin.Context) {
+	ctx := c.Request.Context()
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	logger := logging.WithLayer(ctx, "handler", "file").WithField("key", key)
+
+	opts, err := h.local.Verify(key, c.Request.URL.Query())
+	if err != nil {
+		logger.WithError(err).Warn("refused file download")
+		c.Error(utils.NewError(http.StatusForbidden, "invalid or expired link", err))
+		return
+	}
+
+	file, err := h.local.Open(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.Error(utils.NewError(http.StatusNotFound, "file not found", err))
+			return
+		}
+		logger.WithError(err).Error("failed to open file")
+		c.Error(utils.NewError(http.StatusInternalServerError, "failed to open file", err))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		c.Error(utils.NewError(http.StatusInternalServerError, "failed to open file", err))
+		return
+	}
+
+	c.Header("Content-Type", opts.ContentType)
+	c.Header("Content-Disposition", h.local.ContentDisposition(opts.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), file)
+}
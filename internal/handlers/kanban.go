@@ -0,0 +1,711 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type KanbanHandlerServices struct {
+	Kanban  *services.KanbanService
+	Checker *services.Checker
+}
+
+type KanbanHandler struct {
+	services *KanbanHandlerServices
+	cfg      *config.EnvConfig
+}
+
+// Create a new kanban handler
+func NewKanbanHandler(services KanbanHandlerServices, cfg *config.EnvConfig) *KanbanHandler {
+	return &KanbanHandler{
+		services: &services,
+		cfg:      cfg,
+	}
+}
+
+func (h *KanbanHandler) Routes(rg *gin.RouterGroup) {
+	kanbans := rg.Group("/projects/:id/kanbans")
+	kanbans.Use(middleware.AuthMiddleware(h.cfg))
+
+	view := middleware.RequireProjectPermission(h.services.Checker, permissions.KanbanView)
+	create := middleware.RequireProjectPermission(h.services.Checker, permissions.KanbanCreate)
+	edit := middleware.RequireProjectPermission(h.services.Checker, permissions.KanbanEdit)
+	remove := middleware.RequireProjectPermission(h.services.Checker, permissions.KanbanDelete)
+
+	kanbans.GET("", view, h.GetAll)
+	kanbans.POST("", create, h.Create)
+	kanbans.POST("/import", create, h.ImportKanban)
+	kanbans.GET("/imports/:importId/report", view, h.GetImportReport)
+	kanbans.GET("/:kanbanId", view, h.Get)
+	kanbans.PUT("/:kanbanId", edit, h.Update)
+	kanbans.DELETE("/:kanbanId", remove, h.Delete)
+	kanbans.GET("/:kanbanId/archive", view, h.GetArchive)
+	kanbans.GET("/:kanbanId/activity", view, h.GetActivity)
+	kanbans.GET("/:kanbanId/metrics", view, h.GetMetrics)
+	kanbans.GET("/:kanbanId/export", view, h.ExportKanban)
+
+	// Automation rules, they act on the board with their own authority
+	rules := kanbans.Group("/:kanbanId/rules")
+	rules.GET("", view, h.GetRules)
+	rules.POST("", edit, h.CreateRule)
+	rules.PUT("/:ruleId", edit, h.UpdateRule)
+	rules.DELETE("/:ruleId", edit, h.DeleteRule)
+
+	// Recurring cards, created on schedule into a category
+	recurrences := kanbans.Group("/:kanbanId/recurrences")
+	recurrences.GET("", view, h.GetRecurrences)
+	recurrences.POST("", create, h.CreateRecurrence)
+	recurrences.PUT("/:recurrenceId", edit, h.UpdateRecurrence)
+	recurrences.DELETE("/:recurrenceId", edit, h.DeleteRecurrence)
+
+	// Categories
+	categories := kanbans.Group("/:kanbanId/categories")
+	categories.POST("", create, h.CreateCategory)
+	categories.PUT("/:categoryId", edit, h.UpdateCategory)
+	categories.DELETE("/:categoryId", edit, h.DeleteCategory)
+	categories.PUT("/:categoryId/move", edit, h.MoveCategory)
+	categories.POST("/:categoryId/restore", edit, h.RestoreCategory)
+	categories.DELETE("/:categoryId/permanent", remove, h.PermaDeleteCategory)
+
+	// Swimlanes, cards without a lane are shown below them
+	lanes := kanbans.Group("/:kanbanId/lanes")
+	lanes.GET("", view, h.GetLanes)
+	lanes.POST("", edit, h.CreateLane)
+	lanes.PUT("/:laneId", edit, h.UpdateLane)
+	lanes.PUT("/:laneId/move", edit, h.MoveLane)
+	lanes.DELETE("/:laneId", edit, h.DeleteLane)
+
+	// Items
+	items := kanbans.Group("/:kanbanId/items")
+	items.POST("", create, h.CreateItem)
+	items.POST("/bulk", edit, h.BulkItems)
+	items.PUT("/:itemId", edit, h.UpdateItem)
+	items.PUT("/:itemId/move", edit, h.MoveItem)
+	items.DELETE("/:itemId", edit, h.DeleteItem)
+	items.POST("/:itemId/restore", edit, h.RestoreItem)
+	items.DELETE("/:itemId/permanent", remove, h.PermaDeleteItem)
+	items.GET("/:itemId", view, h.GetItem)
+	items.POST("/:itemId/assignees", edit, h.AddAssignee)
+	items.DELETE("/:itemId/assignees/:userId", edit, h.RemoveAssignee)
+	items.POST("/:itemId/watchers", view, h.AddWatcher)
+	items.DELETE("/:itemId/watchers/:userId", view, h.RemoveWatcher)
+	items.POST("/:itemId/labels", edit, h.AddItemLabel)
+	items.DELETE("/:itemId/labels/:labelId", edit, h.RemoveItemLabel)
+
+	// Item links, the other item may be on any kanban of the project
+	items.GET("/:itemId/links", view, h.GetItemLinks)
+	items.POST("/:itemId/links", edit, h.CreateItemLink)
+	items.DELETE("/:itemId/links/:linkId", edit, h.DeleteItemLink)
+
+	// Item attachments, uploaders delete their own and moderators need delete permission
+	items.GET("/:itemId/attachments", view, h.GetAttachments)
+	items.POST("/:itemId/attachments", edit, h.CreateAttachment)
+	items.GET("/:itemId/attachments/:attachmentId", view, h.GetAttachment)
+	items.DELETE("/:itemId/attachments/:attachmentId", edit, h.DeleteAttachment)
+
+	// Item history, reverting only restores the content of the card
+	items.GET("/:itemId/history", view, h.GetItemHistory)
+	items.POST("/:itemId/history/:activityId/revert", edit, h.RevertItem)
+
+	// Item time tracking, users log their own time and moderators need delete permission
+	items.GET("/:itemId/time", view, h.GetItemTime)
+	items.POST("/:itemId/time", edit, h.CreateTimeEntry)
+	items.POST("/:itemId/time/start", edit, h.StartTimer)
+	items.POST("/:itemId/time/stop", edit, h.StopTimer)
+	items.DELETE("/:itemId/time/:entryId", edit, h.DeleteTimeEntry)
+
+	// Item checklists
+	items.GET("/:itemId/checklist", view, h.GetChecklist)
+	items.POST("/:itemId/checklist", edit, h.CreateChecklistEntry)
+	items.PUT("/:itemId/checklist/:entryId", edit, h.UpdateChecklistEntry)
+	items.PUT("/:itemId/checklist/:entryId/move", edit, h.MoveChecklistEntry)
+	items.DELETE("/:itemId/checklist/:entryId", edit, h.DeleteChecklistEntry)
+	items.POST("/:itemId/checklist/:entryId/promote", create, h.PromoteChecklistEntry)
+
+	// Item comments, authors edit their own and moderators need delete permission
+	items.GET("/:itemId/comments", view, h.GetComments)
+	items.POST("/:itemId/comments", create, h.CreateComment)
+	items.PUT("/:itemId/comments/:commentId", view, h.UpdateComment)
+	items.DELETE("/:itemId/comments/:commentId", view, h.DeleteComment)
+	items.GET("/:itemId/comments/:commentId/history", view, h.GetCommentHistory)
+
+	// Kanban labels
+	labels := kanbans.Group("/:kanbanId/labels")
+	labels.GET("", view, h.GetBoardLabels)
+	labels.POST("", edit, h.CreateBoardLabel)
+	labels.PUT("/:labelId", edit, h.UpdateBoardLabel)
+	labels.DELETE("/:labelId", edit, h.DeleteBoardLabel)
+
+	// Items across all kanbans of a project
+	projectItems := rg.Group("/projects/:id/items")
+	projectItems.Use(middleware.AuthMiddleware(h.cfg))
+	projectItems.GET("", view, h.GetProjectItems)
+
+	// Time logged across all kanbans of a project
+	projectTime := rg.Group("/projects/:id/time")
+	projectTime.Use(middleware.AuthMiddleware(h.cfg))
+	projectTime.GET("", view, h.GetTimeReport)
+	projectTime.GET("/export", view, h.ExportTime)
+
+	// Organisation labels
+	orgLabels := rg.Group("/organisations/:id/labels")
+	orgLabels.Use(middleware.AuthMiddleware(h.cfg))
+	orgLabels.GET("", middleware.RequirePermission(h.services.Checker, permissions.KanbanView), h.GetOrganisationLabels)
+	orgLabels.POST("", middleware.RequirePermission(h.services.Checker, permissions.KanbanEdit), h.CreateOrganisationLabel)
+	orgLabels.PUT("/:labelId", middleware.RequirePermission(h.services.Checker, permissions.KanbanEdit), h.UpdateOrganisationLabel)
+	orgLabels.DELETE("/:labelId", middleware.RequirePermission(h.services.Checker, permissions.KanbanEdit), h.DeleteOrganisationLabel)
+}
+
+// POST /projects/{id}/kanbans
+func (h *KanbanHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	var body dto.CreateKanbanInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	logger.Info("trying to create kanban")
+
+	board, err := h.services.Kanban.Create(ctx, projectID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create kanban")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("kanban successfully created")
+	c.JSON(http.StatusCreated, dto.CreateKanbanResponse{
+		Board: *board,
+	})
+}
+
+// GET /projects/{id}/kanbans
+func (h *KanbanHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	kanbans, err := h.services.Kanban.List(ctx, projectID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get kanbans")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbansResponse{
+		Kanbans: kanbans,
+	})
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}
+//
+// lanes=assignee or lanes=priority groups the cards by assignee or priority
+// instead of the custom lanes
+func (h *KanbanHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	laneBy := dto.KanbanLaneGrouping(c.DefaultQuery("lanes", string(dto.LaneByCustom)))
+	switch laneBy {
+	case dto.LaneByCustom, dto.LaneByAssignee, dto.LaneByPriority:
+	default:
+		c.Error(utils.NewFieldError("lanes", "must be one of lane, assignee or priority"))
+		return
+	}
+
+	board, err := h.services.Kanban.Board(ctx, projectID, kanbanID, laneBy)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get kanban")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, board.Kanban.Version)
+	c.JSON(http.StatusOK, dto.GetKanbanResponse{
+		Board: *board,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}
+func (h *KanbanHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.UpdateKanbanInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	kanban, err := h.services.Kanban.Update(ctx, projectID, kanbanID, body, expectedVersion)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update kanban")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, kanban.Version)
+	c.JSON(http.StatusOK, dto.UpdateKanbanResponse{
+		Kanban: *kanban,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}
+func (h *KanbanHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if err := h.services.Kanban.Delete(ctx, projectID, kanbanID); err != nil {
+		logger.WithError(err).Warn("failed to delete kanban")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("kanban deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/archive?page=1&limit=50
+func (h *KanbanHandler) GetArchive(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), dto.DefaultKanbanArchiveLimit)
+	offset := (page - 1) * limit
+
+	archive, err := h.services.Kanban.Archive(ctx, projectID, kanbanID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get kanban archive")
+		c.Error(err)
+		return
+	}
+
+	archive.Page = page
+	archive.Limit = limit
+	c.JSON(http.StatusOK, archive)
+}
+
+// Categories
+
+// POST /projects/{id}/kanbans/{kanbanId}/categories
+func (h *KanbanHandler) CreateCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanCategoryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	category, err := h.services.Kanban.CreateCategory(ctx, projectID, kanbanID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create category")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanCategoryResponse{
+		Category: *category,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/categories/{categoryId}
+func (h *KanbanHandler) UpdateCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	categoryID := c.Param("categoryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var body dto.UpdateKanbanCategoryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	category, err := h.services.Kanban.UpdateCategory(ctx, projectID, kanbanID, categoryID, body, expectedVersion)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update category")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, dto.KanbanCategoryResponse{
+		Category: *category,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/categories/{categoryId}/move
+func (h *KanbanHandler) MoveCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	categoryID := c.Param("categoryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var body dto.MoveKanbanCategoryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	category, err := h.services.Kanban.MoveCategory(ctx, projectID, kanbanID, categoryID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to move category")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanCategoryResponse{
+		Category: *category,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/categories/{categoryId}
+func (h *KanbanHandler) DeleteCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	categoryID := c.Param("categoryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	category, err := h.services.Kanban.DeleteCategory(ctx, projectID, kanbanID, categoryID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to archive category")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanCategoryResponse{
+		Category: *category,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/categories/{categoryId}/restore
+func (h *KanbanHandler) RestoreCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	categoryID := c.Param("categoryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	category, err := h.services.Kanban.RestoreCategory(ctx, projectID, kanbanID, categoryID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to restore category")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanCategoryResponse{
+		Category: *category,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/categories/{categoryId}/permanent
+func (h *KanbanHandler) PermaDeleteCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	categoryID := c.Param("categoryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	if err := h.services.Kanban.PermaDeleteCategory(ctx, projectID, kanbanID, categoryID); err != nil {
+		logger.WithError(err).Warn("failed to delete category")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("category permanently deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// Items
+
+// POST /projects/{id}/kanbans/{kanbanId}/items
+func (h *KanbanHandler) CreateItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanItemInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	item, err := h.services.Kanban.CreateItem(ctx, projectID, kanbanID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create item")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanItemResponse{
+		Item: *item,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/items/{itemId}
+func (h *KanbanHandler) UpdateItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.UpdateKanbanItemInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	item, err := h.services.Kanban.UpdateItem(ctx, projectID, kanbanID, itemID, body, expectedVersion)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update item")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, item.Version)
+	c.JSON(http.StatusOK, dto.KanbanItemResponse{
+		Item: *item,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/items/{itemId}/move
+func (h *KanbanHandler) MoveItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.MoveKanbanItemInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	result, err := h.services.Kanban.MoveItem(ctx, projectID, kanbanID, itemID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to move item")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}
+func (h *KanbanHandler) DeleteItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	item, err := h.services.Kanban.DeleteItem(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to archive item")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanItemResponse{
+		Item: *item,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/restore
+func (h *KanbanHandler) RestoreItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	item, err := h.services.Kanban.RestoreItem(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to restore item")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanItemResponse{
+		Item: *item,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/permanent
+func (h *KanbanHandler) PermaDeleteItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if err := h.services.Kanban.PermaDeleteItem(ctx, projectID, kanbanID, itemID); err != nil {
+		logger.WithError(err).Warn("failed to delete item")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("item permanently deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// ifMatchVersion reads the version a client expects to update from the
+// If-Match header, nil when the header is missing or matches anything
+func ifMatchVersion(c *gin.Context) (*int32, error) {
+	tag := strings.TrimSpace(c.GetHeader("If-Match"))
+	if tag == "" || tag == "*" {
+		return nil, nil
+	}
+	tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+	version, err := strconv.ParseInt(tag, 10, 32)
+	if err != nil {
+		return nil, utils.NewError(http.StatusBadRequest, "invalid If-Match header", err)
+	}
+	v := int32(version)
+	return &v, nil
+}
+
+// setETag tags a response with the version of the returned resource
+func setETag(c *gin.Context, version int32) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/activity
+func (h *KanbanHandler) GetActivity(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 20)
+	offset := (page - 1) * limit
+
+	activity, err := h.services.Kanban.Activity(ctx, projectID, kanbanID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get activity")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanActivityResponse{
+		Activity: activity,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/history
+func (h *KanbanHandler) GetItemHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 20)
+	offset := (page - 1) * limit
+
+	history, err := h.services.Kanban.ItemHistory(ctx, projectID, kanbanID, itemID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get item history")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanActivityResponse{
+		Activity: history,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/history/{activityId}/revert
+func (h *KanbanHandler) RevertItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	activityID := c.Param("activityId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"activity_id": activityID,
+	})
+
+	item, err := h.services.Kanban.RevertItem(ctx, projectID, kanbanID, itemID, activityID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to revert item")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanItemResponse{Item: *item})
+}
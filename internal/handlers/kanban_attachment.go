@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/attachments
+func (h *KanbanHandler) GetAttachments(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	attachments, err := h.services.Kanban.Attachments(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get attachments")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanAttachmentsResponse{
+		Attachments: attachments,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/attachments
+//
+// Multipart form with the upload in "file"
+func (h *KanbanHandler) CreateAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	// Room for the multipart framing, the service enforces the exact limit
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.AttachmentMaxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger.WithError(err).Warn("missing attachment file")
+		c.Error(utils.NewFieldError("file", "is required"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(utils.NewError(http.StatusBadRequest, "failed to read file", err))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.services.Kanban.CreateAttachment(ctx, utils.GetOrgID(c), projectID, kanbanID, itemID, header.Filename, file)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create attachment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanAttachmentResponse{
+		Attachment: *attachment,
+	})
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/attachments/{attachmentId}
+//
+// Answers with a signed download link that expires after a while
+func (h *KanbanHandler) GetAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	attachmentID := c.Param("attachmentId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"item_id":       itemID,
+		"attachment_id": attachmentID,
+	})
+
+	download, err := h.services.Kanban.DownloadAttachment(ctx, projectID, kanbanID, itemID, attachmentID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get attachment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, download)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/attachments/{attachmentId}
+func (h *KanbanHandler) DeleteAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	attachmentID := c.Param("attachmentId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"item_id":       itemID,
+		"attachment_id": attachmentID,
+	})
+
+	// Users with delete permission moderate the attachments of others
+	moderator := h.services.Checker.Check(ctx, userID, utils.GetOrgID(c), permissions.KanbanDelete) == nil
+
+	if err := h.services.Kanban.DeleteAttachment(ctx, projectID, kanbanID, itemID, attachmentID, userID, moderator); err != nil {
+		logger.WithError(err).Warn("failed to delete attachment")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
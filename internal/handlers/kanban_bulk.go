@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/bulk
+func (h *KanbanHandler) BulkItems(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.BulkKanbanItemsInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	// Deleting cards for good needs the same right as a single permanent delete
+	if body.Action == dto.BulkDelete {
+		if err := h.services.Checker.Check(ctx, utils.GetUserID(c), utils.GetOrgID(c), permissions.KanbanDelete); err != nil {
+			logger.WithError(err).Warn("bulk delete refused")
+			c.Error(err)
+			return
+		}
+	}
+
+	result, err := h.services.Kanban.BulkItems(ctx, projectID, kanbanID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to apply bulk change")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}
+func (h *KanbanHandler) GetItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	item, err := h.services.Kanban.Item(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get item")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, item.Item.Version)
+	c.JSON(http.StatusOK, item)
+}
+
+// GET /projects/{id}/items?assignee=me&watcher=me&label={labelId}&kanban={kanbanId}
+func (h *KanbanHandler) GetProjectItems(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 20)
+	offset := (page - 1) * limit
+
+	filter := services.KanbanItemFilter{
+		KanbanID:   optionalQuery(c, "kanban"),
+		AssigneeID: resolveUser(c, optionalQuery(c, "assignee")),
+		WatcherID:  resolveUser(c, optionalQuery(c, "watcher")),
+		LabelID:    optionalQuery(c, "label"),
+	}
+
+	items, err := h.services.Kanban.ListProjectItems(ctx, projectID, filter, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get items")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanItemsResponse{
+		Items: items,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/assignees
+func (h *KanbanHandler) AddAssignee(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.KanbanItemUserInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	assignees, err := h.services.Kanban.AddAssignee(ctx, projectID, kanbanID, itemID, *resolveUser(c, &body.UserID))
+	if err != nil {
+		logger.WithError(err).Warn("failed to add assignee")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanItemAssigneesResponse{
+		Assignees: assignees,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/assignees/{userId}
+func (h *KanbanHandler) RemoveAssignee(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	userID := c.Param("userId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"assignee_id": userID,
+	})
+
+	assignees, err := h.services.Kanban.RemoveAssignee(ctx, projectID, kanbanID, itemID, *resolveUser(c, &userID))
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove assignee")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanItemAssigneesResponse{
+		Assignees: assignees,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/watchers
+//
+// Anyone who can view the kanban may watch an item, adding other users
+// requires edit permission
+func (h *KanbanHandler) AddWatcher(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.KanbanItemUserInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	watcherID := *resolveUser(c, &body.UserID)
+	if err := h.requireEditForOthers(c, watcherID); err != nil {
+		logger.WithError(err).Warn("user not allowed to add other watchers")
+		c.Error(err)
+		return
+	}
+
+	watchers, err := h.services.Kanban.AddWatcher(ctx, utils.GetOrgID(c), projectID, kanbanID, itemID, watcherID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to add watcher")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanItemWatchersResponse{
+		Watchers: watchers,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/watchers/{userId}
+func (h *KanbanHandler) RemoveWatcher(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	userID := c.Param("userId")
+	watcherID := *resolveUser(c, &userID)
+	if err := h.requireEditForOthers(c, watcherID); err != nil {
+		logger.WithError(err).Warn("user not allowed to remove other watchers")
+		c.Error(err)
+		return
+	}
+
+	watchers, err := h.services.Kanban.RemoveWatcher(ctx, projectID, kanbanID, itemID, watcherID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove watcher")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanItemWatchersResponse{
+		Watchers: watchers,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/labels
+func (h *KanbanHandler) AddItemLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.KanbanItemLabelInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	labels, err := h.services.Kanban.AddItemLabel(ctx, projectID, kanbanID, itemID, body.LabelID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to add label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanLabelsResponse{
+		Labels: labels,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/labels/{labelId}
+func (h *KanbanHandler) RemoveItemLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	labelID := c.Param("labelId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"label_id":   labelID,
+	})
+
+	labels, err := h.services.Kanban.RemoveItemLabel(ctx, projectID, kanbanID, itemID, labelID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanLabelsResponse{
+		Labels: labels,
+	})
+}
+
+// requireEditForOthers checks edit permission when acting on another user
+func (h *KanbanHandler) requireEditForOthers(c *gin.Context, targetID string) error {
+	userID := utils.GetUserID(c)
+	if targetID == userID {
+		return nil
+	}
+	return h.services.Checker.Check(c.Request.Context(), userID, utils.GetOrgID(c), permissions.KanbanEdit)
+}
+
+// optionalQuery returns a query param or nil when it is missing or empty
+func optionalQuery(c *gin.Context, key string) *string {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// resolveUser replaces the "me" alias with the authenticated user
+func resolveUser(c *gin.Context, userID *string) *string {
+	if userID == nil || *userID != "me" {
+		return userID
+	}
+	self := utils.GetUserID(c)
+	return &self
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist
+func (h *KanbanHandler) GetChecklist(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	checklist, err := h.services.Kanban.Checklist(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get checklist")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, checklist)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist
+func (h *KanbanHandler) CreateChecklistEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.CreateKanbanChecklistEntryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+	body.AssigneeID = resolveUser(c, body.AssigneeID)
+
+	entry, err := h.services.Kanban.CreateChecklistEntry(ctx, projectID, kanbanID, itemID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create checklist entry")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist/{entryId}
+func (h *KanbanHandler) UpdateChecklistEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	entryID := c.Param("entryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var body dto.UpdateKanbanChecklistEntryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+	body.AssigneeID.Value = resolveUser(c, body.AssigneeID.Value)
+
+	entry, err := h.services.Kanban.UpdateChecklistEntry(ctx, projectID, kanbanID, itemID, entryID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update checklist entry")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist/{entryId}/move
+func (h *KanbanHandler) MoveChecklistEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	entryID := c.Param("entryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var body dto.MoveKanbanChecklistEntryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	entry, err := h.services.Kanban.MoveChecklistEntry(ctx, projectID, kanbanID, itemID, entryID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to move checklist entry")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist/{entryId}
+func (h *KanbanHandler) DeleteChecklistEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	entryID := c.Param("entryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	if err := h.services.Kanban.DeleteChecklistEntry(ctx, projectID, kanbanID, itemID, entryID); err != nil {
+		logger.WithError(err).Warn("failed to delete checklist entry")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/checklist/{entryId}/promote
+func (h *KanbanHandler) PromoteChecklistEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	entryID := c.Param("entryId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	// The body is optional, without it the card lands next to its parent
+	var body dto.PromoteKanbanChecklistEntryInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			logger.WithError(err).Warn("invalid input provided")
+			c.Error(utils.NewValidationError(err))
+			return
+		}
+	}
+
+	result, err := h.services.Kanban.PromoteChecklistEntry(ctx, projectID, kanbanID, itemID, entryID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to promote checklist entry")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
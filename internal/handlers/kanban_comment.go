@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/comments
+func (h *KanbanHandler) GetComments(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	comments, err := h.services.Kanban.Comments(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get comments")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanCommentsResponse{
+		Comments: comments,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/comments
+func (h *KanbanHandler) CreateComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"user_id":    userID,
+	})
+
+	var body dto.CreateKanbanCommentInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	comment, err := h.services.Kanban.CreateComment(ctx, utils.GetOrgID(c), projectID, kanbanID, itemID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create comment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/items/{itemId}/comments/{commentId}
+func (h *KanbanHandler) UpdateComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	commentID := c.Param("commentId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	var body dto.UpdateKanbanCommentInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	comment, err := h.services.Kanban.UpdateComment(ctx, utils.GetOrgID(c), projectID, kanbanID, itemID, commentID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update comment")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/comments/{commentId}
+func (h *KanbanHandler) DeleteComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	commentID := c.Param("commentId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	// Users with delete permission moderate the comments of others
+	moderator := h.services.Checker.Check(ctx, userID, utils.GetOrgID(c), permissions.KanbanDelete) == nil
+
+	if err := h.services.Kanban.DeleteComment(ctx, projectID, kanbanID, itemID, commentID, userID, moderator); err != nil {
+		logger.WithError(err).Warn("failed to delete comment")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/comments/{commentId}/history
+func (h *KanbanHandler) GetCommentHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	commentID := c.Param("commentId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	revisions, err := h.services.Kanban.CommentHistory(ctx, projectID, kanbanID, itemID, commentID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get comment history")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanCommentHistoryResponse{
+		Revisions: revisions,
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/export?format=json&archived=true
+//
+// Formats are json, which can be imported again, csv and markdown
+func (h *KanbanHandler) ExportKanban(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	format := dto.KanbanExportFormat(c.DefaultQuery("format", string(dto.ExportJSON)))
+	switch format {
+	case dto.ExportJSON, dto.ExportCSV, dto.ExportMarkdown:
+	default:
+		c.Error(utils.NewFieldError("format", "must be one of json, csv or markdown"))
+		return
+	}
+	includeArchived := utils.ParseBoolDefault(c.Query("archived"), false)
+
+	export, err := h.services.Kanban.Export(ctx, projectID, kanbanID, format, includeArchived)
+	if err != nil {
+		logger.WithError(err).Warn("failed to export kanban")
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName()))
+	c.Status(http.StatusOK)
+
+	// The status is sent, a failure can only cut the download short
+	if err := export.Write(ctx, c.Writer); err != nil {
+		logger.WithError(err).Warn("failed to write kanban export")
+	}
+}
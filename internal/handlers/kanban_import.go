@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Largest file accepted by a board import
+const maxImportFileSize = 5 << 20
+
+// POST /projects/{id}/kanbans/import
+//
+// Multipart form with the board in "file", dryRun=true previews the import
+// without creating anything
+func (h *KanbanHandler) ImportKanban(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+1<<20)
+
+	var body dto.ImportKanbanInput
+	if err := c.ShouldBind(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger.WithError(err).Warn("missing import file")
+		c.Error(utils.NewFieldError("file", "is required"))
+		return
+	}
+	if header.Size > maxImportFileSize {
+		c.Error(utils.NewFieldError("file", fmt.Sprintf("must be at most %d MB", maxImportFileSize>>20)))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(utils.NewError(http.StatusBadRequest, "failed to read file", err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		c.Error(utils.NewError(http.StatusBadRequest, "failed to read file", err))
+		return
+	}
+
+	logger.Info("trying to import kanban")
+
+	result, err := h.services.Kanban.Import(ctx, utils.GetOrgID(c), projectID, body, header.Filename, data)
+	if err != nil {
+		logger.WithError(err).Warn("failed to import kanban")
+		c.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+// GET /projects/{id}/kanbans/imports/{importId}/report
+func (h *KanbanHandler) GetImportReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	importID := c.Param("importId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"import_id":  importID,
+	})
+
+	_, issues, err := h.services.Kanban.ImportReport(ctx, projectID, importID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to fetch import report")
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s.csv"`, importID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"row", "column", "title", "level", "message"})
+	for _, issue := range issues {
+		w.Write([]string{
+			strconv.Itoa(issue.Row),
+			utils.CSVCell(issue.Column),
+			utils.CSVCell(issue.Title),
+			string(issue.Level),
+			utils.CSVCell(issue.Message),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.WithError(err).Warn("failed to write import report")
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /organisations/{id}/labels
+func (h *KanbanHandler) GetOrganisationLabels(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("org_id", orgID)
+
+	labels, err := h.services.Kanban.OrganisationLabels(ctx, orgID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get labels")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanLabelsResponse{
+		Labels: labels,
+	})
+}
+
+// POST /organisations/{id}/labels
+func (h *KanbanHandler) CreateOrganisationLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("org_id", orgID)
+
+	var body dto.CreateKanbanLabelInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	label, err := h.services.Kanban.CreateOrganisationLabel(ctx, orgID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanLabelResponse{
+		Label: *label,
+	})
+}
+
+// PUT /organisations/{id}/labels/{labelId}
+func (h *KanbanHandler) UpdateOrganisationLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	labelID := c.Param("labelId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"org_id":   orgID,
+		"label_id": labelID,
+	})
+
+	var body dto.UpdateKanbanLabelInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	label, err := h.services.Kanban.UpdateOrganisationLabel(ctx, orgID, labelID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanLabelResponse{
+		Label: *label,
+	})
+}
+
+// DELETE /organisations/{id}/labels/{labelId}
+func (h *KanbanHandler) DeleteOrganisationLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	labelID := c.Param("labelId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"org_id":   orgID,
+		"label_id": labelID,
+	})
+
+	if err := h.services.Kanban.DeleteOrganisationLabel(ctx, orgID, labelID); err != nil {
+		logger.WithError(err).Warn("failed to delete label")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/labels
+func (h *KanbanHandler) GetBoardLabels(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	labels, err := h.services.Kanban.BoardLabels(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get labels")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanLabelsResponse{
+		Labels: labels,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/labels
+func (h *KanbanHandler) CreateBoardLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanLabelInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	label, err := h.services.Kanban.CreateBoardLabel(ctx, utils.GetOrgID(c), projectID, kanbanID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanLabelResponse{
+		Label: *label,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/labels/{labelId}
+func (h *KanbanHandler) UpdateBoardLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	labelID := c.Param("labelId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"label_id":   labelID,
+	})
+
+	var body dto.UpdateKanbanLabelInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	label, err := h.services.Kanban.UpdateBoardLabel(ctx, utils.GetOrgID(c), projectID, kanbanID, labelID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update label")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanLabelResponse{
+		Label: *label,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/labels/{labelId}
+func (h *KanbanHandler) DeleteBoardLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	labelID := c.Param("labelId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"label_id":   labelID,
+	})
+
+	if err := h.services.Kanban.DeleteBoardLabel(ctx, utils.GetOrgID(c), projectID, kanbanID, labelID); err != nil {
+		logger.WithError(err).Warn("failed to delete label")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/lanes
+func (h *KanbanHandler) GetLanes(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	lanes, err := h.services.Kanban.Lanes(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get lanes")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanLanesResponse{
+		Lanes: lanes,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/lanes
+func (h *KanbanHandler) CreateLane(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanLaneInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	lane, err := h.services.Kanban.CreateLane(ctx, projectID, kanbanID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create lane")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.KanbanLaneResponse{
+		Lane: *lane,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/lanes/{laneId}
+func (h *KanbanHandler) UpdateLane(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	laneID := c.Param("laneId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	var body dto.UpdateKanbanLaneInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	lane, err := h.services.Kanban.UpdateLane(ctx, projectID, kanbanID, laneID, body, expectedVersion)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update lane")
+		c.Error(err)
+		return
+	}
+
+	setETag(c, lane.Version)
+	c.JSON(http.StatusOK, dto.KanbanLaneResponse{
+		Lane: *lane,
+	})
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/lanes/{laneId}/move
+func (h *KanbanHandler) MoveLane(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	laneID := c.Param("laneId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	var body dto.MoveKanbanLaneInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	lane, err := h.services.Kanban.MoveLane(ctx, projectID, kanbanID, laneID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to move lane")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KanbanLaneResponse{
+		Lane: *lane,
+	})
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/lanes/{laneId}
+func (h *KanbanHandler) DeleteLane(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	laneID := c.Param("laneId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	if err := h.services.Kanban.DeleteLane(ctx, projectID, kanbanID, laneID); err != nil {
+		logger.WithError(err).Warn("failed to delete lane")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/links
+func (h *KanbanHandler) GetItemLinks(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	links, err := h.services.Kanban.ItemLinks(ctx, projectID, kanbanID, itemID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get links")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/links
+func (h *KanbanHandler) CreateItemLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.CreateKanbanItemLinkInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	link, err := h.services.Kanban.CreateItemLink(ctx, projectID, kanbanID, itemID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to link items")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/links/{linkId}
+func (h *KanbanHandler) DeleteItemLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	linkID := c.Param("linkId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"link_id":    linkID,
+	})
+
+	if err := h.services.Kanban.DeleteItemLink(ctx, projectID, kanbanID, itemID, linkID); err != nil {
+		logger.WithError(err).Warn("failed to delete link")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
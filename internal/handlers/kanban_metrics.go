@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Days covered by the metrics when no start is given
+const defaultMetricsDays = 30
+
+// GET /projects/{id}/kanbans/{kanbanId}/metrics?from=2024-01-01&to=2024-01-31
+func (h *KanbanHandler) GetMetrics(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.Error(utils.NewFieldError("to", "must be a date"))
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, 1-defaultMetricsDays)
+	if value := c.Query("from"); value != "" {
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.Error(utils.NewFieldError("from", "must be a date"))
+			return
+		}
+		from = t
+	}
+
+	metrics, err := h.services.Kanban.Metrics(ctx, projectID, kanbanID, from, to)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get metrics")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
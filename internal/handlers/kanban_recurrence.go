@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/recurrences
+func (h *KanbanHandler) GetRecurrences(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	recurrences, err := h.services.Kanban.Recurrences(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get recurrences")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanRecurrencesResponse{
+		Recurrences: recurrences,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/recurrences
+func (h *KanbanHandler) CreateRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanRecurrenceInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	recurrence, err := h.services.Kanban.CreateRecurrence(ctx, projectID, kanbanID, utils.GetUserID(c), body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create recurrence")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurrence)
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/recurrences/{recurrenceId}
+func (h *KanbanHandler) UpdateRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	recurrenceID := c.Param("recurrenceId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"recurrence_id": recurrenceID,
+	})
+
+	var body dto.UpdateKanbanRecurrenceInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	recurrence, err := h.services.Kanban.UpdateRecurrence(ctx, projectID, kanbanID, recurrenceID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update recurrence")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrence)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/recurrences/{recurrenceId}
+func (h *KanbanHandler) DeleteRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	recurrenceID := c.Param("recurrenceId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"recurrence_id": recurrenceID,
+	})
+
+	if err := h.services.Kanban.DeleteRecurrence(ctx, projectID, kanbanID, recurrenceID); err != nil {
+		logger.WithError(err).Warn("failed to delete recurrence")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
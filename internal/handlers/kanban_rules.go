@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/rules
+func (h *KanbanHandler) GetRules(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	rules, err := h.services.Kanban.Rules(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get rules")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetKanbanRulesResponse{
+		Rules: rules,
+	})
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/rules
+func (h *KanbanHandler) CreateRule(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var body dto.CreateKanbanRuleInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	rule, err := h.services.Kanban.CreateRule(ctx, projectID, kanbanID, utils.GetUserID(c), body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create rule")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// PUT /projects/{id}/kanbans/{kanbanId}/rules/{ruleId}
+func (h *KanbanHandler) UpdateRule(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	ruleID := c.Param("ruleId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"rule_id":    ruleID,
+	})
+
+	var body dto.UpdateKanbanRuleInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	rule, err := h.services.Kanban.UpdateRule(ctx, projectID, kanbanID, ruleID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update rule")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/rules/{ruleId}
+func (h *KanbanHandler) DeleteRule(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	ruleID := c.Param("ruleId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"rule_id":    ruleID,
+	})
+
+	if err := h.services.Kanban.DeleteRule(ctx, projectID, kanbanID, ruleID); err != nil {
+		logger.WithError(err).Warn("failed to delete rule")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
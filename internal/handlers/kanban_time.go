@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /projects/{id}/kanbans/{kanbanId}/items/{itemId}/time?user=me&from=2024-01-01&to=2024-01-31
+func (h *KanbanHandler) GetItemTime(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	filter, err := timeFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.services.Kanban.ItemTime(ctx, projectID, kanbanID, itemID, filter)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get item time")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/time
+func (h *KanbanHandler) CreateTimeEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.CreateKanbanTimeEntryInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewValidationError(err))
+		return
+	}
+
+	entry, err := h.services.Kanban.CreateTimeEntry(ctx, projectID, kanbanID, itemID, utils.GetUserID(c), body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to log time")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/time/start
+func (h *KanbanHandler) StartTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var body dto.StartKanbanTimerInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			logger.WithError(err).Warn("invalid input provided")
+			c.Error(utils.NewValidationError(err))
+			return
+		}
+	}
+
+	entry, err := h.services.Kanban.StartTimer(ctx, projectID, kanbanID, itemID, utils.GetUserID(c), body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to start timer")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// POST /projects/{id}/kanbans/{kanbanId}/items/{itemId}/time/stop
+func (h *KanbanHandler) StopTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	entry, err := h.services.Kanban.StopTimer(ctx, projectID, kanbanID, itemID, utils.GetUserID(c))
+	if err != nil {
+		logger.WithError(err).Warn("failed to stop timer")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DELETE /projects/{id}/kanbans/{kanbanId}/items/{itemId}/time/{entryId}
+func (h *KanbanHandler) DeleteTimeEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+	itemID := c.Param("itemId")
+	entryID := c.Param("entryId")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	// Users with delete permission moderate the time entries of others
+	moderator := h.services.Checker.Check(ctx, userID, utils.GetOrgID(c), permissions.KanbanDelete) == nil
+
+	if err := h.services.Kanban.DeleteTimeEntry(ctx, projectID, kanbanID, itemID, entryID, userID, moderator); err != nil {
+		logger.WithError(err).Warn("failed to delete time entry")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /projects/{id}/time?user=me&item={itemId}&from=2024-01-01&to=2024-01-31
+func (h *KanbanHandler) GetTimeReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	filter, err := timeFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter.ItemID = optionalQuery(c, "item")
+
+	report, err := h.services.Kanban.TimeReport(ctx, projectID, filter)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get time report")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GET /projects/{id}/time/export?user=me&item={itemId}&from=2024-01-01&to=2024-01-31
+func (h *KanbanHandler) ExportTime(c *gin.Context) {
+	ctx := c.Request.Context()
+	projectID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "kanban").WithField("project_id", projectID)
+
+	filter, err := timeFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter.ItemID = optionalQuery(c, "item")
+
+	entries, err := h.services.Kanban.TimeEntries(ctx, projectID, filter)
+	if err != nil {
+		logger.WithError(err).Warn("failed to export time entries")
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="time-%s.csv"`, projectID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"date", "user", "kanban", "item", "started_at", "ended_at", "hours", "note"})
+	for _, e := range entries {
+		ended := ""
+		if e.EndedAt.Valid {
+			ended = e.EndedAt.Time.UTC().Format(time.RFC3339)
+		}
+		w.Write([]string{
+			e.StartedAt.Time.UTC().Format(time.DateOnly),
+			utils.CSVCell(e.Username),
+			utils.CSVCell(e.KanbanName),
+			utils.CSVCell(e.ItemTitle),
+			e.StartedAt.Time.UTC().Format(time.RFC3339),
+			ended,
+			strconv.FormatFloat(float64(e.Seconds)/3600, 'f', 2, 64),
+			utils.CSVCell(e.Note.String),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.WithError(err).Warn("failed to write time export")
+	}
+}
+
+// timeFilter reads the user and date range of a time query, dates without a
+// time cover the whole day
+func timeFilter(c *gin.Context) (services.KanbanTimeFilter, error) {
+	filter := services.KanbanTimeFilter{
+		UserID: resolveUser(c, optionalQuery(c, "user")),
+	}
+
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		return filter, err
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		return filter, err
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return filter, utils.NewFieldError("to", "must be after from")
+	}
+	filter.From, filter.To = from, to
+	return filter, nil
+}
+
+// queryTime parses an RFC 3339 time or a date, a date used as the end of a
+// range includes that day
+func queryTime(c *gin.Context, key string, end bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, utils.NewFieldError(key, "must be a date or an RFC 3339 time")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
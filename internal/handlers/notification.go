@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+	cfg     *config.EnvConfig
+}
+
+// Create a new notification handler
+func NewNotificationHandler(service *services.NotificationService, cfg *config.EnvConfig) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+func (h *NotificationHandler) Routes(rg *gin.RouterGroup) {
+	notifications := rg.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware(h.cfg))
+
+	notifications.GET("", h.GetAll)
+	notifications.POST("/read", h.MarkAllRead)
+	notifications.POST("/:notificationId/read", h.MarkRead)
+}
+
+// GET /notifications?unread=true
+func (h *NotificationHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "notification").WithField("user_id", userID)
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 20)
+	offset := (page - 1) * limit
+	unreadOnly := utils.ParseBoolDefault(c.Query("unread"), false)
+
+	notifications, unread, err := h.service.List(ctx, userID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}, unreadOnly)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get notifications")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetNotificationsResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Page:          page,
+		Limit:         limit,
+	})
+}
+
+// POST /notifications/{notificationId}/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.GetUserID(c)
+	notificationID := c.Param("notificationId")
+
+	logger := logging.WithLayer(ctx, "handler", "notification").WithFields(logrus.Fields{
+		"user_id":         userID,
+		"notification_id": notificationID,
+	})
+
+	if err := h.service.MarkRead(ctx, userID, notificationID); err != nil {
+		logger.WithError(err).Warn("failed to mark notification as read")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /notifications/read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "notification").WithField("user_id", userID)
+
+	marked, err := h.service.MarkAllRead(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to mark notifications as read")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MarkNotificationsReadResponse{
+		Marked: marked,
+	})
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectHandlerServices struct {
+	Project *services.ProjectService
+	Checker *services.Checker
+}
+
+type ProjectHandler struct {
+	services *ProjectHandlerServices
+	cfg      *config.EnvConfig
+}
+
+// Create a new project handler
+func NewProjectHandler(services ProjectHandlerServices, cfg *config.EnvConfig) *ProjectHandler {
+	return &ProjectHandler{
+		services: &services,
+		cfg:      cfg,
+	}
+}
+
+func (h *ProjectHandler) Routes(rg *gin.RouterGroup) {
+	projects := rg.Group("/organisations/:id/projects")
+	projects.Use(middleware.AuthMiddleware(h.cfg))
+
+	view := middleware.RequirePermission(h.services.Checker, permissions.ProjectView)
+	edit := middleware.RequirePermission(h.services.Checker, permissions.ProjectEdit)
+
+	projects.GET("", view, h.GetAll)
+	projects.POST("", middleware.RequirePermission(h.services.Checker, permissions.ProjectCreate), h.Create)
+	projects.GET("/:projectId", view, h.Get)
+	projects.PUT("/:projectId", edit, h.Update)
+	projects.PUT("/:projectId/status", edit, h.UpdateStatus)
+	projects.DELETE("/:projectId", middleware.RequirePermission(h.services.Checker, permissions.ProjectDelete), h.Delete)
+
+	// Members
+	members := projects.Group("/:projectId/members")
+	members.GET("", view, h.GetMembers)
+	members.POST("", edit, h.AddMember)
+	members.PUT("/:userId", edit, h.UpdateMember)
+	members.DELETE("/:userId", edit, h.RemoveMember)
+}
+
+// POST /organisations/{id}/projects
+func (h *ProjectHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	var body dto.CreateProjectInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("trying to create project")
+
+	project, err := h.services.Project.Create(ctx, orgID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create project")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("project successfully created")
+	c.JSON(http.StatusCreated, dto.CreateProjectResponse{
+		Project: *project,
+	})
+}
+
+// GET /organisations/{id}/projects
+func (h *ProjectHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithField("org_id", orgID)
+
+	search := c.Query("search")
+	status := dto.ProjectStatus(c.Query("status"))
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 10)
+	offset := (page - 1) * limit
+
+	switch status {
+	case "", dto.ProjectActive, dto.ProjectCompleted, dto.ProjectArchived:
+	default:
+		logger.Warnf("invalid status filter: %s", status)
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid status filter", nil))
+		return
+	}
+
+	logger.Info("trying to fetch projects")
+
+	projects, err := h.services.Project.List(ctx, orgID, search, status, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get projects")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("projects successfully fetched")
+	c.JSON(http.StatusOK, dto.GetProjectsResponse{
+		Projects: projects,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// GET /organisations/{id}/projects/{projectId}
+func (h *ProjectHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	project, err := h.services.Project.Get(ctx, projectID, orgID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get project")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetProjectResponse{
+		Project: *project,
+	})
+}
+
+// PUT /organisations/{id}/projects/{projectId}
+func (h *ProjectHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	var body dto.UpdateProjectInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to parse json")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("attempting to update project")
+
+	project, err := h.services.Project.Update(ctx, projectID, orgID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update project")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("project updated successfully")
+	c.JSON(http.StatusOK, dto.UpdateProjectResponse{
+		Project: *project,
+	})
+}
+
+// PUT /organisations/{id}/projects/{projectId}/status
+func (h *ProjectHandler) UpdateStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	var body dto.UpdateProjectStatusInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to parse json")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Infof("attempting to change project status to %s", body.Status)
+
+	project, err := h.services.Project.UpdateStatus(ctx, projectID, orgID, body.Status)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update project status")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UpdateProjectResponse{
+		Project: *project,
+	})
+}
+
+// DELETE /organisations/{id}/projects/{projectId}
+func (h *ProjectHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	if err := h.services.Project.Delete(ctx, projectID, orgID); err != nil {
+		logger.WithError(err).Warn("failed to delete project")
+		c.Error(err)
+		return
+	}
+
+	logger.Info("project deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// Members
+
+// GET /organisations/{id}/projects/{projectId}/members
+func (h *ProjectHandler) GetMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	members, err := h.services.Project.ListMembers(ctx, projectID, orgID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get project members")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetProjectMembersResponse{
+		Members: members,
+	})
+}
+
+// POST /organisations/{id}/projects/{projectId}/members
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+	})
+
+	var body dto.CreateProjectMemberInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	member, err := h.services.Project.AddMember(ctx, projectID, orgID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to add project member")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateProjectMemberResponse{
+		Member: *member,
+	})
+}
+
+// PUT /organisations/{id}/projects/{projectId}/members/{userId}
+func (h *ProjectHandler) UpdateMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+	memberID := c.Param("userId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+		"member_id":  memberID,
+	})
+
+	var body dto.UpdateProjectMemberInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("invalid input provided")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	member, err := h.services.Project.UpdateMember(ctx, projectID, orgID, memberID, body.Role)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update project member")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UpdateProjectMemberResponse{
+		Member: *member,
+	})
+}
+
+// DELETE /organisations/{id}/projects/{projectId}/members/{userId}
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	projectID := c.Param("projectId")
+	memberID := c.Param("userId")
+
+	logger := logging.WithLayer(ctx, "handler", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": projectID,
+		"member_id":  memberID,
+	})
+
+	if err := h.services.Project.RemoveMember(ctx, projectID, orgID, memberID); err != nil {
+		logger.WithError(err).Warn("failed to remove project member")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
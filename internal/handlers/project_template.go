@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/middleware"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectTemplateHandlerServices struct {
+	Template *services.ProjectTemplateService
+	Checker  *services.Checker
+}
+
+type ProjectTemplateHandler struct {
+	services *ProjectTemplateHandlerServices
+	cfg      *config.EnvConfig
+}
+
+// Create a new project template handler
+func NewProjectTemplateHandler(services ProjectTemplateHandlerServices, cfg *config.EnvConfig) *ProjectTemplateHandler {
+	return &ProjectTemplateHandler{
+		services: &services,
+		cfg:      cfg,
+	}
+}
+
+func (h *ProjectTemplateHandler) Routes(rg *gin.RouterGroup) {
+	base := rg.Group("/organisations/:id")
+	base.Use(middleware.AuthMiddleware(h.cfg))
+
+	create := middleware.RequirePermission(h.services.Checker, permissions.ProjectCreate)
+
+	templates := base.Group("/project-templates")
+	templates.GET("", middleware.RequirePermission(h.services.Checker, permissions.ProjectView), h.GetAll)
+	templates.POST("", create, h.Create)
+	templates.GET("/:templateId", middleware.RequirePermission(h.services.Checker, permissions.ProjectView), h.Get)
+	templates.DELETE("/:templateId", middleware.RequirePermission(h.services.Checker, permissions.ProjectDelete), h.Delete)
+	templates.POST("/:templateId/instantiate", create, h.Instantiate)
+
+	base.POST("/projects/:projectId/clone", create, h.Clone)
+}
+
+// POST /organisations/{id}/project-templates
+func (h *ProjectTemplateHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	var body dto.CreateProjectTemplateInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("trying to save project as template")
+
+	template, err := h.services.Template.Create(ctx, orgID, userID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to create template")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateProjectTemplateResponse{
+		Template: dto.NewProjectTemplate(*template, true),
+	})
+}
+
+// GET /organisations/{id}/project-templates
+func (h *ProjectTemplateHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithField("org_id", orgID)
+
+	page := utils.ParseIntDefault(c.Query("page"), 1)
+	limit := utils.ParseIntDefault(c.Query("limit"), 10)
+	offset := (page - 1) * limit
+
+	templates, err := h.services.Template.List(ctx, orgID, services.Pagination{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to get templates")
+		c.Error(err)
+		return
+	}
+
+	output := make([]dto.ProjectTemplate, 0, len(templates))
+	for _, t := range templates {
+		output = append(output, dto.NewProjectTemplate(t, false))
+	}
+
+	c.JSON(http.StatusOK, dto.GetProjectTemplatesResponse{
+		Templates: output,
+		Page:      page,
+		Limit:     limit,
+	})
+}
+
+// GET /organisations/{id}/project-templates/{templateId}
+func (h *ProjectTemplateHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	templateID := c.Param("templateId")
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"template_id": templateID,
+	})
+
+	template, err := h.services.Template.Get(ctx, templateID, orgID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get template")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetProjectTemplateResponse{
+		Template: dto.NewProjectTemplate(*template, true),
+	})
+}
+
+// DELETE /organisations/{id}/project-templates/{templateId}
+func (h *ProjectTemplateHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	templateID := c.Param("templateId")
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"template_id": templateID,
+	})
+
+	if err := h.services.Template.Delete(ctx, templateID, orgID); err != nil {
+		logger.WithError(err).Warn("failed to delete template")
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /organisations/{id}/project-templates/{templateId}/instantiate
+func (h *ProjectTemplateHandler) Instantiate(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+	templateID := c.Param("templateId")
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"user_id":     userID,
+		"template_id": templateID,
+	})
+
+	var body dto.InstantiateProjectTemplateInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("trying to create project from template")
+
+	project, err := h.services.Template.Instantiate(ctx, orgID, userID, templateID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to instantiate template")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateProjectResponse{
+		Project: *project,
+	})
+}
+
+// POST /organisations/{id}/projects/{projectId}/clone
+func (h *ProjectTemplateHandler) Clone(c *gin.Context) {
+	ctx := c.Request.Context()
+	orgID := c.Param("id")
+	userID := utils.GetUserID(c)
+	projectID := c.Param("projectId")
+
+	logger := logging.WithLayer(ctx, "handler", "project_template").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"user_id":    userID,
+		"project_id": projectID,
+	})
+
+	var body dto.CloneProjectInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.WithError(err).Warn("failed to bind input params")
+		c.Error(utils.NewError(http.StatusBadRequest, "invalid input", err))
+		return
+	}
+
+	logger.Info("trying to clone project")
+
+	project, err := h.services.Template.Clone(ctx, orgID, userID, projectID, body)
+	if err != nil {
+		logger.WithError(err).Warn("failed to clone project")
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateProjectResponse{
+		Project: *project,
+	})
+}
@@ -42,3 +42,41 @@ func RequirePermission(checker *services.Checker, perm permissions.Permission) g
 		c.Next()
 	}
 }
+
+// RequireProjectPermission checks a permission in the organisation owning
+// the project given by the id route param
+func RequireProjectPermission(checker *services.Checker, perm permissions.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		userID := utils.GetUserID(c)
+		projectID := c.Param("id")
+
+		logger := logging.WithLayer(ctx, "middleware", "permissions").WithFields(logrus.Fields{
+			"user_id":    userID,
+			"project_id": projectID,
+			"perm":       perm,
+		})
+
+		if projectID == "" {
+			logger.Warn("missing project ID in route")
+			c.Error(utils.NewError(http.StatusBadRequest, "missing project id in route", nil))
+			c.Abort()
+			return
+		}
+
+		logger.Infof("checking project permission: %s", perm)
+		orgID, err := checker.CheckProject(ctx, userID, projectID, perm)
+		if err != nil {
+			logger.WithError(err).Warn("permission denied")
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		// Attach organisation ID to context
+		c.Set("org_id", orgID)
+
+		logger.Info("permission granted")
+		c.Next()
+	}
+}
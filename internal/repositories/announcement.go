@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// AnnouncementRepo wraps SQLC queries for organisation announcements
+type AnnouncementRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewAnnouncementRepo creates a new instance of AnnouncementRepo
+func NewAnnouncementRepo(q repository.Querier, logger *logrus.Logger) *AnnouncementRepo {
+	return &AnnouncementRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// Create a new announcement
+func (r *AnnouncementRepo) Create(ctx context.Context, params repository.CreateAnnouncementParams) (repository.Announcement, error) {
+	return r.q.CreateAnnouncement(ctx, params)
+}
+
+// Get an announcement within an organisation, scheduled and expired ones
+// only when includeUnpublished is set
+func (r *AnnouncementRepo) Get(ctx context.Context, id, orgID string, includeUnpublished bool) (repository.Announcement, error) {
+	return r.q.GetAnnouncement(ctx, repository.GetAnnouncementParams{
+		ID:                 id,
+		OrganisationID:     orgID,
+		IncludeUnpublished: includeUnpublished,
+	})
+}
+
+// List gets a paginated list of announcements with the users read state
+func (r *AnnouncementRepo) List(ctx context.Context, params repository.ListAnnouncementsParams) ([]repository.ListAnnouncementsRow, error) {
+	return r.q.ListAnnouncements(ctx, params)
+}
+
+// ListUnread gets all published announcements the user has not read yet
+func (r *AnnouncementRepo) ListUnread(ctx context.Context, userID, orgID string) ([]repository.ListUnreadAnnouncementsRow, error) {
+	return r.q.ListUnreadAnnouncements(ctx, repository.ListUnreadAnnouncementsParams{
+		UserID:         userID,
+		OrganisationID: orgID,
+	})
+}
+
+// Update an announcement
+func (r *AnnouncementRepo) Update(ctx context.Context, params repository.UpdateAnnouncementParams) (repository.Announcement, error) {
+	return r.q.UpdateAnnouncement(ctx, params)
+}
+
+// Delete an announcement, returns the number of deleted rows
+func (r *AnnouncementRepo) Delete(ctx context.Context, id, orgID string) (int64, error) {
+	return r.q.DeleteAnnouncement(ctx, repository.DeleteAnnouncementParams{
+		ID:             id,
+		OrganisationID: orgID,
+	})
+}
+
+// MarkRead marks a single announcement as read by the user
+func (r *AnnouncementRepo) MarkRead(ctx context.Context, id, userID string) error {
+	return r.q.MarkAnnouncementRead(ctx, repository.MarkAnnouncementReadParams{
+		AnnouncementID: id,
+		UserID:         userID,
+	})
+}
+
+// MarkAllRead marks every published announcement in the organisation as read
+func (r *AnnouncementRepo) MarkAllRead(ctx context.Context, userID, orgID string) (int64, error) {
+	return r.q.MarkAllAnnouncementsRead(ctx, repository.MarkAllAnnouncementsReadParams{
+		UserID:         userID,
+		OrganisationID: orgID,
+	})
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// AttachmentRepo wraps SQLC queries for attachments shared by cards and chats
+type AttachmentRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewAttachmentRepo creates a new instance of AttachmentRepo
+func NewAttachmentRepo(q repository.Querier, logger *logrus.Logger) *AttachmentRepo {
+	return &AttachmentRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// GetOrganisationLimits returns the upload limits an organisation set
+func (r *AttachmentRepo) GetOrganisationLimits(ctx context.Context, orgID string) (repository.GetOrganisationAttachmentLimitsRow, error) {
+	return r.q.GetOrganisationAttachmentLimits(ctx, orgID)
+}
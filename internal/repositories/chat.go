@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// ChatRepo wraps SQLC queries for chat rooms and messages
+type ChatRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewChatRepo creates a new instance of ChatRepo
+func NewChatRepo(q repository.Querier, logger *logrus.Logger) *ChatRepo {
+	return &ChatRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// ListRooms gets the rooms of an organisation the user is a member of
+func (r *ChatRepo) ListRooms(ctx context.Context, orgID, userID string) ([]repository.ChatRoom, error) {
+	return r.q.ListUserChatRooms(ctx, repository.ListUserChatRoomsParams{
+		OrganisationID: orgID,
+		UserID:         userID,
+	})
+}
+
+// ListMessages gets a page of the messages of a room, newest first
+func (r *ChatRepo) ListMessages(ctx context.Context, params repository.ListChatMessagesParams) ([]repository.ChatMessage, error) {
+	return r.q.ListChatMessages(ctx, params)
+}
+
+// ListAttachments gets the files attached to the given messages
+func (r *ChatRepo) ListAttachments(ctx context.Context, messageIDs []string) ([]repository.Attachment, error) {
+	return r.q.ListChatMessageAttachments(ctx, messageIDs)
+}
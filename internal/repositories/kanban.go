@@ -0,0 +1,336 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// KanbanRepo wraps SQLC queries for kanban boards, categories and items
+type KanbanRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewKanbanRepo creates a new instance of KanbanRepo
+func NewKanbanRepo(q repository.Querier, logger *logrus.Logger) *KanbanRepo {
+	return &KanbanRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// Get a kanban within a project
+func (r *KanbanRepo) Get(ctx context.Context, id, projectID string) (repository.Kanban, error) {
+	return r.q.GetKanban(ctx, repository.GetKanbanParams{
+		ID:        id,
+		ProjectID: projectID,
+	})
+}
+
+// GetByID gets a kanban by its id regardless of project
+func (r *KanbanRepo) GetByID(ctx context.Context, id string) (repository.Kanban, error) {
+	return r.q.GetKanbanByID(ctx, id)
+}
+
+// List gets all kanbans in a project
+func (r *KanbanRepo) List(ctx context.Context, projectID string) ([]repository.Kanban, error) {
+	return r.q.ListKanbansByProject(ctx, projectID)
+}
+
+// Update a kanban
+func (r *KanbanRepo) Update(ctx context.Context, params repository.UpdateKanbanParams) (repository.Kanban, error) {
+	return r.q.UpdateKanban(ctx, params)
+}
+
+// Delete a kanban, returns the number of deleted rows
+func (r *KanbanRepo) Delete(ctx context.Context, id, projectID string) (int64, error) {
+	return r.q.DeleteKanban(ctx, repository.DeleteKanbanParams{
+		ID:        id,
+		ProjectID: projectID,
+	})
+}
+
+// --- Categories ---
+
+func (r *KanbanRepo) GetCategory(ctx context.Context, id, kanbanID string) (repository.KanbanCategory, error) {
+	return r.q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+		ID:       id,
+		KanbanID: kanbanID,
+	})
+}
+
+func (r *KanbanRepo) ListCategories(ctx context.Context, kanbanID string) ([]repository.KanbanCategory, error) {
+	return r.q.ListKanbanCategories(ctx, kanbanID)
+}
+
+func (r *KanbanRepo) ListDeletedCategories(ctx context.Context, params repository.ListDeletedKanbanCategoriesParams) ([]repository.KanbanCategory, error) {
+	return r.q.ListDeletedKanbanCategories(ctx, params)
+}
+
+func (r *KanbanRepo) CountDeletedCategories(ctx context.Context, kanbanID string) (int64, error) {
+	return r.q.CountDeletedKanbanCategories(ctx, kanbanID)
+}
+
+// --- Lanes ---
+
+func (r *KanbanRepo) ListLanes(ctx context.Context, kanbanID string) ([]repository.KanbanLane, error) {
+	return r.q.ListKanbanLanes(ctx, kanbanID)
+}
+
+// --- Items ---
+
+func (r *KanbanRepo) GetItem(ctx context.Context, id, kanbanID string) (repository.KanbanItem, error) {
+	return r.q.GetKanbanItem(ctx, repository.GetKanbanItemParams{
+		ID:       id,
+		KanbanID: kanbanID,
+	})
+}
+
+func (r *KanbanRepo) ListItems(ctx context.Context, kanbanID string) ([]repository.KanbanItem, error) {
+	return r.q.ListKanbanItems(ctx, kanbanID)
+}
+
+func (r *KanbanRepo) ListDeletedItems(ctx context.Context, params repository.ListDeletedKanbanItemsParams) ([]repository.KanbanItem, error) {
+	return r.q.ListDeletedKanbanItems(ctx, params)
+}
+
+func (r *KanbanRepo) CountDeletedItems(ctx context.Context, kanbanID string) (int64, error) {
+	return r.q.CountDeletedKanbanItems(ctx, kanbanID)
+}
+
+// ListProjectItems lists active items across the kanbans of a project
+func (r *KanbanRepo) ListProjectItems(ctx context.Context, params repository.ListProjectKanbanItemsParams) ([]repository.ListProjectKanbanItemsRow, error) {
+	return r.q.ListProjectKanbanItems(ctx, params)
+}
+
+// --- Attachments ---
+
+// ListAttachments lists the attachments of an item, oldest first
+func (r *KanbanRepo) ListAttachments(ctx context.Context, itemID string) ([]repository.Attachment, error) {
+	return r.q.ListItemAttachments(ctx, utils.PtrToPgText(&itemID))
+}
+
+// --- Assignees, watchers and labels ---
+
+// ListAssignees lists every assignee link of the items in a kanban
+func (r *KanbanRepo) ListAssignees(ctx context.Context, kanbanID string) ([]repository.ListKanbanAssigneesRow, error) {
+	return r.q.ListKanbanAssignees(ctx, kanbanID)
+}
+
+// ListLabelLinks lists every label link of the items in a kanban
+func (r *KanbanRepo) ListLabelLinks(ctx context.Context, kanbanID string) ([]repository.ListKanbanLabelLinksRow, error) {
+	return r.q.ListKanbanLabelLinks(ctx, kanbanID)
+}
+
+func (r *KanbanRepo) ListItemAssignees(ctx context.Context, itemID string) ([]repository.ListKanbanItemAssigneesRow, error) {
+	return r.q.ListKanbanItemAssignees(ctx, itemID)
+}
+
+func (r *KanbanRepo) ListItemWatchers(ctx context.Context, itemID string) ([]repository.ListKanbanItemWatchersRow, error) {
+	return r.q.ListKanbanItemWatchers(ctx, itemID)
+}
+
+func (r *KanbanRepo) ListItemLabels(ctx context.Context, itemID string) ([]repository.KanbanLabel, error) {
+	return r.q.ListKanbanItemLabels(ctx, itemID)
+}
+
+// ListBoardLabels lists the organisation and kanban labels usable on a kanban
+func (r *KanbanRepo) ListBoardLabels(ctx context.Context, kanbanID string) ([]repository.KanbanLabel, error) {
+	return r.q.ListBoardLabels(ctx, kanbanID)
+}
+
+// ListOrganisationLabels lists the labels shared by all kanbans of an organisation
+func (r *KanbanRepo) ListOrganisationLabels(ctx context.Context, orgID string) ([]repository.KanbanLabel, error) {
+	return r.q.ListOrganisationLabels(ctx, orgID)
+}
+
+func (r *KanbanRepo) CreateLabel(ctx context.Context, params repository.CreateKanbanLabelParams) (repository.KanbanLabel, error) {
+	return r.q.CreateKanbanLabel(ctx, params)
+}
+
+// ListOrganisationKanbanIDs lists the ids of every kanban in an organisation
+func (r *KanbanRepo) ListOrganisationKanbanIDs(ctx context.Context, orgID string) ([]string, error) {
+	return r.q.ListOrganisationKanbanIDs(ctx, orgID)
+}
+
+// --- Comments ---
+
+func (r *KanbanRepo) GetComment(ctx context.Context, id, itemID string) (repository.KanbanItemComment, error) {
+	return r.q.GetKanbanItemComment(ctx, repository.GetKanbanItemCommentParams{
+		ID:     id,
+		ItemID: itemID,
+	})
+}
+
+// ListComments lists the comment thread of an item in posting order
+func (r *KanbanRepo) ListComments(ctx context.Context, itemID string) ([]repository.ListKanbanItemCommentsRow, error) {
+	return r.q.ListKanbanItemComments(ctx, itemID)
+}
+
+func (r *KanbanRepo) ListCommentMentions(ctx context.Context, itemID string) ([]repository.KanbanItemCommentMention, error) {
+	return r.q.ListKanbanItemCommentMentions(ctx, itemID)
+}
+
+func (r *KanbanRepo) ListCommentRevisions(ctx context.Context, commentID string) ([]repository.ListKanbanItemCommentRevisionsRow, error) {
+	return r.q.ListKanbanItemCommentRevisions(ctx, commentID)
+}
+
+// --- Checklists ---
+
+// ListChecklistProgress counts done and total checklist entries per item of a kanban
+func (r *KanbanRepo) ListChecklistProgress(ctx context.Context, kanbanID string) ([]repository.ListKanbanChecklistProgressRow, error) {
+	return r.q.ListKanbanChecklistProgress(ctx, kanbanID)
+}
+
+// ListChecklist lists the checklist entries of an item in order
+func (r *KanbanRepo) ListChecklist(ctx context.Context, itemID string) ([]repository.KanbanChecklistEntry, error) {
+	return r.q.ListKanbanChecklistEntries(ctx, itemID)
+}
+
+// --- Links ---
+
+// ListItemLinks lists the links of an item seen from that item
+func (r *KanbanRepo) ListItemLinks(ctx context.Context, itemID string) ([]repository.ListKanbanItemLinksRow, error) {
+	return r.q.ListKanbanItemLinks(ctx, itemID)
+}
+
+// ListItemBlockers lists the unfinished items blocking an item
+func (r *KanbanRepo) ListItemBlockers(ctx context.Context, itemID string) ([]repository.ListKanbanItemBlockersRow, error) {
+	return r.q.ListKanbanItemBlockers(ctx, itemID)
+}
+
+// ListItemDependents lists the items an item blocks with their blocked state
+func (r *KanbanRepo) ListItemDependents(ctx context.Context, itemID string) ([]repository.ListKanbanItemDependentsRow, error) {
+	return r.q.ListKanbanItemDependents(ctx, itemID)
+}
+
+// ListBlockedItems lists the ids of the blocked items of a kanban
+func (r *KanbanRepo) ListBlockedItems(ctx context.Context, kanbanID string) ([]string, error) {
+	return r.q.ListBlockedKanbanItems(ctx, kanbanID)
+}
+
+// --- Activity ---
+
+// ListActivity pages through the activity log of a kanban
+func (r *KanbanRepo) ListActivity(ctx context.Context, params repository.ListKanbanActivityParams) ([]repository.ListKanbanActivityRow, error) {
+	return r.q.ListKanbanActivity(ctx, params)
+}
+
+// ListItemActivity pages through the history of an item
+func (r *KanbanRepo) ListItemActivity(ctx context.Context, params repository.ListKanbanItemActivityParams) ([]repository.ListKanbanItemActivityRow, error) {
+	return r.q.ListKanbanItemActivity(ctx, params)
+}
+
+// --- Time tracking ---
+
+// ListTimeEntries lists the time entries of a project matching a filter
+func (r *KanbanRepo) ListTimeEntries(ctx context.Context, params repository.ListKanbanTimeEntriesParams) ([]repository.ListKanbanTimeEntriesRow, error) {
+	return r.q.ListKanbanTimeEntries(ctx, params)
+}
+
+// TimeTotalsByUser totals the time logged in a project per user
+func (r *KanbanRepo) TimeTotalsByUser(ctx context.Context, params repository.KanbanTimeTotalsByUserParams) ([]repository.KanbanTimeTotalsByUserRow, error) {
+	return r.q.KanbanTimeTotalsByUser(ctx, params)
+}
+
+// TimeTotalsByItem totals the time logged in a project per item
+func (r *KanbanRepo) TimeTotalsByItem(ctx context.Context, params repository.KanbanTimeTotalsByItemParams) ([]repository.KanbanTimeTotalsByItemRow, error) {
+	return r.q.KanbanTimeTotalsByItem(ctx, params)
+}
+
+// --- Metrics ---
+
+// ListCompletedItems lists the items of a kanban completed within a range
+func (r *KanbanRepo) ListCompletedItems(ctx context.Context, params repository.ListKanbanCompletedItemsParams) ([]repository.ListKanbanCompletedItemsRow, error) {
+	return r.q.ListKanbanCompletedItems(ctx, params)
+}
+
+// CumulativeFlow counts the items per category at the end of every day of a range
+func (r *KanbanRepo) CumulativeFlow(ctx context.Context, params repository.KanbanCumulativeFlowParams) ([]repository.KanbanCumulativeFlowRow, error) {
+	return r.q.KanbanCumulativeFlow(ctx, params)
+}
+
+// --- Rules ---
+
+func (r *KanbanRepo) GetRule(ctx context.Context, id, kanbanID string) (repository.KanbanRule, error) {
+	return r.q.GetKanbanRule(ctx, repository.GetKanbanRuleParams{
+		ID:       id,
+		KanbanID: kanbanID,
+	})
+}
+
+func (r *KanbanRepo) ListRules(ctx context.Context, kanbanID string) ([]repository.KanbanRule, error) {
+	return r.q.ListKanbanRules(ctx, kanbanID)
+}
+
+// ListEnabledRules lists the rules of a kanban listening for an event
+func (r *KanbanRepo) ListEnabledRules(ctx context.Context, kanbanID, event string) ([]repository.KanbanRule, error) {
+	return r.q.ListEnabledKanbanRules(ctx, repository.ListEnabledKanbanRulesParams{
+		KanbanID: kanbanID,
+		Event:    event,
+	})
+}
+
+// ListOverdueRuleItems lists overdue cards with the rules still to fire for them, across all kanbans
+func (r *KanbanRepo) ListOverdueRuleItems(ctx context.Context, limit int32) ([]repository.ListOverdueKanbanRuleItemsRow, error) {
+	return r.q.ListOverdueKanbanRuleItems(ctx, limit)
+}
+
+func (r *KanbanRepo) ClaimRuleRun(ctx context.Context, params repository.ClaimKanbanRuleRunParams) (int64, error) {
+	return r.q.ClaimKanbanRuleRun(ctx, params)
+}
+
+// --- Recurrences ---
+
+func (r *KanbanRepo) ListRecurrences(ctx context.Context, kanbanID string) ([]repository.KanbanRecurrence, error) {
+	return r.q.ListKanbanRecurrences(ctx, kanbanID)
+}
+
+func (r *KanbanRepo) DeleteRecurrence(ctx context.Context, id, kanbanID string) (int64, error) {
+	return r.q.DeleteKanbanRecurrence(ctx, repository.DeleteKanbanRecurrenceParams{
+		ID:       id,
+		KanbanID: kanbanID,
+	})
+}
+
+// ListDueRecurrenceIDs lists recurrences with a card to create, across all kanbans
+func (r *KanbanRepo) ListDueRecurrenceIDs(ctx context.Context, limit int32) ([]string, error) {
+	return r.q.ListDueKanbanRecurrenceIDs(ctx, limit)
+}
+
+// --- Imports ---
+
+func (r *KanbanRepo) CreateImport(ctx context.Context, params repository.CreateKanbanImportParams) (repository.KanbanImport, error) {
+	return r.q.CreateKanbanImport(ctx, params)
+}
+
+func (r *KanbanRepo) GetImport(ctx context.Context, id, projectID string) (repository.KanbanImport, error) {
+	return r.q.GetKanbanImport(ctx, repository.GetKanbanImportParams{
+		ID:        id,
+		ProjectID: projectID,
+	})
+}
+
+// --- Exports ---
+
+func (r *KanbanRepo) ListExportCategories(ctx context.Context, kanbanID string, includeArchived bool) ([]repository.KanbanCategory, error) {
+	return r.q.ListKanbanExportCategories(ctx, repository.ListKanbanExportCategoriesParams{
+		KanbanID:        kanbanID,
+		IncludeArchived: includeArchived,
+	})
+}
+
+func (r *KanbanRepo) ListExportItems(ctx context.Context, params repository.ListKanbanExportItemsParams) ([]repository.KanbanItem, error) {
+	return r.q.ListKanbanExportItems(ctx, params)
+}
+
+func (r *KanbanRepo) ListExportItemLabels(ctx context.Context, itemIDs []string) ([]repository.ListKanbanExportItemLabelsRow, error) {
+	return r.q.ListKanbanExportItemLabels(ctx, itemIDs)
+}
+
+func (r *KanbanRepo) ListExportItemAssignees(ctx context.Context, itemIDs []string) ([]repository.ListKanbanExportItemAssigneesRow, error) {
+	return r.q.ListKanbanExportItemAssignees(ctx, itemIDs)
+}
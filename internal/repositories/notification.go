@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationRepo wraps SQLC queries for user notifications
+type NotificationRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewNotificationRepo creates a new instance of NotificationRepo
+func NewNotificationRepo(q repository.Querier, logger *logrus.Logger) *NotificationRepo {
+	return &NotificationRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// List notifications of a user, newest first
+func (r *NotificationRepo) List(ctx context.Context, params repository.ListNotificationsParams) ([]repository.ListNotificationsRow, error) {
+	return r.q.ListNotifications(ctx, params)
+}
+
+// CountUnread counts the unread notifications of a user
+func (r *NotificationRepo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.q.CountUnreadNotifications(ctx, userID)
+}
+
+// MarkRead marks a notification of a user as read, returns the number of matched rows
+func (r *NotificationRepo) MarkRead(ctx context.Context, id, userID string) (int64, error) {
+	return r.q.MarkNotificationRead(ctx, repository.MarkNotificationReadParams{
+		ID:     id,
+		UserID: userID,
+	})
+}
+
+// MarkAllRead marks every unread notification of a user as read
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return r.q.MarkAllNotificationsRead(ctx, userID)
+}
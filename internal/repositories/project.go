@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectRepo wraps SQLC queries for projects and their members
+type ProjectRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewProjectRepo creates a new instance of ProjectRepo
+func NewProjectRepo(q repository.Querier, logger *logrus.Logger) *ProjectRepo {
+	return &ProjectRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// Create a new project
+func (r *ProjectRepo) Create(ctx context.Context, params repository.CreateProjectParams) (repository.Project, error) {
+	return r.q.CreateProject(ctx, params)
+}
+
+// Get a project within an organisation
+func (r *ProjectRepo) Get(ctx context.Context, id, orgID string) (repository.Project, error) {
+	return r.q.GetProject(ctx, repository.GetProjectParams{
+		ID:             id,
+		OrganisationID: orgID,
+	})
+}
+
+// GetByID gets a project by its id regardless of organisation
+func (r *ProjectRepo) GetByID(ctx context.Context, id string) (repository.Project, error) {
+	return r.q.GetProjectByID(ctx, id)
+}
+
+// List gets a paginated list of projects in an organisation
+func (r *ProjectRepo) List(ctx context.Context, params repository.ListProjectsParams) ([]repository.Project, error) {
+	return r.q.ListProjects(ctx, params)
+}
+
+// Update a project
+func (r *ProjectRepo) Update(ctx context.Context, params repository.UpdateProjectParams) (repository.Project, error) {
+	return r.q.UpdateProject(ctx, params)
+}
+
+// UpdateStatus sets the status of a project
+func (r *ProjectRepo) UpdateStatus(ctx context.Context, params repository.UpdateProjectStatusParams) (repository.Project, error) {
+	return r.q.UpdateProjectStatus(ctx, params)
+}
+
+// Delete a project, returns the number of deleted rows
+func (r *ProjectRepo) Delete(ctx context.Context, id, orgID string) (int64, error) {
+	return r.q.DeleteProject(ctx, repository.DeleteProjectParams{
+		ID:             id,
+		OrganisationID: orgID,
+	})
+}
+
+// --- Members ---
+
+func (r *ProjectRepo) AddMember(ctx context.Context, params repository.AddProjectMemberParams) (repository.ProjectMember, error) {
+	return r.q.AddProjectMember(ctx, params)
+}
+
+func (r *ProjectRepo) GetMember(ctx context.Context, projectID, userID string) (repository.ProjectMember, error) {
+	return r.q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+}
+
+func (r *ProjectRepo) ListMembers(ctx context.Context, projectID string) ([]repository.ListProjectMembersRow, error) {
+	return r.q.ListProjectMembers(ctx, projectID)
+}
+
+func (r *ProjectRepo) UpdateMemberRole(ctx context.Context, params repository.UpdateProjectMemberRoleParams) (repository.ProjectMember, error) {
+	return r.q.UpdateProjectMemberRole(ctx, params)
+}
+
+func (r *ProjectRepo) RemoveMember(ctx context.Context, projectID, userID string) (int64, error) {
+	return r.q.RemoveProjectMember(ctx, repository.RemoveProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+}
+
+func (r *ProjectRepo) CountAdmins(ctx context.Context, projectID string) (int64, error) {
+	return r.q.CountProjectAdmins(ctx, projectID)
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectTemplateRepo wraps SQLC queries for project templates
+type ProjectTemplateRepo struct {
+	q      repository.Querier
+	logger *logrus.Logger
+}
+
+// NewProjectTemplateRepo creates a new instance of ProjectTemplateRepo
+func NewProjectTemplateRepo(q repository.Querier, logger *logrus.Logger) *ProjectTemplateRepo {
+	return &ProjectTemplateRepo{
+		q:      q,
+		logger: logger,
+	}
+}
+
+// Get a template within an organisation
+func (r *ProjectTemplateRepo) Get(ctx context.Context, id, orgID string) (repository.ProjectTemplate, error) {
+	return r.q.GetProjectTemplate(ctx, repository.GetProjectTemplateParams{
+		ID:             id,
+		OrganisationID: orgID,
+	})
+}
+
+// List gets a paginated list of templates in an organisation
+func (r *ProjectTemplateRepo) List(ctx context.Context, orgID string, limit, offset int32) ([]repository.ProjectTemplate, error) {
+	return r.q.ListProjectTemplates(ctx, repository.ListProjectTemplatesParams{
+		OrganisationID: orgID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+}
+
+// Delete a template, returns the number of deleted rows
+func (r *ProjectTemplateRepo) Delete(ctx context.Context, id, orgID string) (int64, error) {
+	return r.q.DeleteProjectTemplate(ctx, repository.DeleteProjectTemplateParams{
+		ID:             id,
+		OrganisationID: orgID,
+	})
+}
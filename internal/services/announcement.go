@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type AnnouncementService struct {
+	repo   *repositories.AnnouncementRepo
+	logger *logrus.Logger
+}
+
+func NewAnnouncementService(repo *repositories.AnnouncementRepo, logger *logrus.Logger) *AnnouncementService {
+	return &AnnouncementService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// -------------------------------------------------------------
+// Create
+// -------------------------------------------------------------
+func (s *AnnouncementService) Create(ctx context.Context, orgID, userID string, params dto.CreateAnnouncementInput) (*repository.Announcement, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+	logger.Info("creating announcement")
+
+	publishAt := time.Now()
+	if params.PublishAt != nil {
+		publishAt = *params.PublishAt
+	}
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(publishAt) {
+		logger.Warn("announcement expires before it is published")
+		return nil, utils.NewError(http.StatusBadRequest, "expiry must be after publish time", nil)
+	}
+
+	announcement, err := s.repo.Create(ctx, repository.CreateAnnouncementParams{
+		ID:             gonanoid.Must(),
+		OrganisationID: orgID,
+		AuthorID:       utils.PtrToPgText(&userID),
+		Body:           params.Body,
+		Pinned:         params.Pinned,
+		PublishAt:      utils.PtrToPgTimestamptz(params.PublishAt),
+		ExpiresAt:      utils.PtrToPgTimestamptz(params.ExpiresAt),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create announcement")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to create announcement", err)
+	}
+
+	logger.WithField("announcement_id", announcement.ID).Info("announcement created")
+	return &announcement, nil
+}
+
+// -------------------------------------------------------------
+// List
+// -------------------------------------------------------------
+func (s *AnnouncementService) List(ctx context.Context, orgID, userID string, pagination Pagination, includeUnpublished bool) ([]repository.ListAnnouncementsRow, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+	logger.Info("fetching announcements")
+
+	list, err := s.repo.List(ctx, repository.ListAnnouncementsParams{
+		UserID:             userID,
+		OrganisationID:     orgID,
+		IncludeUnpublished: includeUnpublished,
+		Limit:              pagination.Limit,
+		Offset:             pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list announcements")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list announcements", err)
+	}
+
+	logger.Infof("fetched %d announcements", len(list))
+	return list, nil
+}
+
+// -------------------------------------------------------------
+// Unread
+// -------------------------------------------------------------
+func (s *AnnouncementService) Unread(ctx context.Context, orgID, userID string) ([]repository.ListUnreadAnnouncementsRow, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	list, err := s.repo.ListUnread(ctx, userID, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list unread announcements")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list unread announcements", err)
+	}
+
+	logger.Infof("user has %d unread announcements", len(list))
+	return list, nil
+}
+
+// -------------------------------------------------------------
+// Get
+// -------------------------------------------------------------
+// Get fetches an announcement, scheduled and expired announcements are only
+// found with includeUnpublished
+func (s *AnnouncementService) Get(ctx context.Context, id, orgID string, includeUnpublished bool) (*repository.Announcement, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": id,
+	})
+
+	announcement, err := s.repo.Get(ctx, id, orgID, includeUnpublished)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithError(err).Warn("announcement not found")
+			return nil, utils.NewError(http.StatusNotFound, "announcement not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch announcement")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch announcement", err)
+	}
+
+	return &announcement, nil
+}
+
+// -------------------------------------------------------------
+// Update
+// -------------------------------------------------------------
+func (s *AnnouncementService) Update(ctx context.Context, id, orgID string, params dto.UpdateAnnouncementInput) (*repository.Announcement, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": id,
+	})
+	logger.Info("attempting to update announcement")
+
+	current, err := s.Get(ctx, id, orgID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the resulting publish window
+	publishAt := current.PublishAt.Time
+	if params.PublishAt != nil {
+		publishAt = *params.PublishAt
+	}
+	expiresAt := utils.PgTimestamptzToPtr(current.ExpiresAt)
+	if params.ClearExpiresAt {
+		expiresAt = nil
+	} else if params.ExpiresAt != nil {
+		expiresAt = params.ExpiresAt
+	}
+	if expiresAt != nil && !expiresAt.After(publishAt) {
+		logger.Warn("announcement expires before it is published")
+		return nil, utils.NewError(http.StatusBadRequest, "expiry must be after publish time", nil)
+	}
+
+	announcement, err := s.repo.Update(ctx, repository.UpdateAnnouncementParams{
+		ID:             id,
+		OrganisationID: orgID,
+		Body:           utils.PtrToPgText(params.Body),
+		Pinned:         utils.PtrToPgBool(params.Pinned),
+		PublishAt:      utils.PtrToPgTimestamptz(params.PublishAt),
+		ExpiresAt:      utils.PtrToPgTimestamptz(params.ExpiresAt),
+		ClearExpiresAt: params.ClearExpiresAt,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to update announcement")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to update announcement", err)
+	}
+
+	logger.Info("announcement updated successfully")
+	return &announcement, nil
+}
+
+// -------------------------------------------------------------
+// Delete
+// -------------------------------------------------------------
+func (s *AnnouncementService) Delete(ctx context.Context, id, orgID string) error {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"announcement_id": id,
+	})
+
+	deleted, err := s.repo.Delete(ctx, id, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete announcement")
+		return utils.NewError(http.StatusInternalServerError, "failed to delete announcement", err)
+	}
+	if deleted == 0 {
+		logger.Warn("announcement not found")
+		return utils.NewError(http.StatusNotFound, "announcement not found", nil)
+	}
+
+	logger.Info("announcement deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Read tracking
+// -------------------------------------------------------------
+func (s *AnnouncementService) MarkRead(ctx context.Context, id, orgID, userID string, includeUnpublished bool) error {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":          orgID,
+		"user_id":         userID,
+		"announcement_id": id,
+	})
+
+	// Make sure the announcement belongs to the organisation and is visible
+	if _, err := s.Get(ctx, id, orgID, includeUnpublished); err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkRead(ctx, id, userID); err != nil {
+		logger.WithError(err).Error("failed to mark announcement as read")
+		return utils.NewError(http.StatusInternalServerError, "failed to mark announcement as read", err)
+	}
+
+	logger.Debug("announcement marked as read")
+	return nil
+}
+
+func (s *AnnouncementService) MarkAllRead(ctx context.Context, orgID, userID string) (int64, error) {
+	logger := logging.WithLayer(ctx, "service", "announcement").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	marked, err := s.repo.MarkAllRead(ctx, userID, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to mark announcements as read")
+		return 0, utils.NewError(http.StatusInternalServerError, "failed to mark announcements as read", err)
+	}
+
+	logger.Infof("marked %d announcements as read", marked)
+	return marked, nil
+}
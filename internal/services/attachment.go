@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/storage"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Scan states stored with an attachment
+const (
+	scanUnscanned = "unscanned"
+	scanClean     = "clean"
+)
+
+const (
+	// Stored files removed per cleanup run
+	attachmentCleanupBatch = 100
+	// Longest file name kept, longer names are cut before the extension
+	maxAttachmentNameLength = 255
+	// Most types an organisation may allow
+	maxAttachmentTypes = 50
+)
+
+// attachmentType matches a MIME type or a family like image/*
+var attachmentType = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$&^_.+-]*/(\*|[a-z0-9][a-z0-9!#$&^_.+-]*)$`)
+
+type AttachmentOptions struct {
+	// MaxSize caps every upload, organisations may only lower it
+	MaxSize int64
+	// URLTTL is how long a download link works
+	URLTTL time.Duration
+}
+
+// AttachmentStore keeps the files of card and chat attachments. Uploads are
+// checked against the limits of their organisation and the virus scanner
+// before they reach storage, and files of deleted attachments are removed by
+// RunCleanup.
+type AttachmentStore struct {
+	repo    *repositories.AttachmentRepo
+	tx      *repositories.TxManager
+	storage storage.Storage
+	scanner AttachmentScanner
+	opts    AttachmentOptions
+	logger  *logrus.Logger
+}
+
+func NewAttachmentStore(repo *repositories.AttachmentRepo, tx *repositories.TxManager, storage storage.Storage, opts AttachmentOptions, logger *logrus.Logger) *AttachmentStore {
+	return &AttachmentStore{
+		repo:    repo,
+		tx:      tx,
+		storage: storage,
+		opts:    opts,
+		logger:  logger,
+	}
+}
+
+// SetScanner registers the virus scanner uploads are passed through
+func (a *AttachmentStore) SetScanner(scanner AttachmentScanner) {
+	a.scanner = scanner
+}
+
+// storedFile is an upload that is in storage but not recorded yet
+type storedFile struct {
+	Key         string
+	FileName    string
+	ContentType string
+	Size        int64
+	Checksum    string
+	ScanStatus  string
+}
+
+// store checks an upload for an organisation and puts it in storage. The
+// file is spooled to disk first so it can be sniffed, scanned and hashed.
+func (a *AttachmentStore) store(ctx context.Context, orgID, fileName string, content io.Reader) (*storedFile, error) {
+	logger := logging.WithLayer(ctx, "service", "attachment").WithField("org_id", orgID)
+
+	limits, err := a.repo.GetOrganisationLimits(ctx, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch attachment limits")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store file", err)
+	}
+	maxSize := a.opts.MaxSize
+	if limits.AttachmentMaxSize.Valid && limits.AttachmentMaxSize.Int64 < maxSize {
+		maxSize = limits.AttachmentMaxSize.Int64
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store file", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return nil, utils.NewError(http.StatusBadRequest, "failed to read file", err)
+	}
+	if size > maxSize {
+		return nil, utils.NewError(http.StatusRequestEntityTooLarge, "file must be at most "+formatFileSize(maxSize), nil)
+	}
+	if size == 0 {
+		return nil, utils.NewFieldError("file", "must not be empty")
+	}
+
+	head := make([]byte, 512)
+	n, _ := tmp.ReadAt(head, 0)
+	file := &storedFile{
+		Key:         "attachments/" + orgID + "/" + gonanoid.Must(),
+		FileName:    attachmentName(fileName),
+		ContentType: detectContentType(fileName, head[:n]),
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ScanStatus:  scanUnscanned,
+	}
+	if !attachmentTypeAllowed(file.ContentType, limits.AttachmentTypes) {
+		return nil, utils.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("files of type %s are not allowed", file.ContentType), nil)
+	}
+
+	if a.scanner != nil {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to store file", err)
+		}
+		result, err := a.scanner.Scan(ctx, file.FileName, tmp)
+		if err != nil {
+			logger.WithError(err).Error("virus scan failed")
+			return nil, utils.NewError(http.StatusServiceUnavailable, "virus scan is unavailable, try again later", err)
+		}
+		if result.Infected {
+			logger.WithField("signature", result.Signature).Warn("infected upload refused")
+			return nil, utils.NewError(http.StatusUnprocessableEntity, "file was rejected by the virus scanner", nil)
+		}
+		file.ScanStatus = scanClean
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store file", err)
+	}
+	if err := a.storage.Put(ctx, file.Key, tmp, size, storage.PutOptions{
+		ContentType: file.ContentType,
+		Checksum:    file.Checksum,
+	}); err != nil {
+		logger.WithError(err).Error("failed to put file in storage")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store file", err)
+	}
+	return file, nil
+}
+
+// discard removes a stored file whose attachment could not be recorded
+func (a *AttachmentStore) discard(ctx context.Context, key string) {
+	if err := a.storage.Delete(ctx, key); err != nil {
+		logging.WithLayer(ctx, "service", "attachment").WithError(err).WithField("key", key).Warn("failed to discard file")
+	}
+}
+
+// signedURL returns a download link for an attachment and when it expires
+func (a *AttachmentStore) signedURL(ctx context.Context, attachment repository.Attachment) (string, time.Time, error) {
+	expires := time.Now().Add(a.opts.URLTTL).Truncate(time.Second)
+	url, err := a.storage.SignedURL(ctx, attachment.StorageKey, storage.URLOptions{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Expires:     expires,
+	})
+	if err != nil {
+		return "", time.Time{}, utils.NewError(http.StatusInternalServerError, "failed to create download link", err)
+	}
+	return url, expires, nil
+}
+
+// RunCleanup removes the stored files of deleted attachments every interval
+// until ctx is done
+func (a *AttachmentStore) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Cleanup(ctx); err != nil {
+				a.logger.WithError(err).Warn("failed to clean up attachments")
+			}
+		}
+	}
+}
+
+// Cleanup removes a batch of files queued when their attachment was deleted.
+// The queue rows stay locked while their files are removed, so several
+// instances of the API can clean up at the same time. Files that fail to be
+// removed stay queued for the next run.
+func (a *AttachmentStore) Cleanup(ctx context.Context) error {
+	logger := logging.WithLayer(ctx, "service", "attachment")
+
+	removed := 0
+	err := a.tx.WithTx(ctx, func(q repository.Querier) error {
+		keys, err := q.ListAttachmentDeletions(ctx, attachmentCleanupBatch)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := a.storage.Delete(ctx, key); err != nil {
+				logger.WithError(err).WithField("key", key).Warn("failed to delete stored file")
+				continue
+			}
+			if err := q.DeleteAttachmentDeletion(ctx, key); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		logger.WithField("count", removed).Info("stored files removed")
+	}
+	return nil
+}
+
+// validAttachmentTypes checks the types an organisation allows
+func validAttachmentTypes(types []string) error {
+	if len(types) > maxAttachmentTypes {
+		return utils.NewFieldError("attachmentTypes", fmt.Sprintf("must have at most %d entries", maxAttachmentTypes))
+	}
+	for _, t := range types {
+		if !attachmentType.MatchString(t) {
+			return utils.NewFieldError("attachmentTypes", fmt.Sprintf("%q is not a MIME type like image/png or image/*", t))
+		}
+	}
+	return nil
+}
+
+// attachmentTypeAllowed matches a content type against the allowed types,
+// no types allows everything
+func attachmentTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		if family, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(contentType, family) {
+			return true
+		}
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType sniffs the content of a file. Sniffing only recognises
+// a few formats, files it can't tell apart from plain text or a zip archive
+// are named by their extension.
+func detectContentType(fileName string, head []byte) string {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	switch detected {
+	case "application/octet-stream", "text/plain", "application/zip":
+		byExtension, _, _ := mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))))
+		if byExtension != "" && !strings.HasPrefix(byExtension, "text/html") {
+			return byExtension
+		}
+	}
+	if detected == "" {
+		return "application/octet-stream"
+	}
+	return detected
+}
+
+// attachmentName strips the path and control characters from an uploaded
+// file name and cuts it to fit
+func attachmentName(fileName string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	runes := []rune(name)
+	if len(runes) <= maxAttachmentNameLength {
+		return name
+	}
+	ext := []rune(filepath.Ext(name))
+	if len(ext) > 16 {
+		ext = nil
+	}
+	return string(runes[:maxAttachmentNameLength-len(ext)]) + string(ext)
+}
+
+func formatFileSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", size>>20)
+	case size >= 1<<10 && size%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", size>>10)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// AttachmentScanner is the hook for a virus scanner, every upload is passed
+// through it before it is stored
+type AttachmentScanner interface {
+	Scan(ctx context.Context, fileName string, content io.Reader) (AttachmentScanResult, error)
+}
+
+type AttachmentScanResult struct {
+	Infected bool `json:"infected"`
+	// Signature names what was found
+	Signature string `json:"signature"`
+}
+
+// WebhookScanner posts uploads to a scanning service, for example a small
+// wrapper around ClamAV. The service answers 200 with an
+// AttachmentScanResult as JSON.
+type WebhookScanner struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookScanner(url string) *WebhookScanner {
+	return &WebhookScanner{
+		url:    url,
+		client: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (w *WebhookScanner) Scan(ctx context.Context, fileName string, content io.Reader) (AttachmentScanResult, error) {
+	var result AttachmentScanResult
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, content)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-File-Name", fileName)
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("scanner returned %s", res.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(&result); err != nil {
+		return result, fmt.Errorf("invalid scanner response: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ChatService handles the chat rooms of an organisation, their messages and
+// the files attached to them. Rooms are only visible to their members.
+type ChatService struct {
+	repo        *repositories.ChatRepo
+	tx          *repositories.TxManager
+	attachments *AttachmentStore
+	logger      *logrus.Logger
+}
+
+func NewChatService(repo *repositories.ChatRepo, tx *repositories.TxManager, logger *logrus.Logger) *ChatService {
+	return &ChatService{
+		repo:   repo,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// SetAttachments registers where the files attached to messages are kept
+func (s *ChatService) SetAttachments(attachments *AttachmentStore) {
+	s.attachments = attachments
+}
+
+// -------------------------------------------------------------
+// Rooms
+// -------------------------------------------------------------
+
+// Rooms lists the rooms of an organisation the user is a member of
+func (s *ChatService) Rooms(ctx context.Context, orgID, userID string) ([]repository.ChatRoom, error) {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	rooms, err := s.repo.ListRooms(ctx, orgID, userID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list chat rooms")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list chats", err)
+	}
+	return rooms, nil
+}
+
+// CreateRoom creates a room with its creator and the given members, all of
+// them must be members of the organisation
+func (s *ChatService) CreateRoom(ctx context.Context, orgID, userID string, params dto.CreateChatRoomInput) (*dto.ChatRoom, error) {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+
+	var room dto.ChatRoom
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		member, err := q.OrganisationMemberExists(ctx, repository.OrganisationMemberExistsParams{
+			OrganisationID: orgID,
+			UserID:         userID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to check membership")
+			return utils.NewError(http.StatusInternalServerError, "failed to check membership", err)
+		}
+		if !member {
+			return utils.NewError(http.StatusForbidden, "not a member of the organisation", nil)
+		}
+
+		room.ChatRoom, err = q.CreateChatRoom(ctx, repository.CreateChatRoomParams{
+			ID:             gonanoid.Must(),
+			OrganisationID: orgID,
+			Name:           utils.PtrToPgText(params.Name),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create chat room")
+			return utils.NewError(http.StatusInternalServerError, "failed to create chat", err)
+		}
+
+		room.Members = []string{}
+		for i, memberID := range append([]string{userID}, params.Members...) {
+			added, err := q.AddChatMember(ctx, repository.AddChatMemberParams{
+				ID:     gonanoid.Must(),
+				RoomID: room.ID,
+				UserID: memberID,
+			})
+			if err != nil {
+				logger.WithError(err).Error("failed to add chat member")
+				return utils.NewError(http.StatusInternalServerError, "failed to create chat", err)
+			}
+			if added > 0 {
+				room.Members = append(room.Members, memberID)
+				continue
+			}
+			// Members listed twice are added once, others are not in the
+			// organisation
+			if !slices.Contains(room.Members, memberID) {
+				return utils.NewFieldError(fmt.Sprintf("members[%d]", i-1), "user is not a member of the organisation")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("room_id", room.ID).Info("chat room created")
+	return &room, nil
+}
+
+// -------------------------------------------------------------
+// Messages
+// -------------------------------------------------------------
+
+// Messages lists a page of the messages of a room with their attachments,
+// newest first
+func (s *ChatService) Messages(ctx context.Context, orgID, roomID, userID string, pagination Pagination) ([]dto.ChatMessage, error) {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"room_id": roomID,
+	})
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		_, err := getChatRoom(ctx, q, orgID, roomID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListMessages(ctx, repository.ListChatMessagesParams{
+		RoomID: roomID,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list messages")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list messages", err)
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, m := range rows {
+		ids = append(ids, m.ID)
+	}
+	attachments, err := s.repo.ListAttachments(ctx, ids)
+	if err != nil {
+		logger.WithError(err).Error("failed to list attachments")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list messages", err)
+	}
+	byMessage := map[string][]repository.Attachment{}
+	for _, a := range attachments {
+		byMessage[a.ChatMessageID.String] = append(byMessage[a.ChatMessageID.String], a)
+	}
+
+	messages := make([]dto.ChatMessage, 0, len(rows))
+	for _, m := range rows {
+		messages = append(messages, newChatMessage(m, byMessage[m.ID]))
+	}
+	return messages, nil
+}
+
+// SendMessage posts a message to a room, files are attached to it afterwards
+func (s *ChatService) SendMessage(ctx context.Context, orgID, roomID, userID string, params dto.CreateChatMessageInput) (*dto.ChatMessage, error) {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"room_id": roomID,
+		"user_id": userID,
+	})
+
+	var message repository.ChatMessage
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getChatRoom(ctx, q, orgID, roomID, userID); err != nil {
+			return err
+		}
+
+		var err error
+		message, err = q.CreateChatMessage(ctx, repository.CreateChatMessageParams{
+			ID:      gonanoid.Must(),
+			RoomID:  roomID,
+			UserID:  userID,
+			Message: params.Message,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create message")
+			return utils.NewError(http.StatusInternalServerError, "failed to send message", err)
+		}
+
+		// The sender has read everything up to their own message
+		if err := q.SetChatMemberLastMessage(ctx, repository.SetChatMemberLastMessageParams{
+			MessageID: utils.PtrToPgText(&message.ID),
+			RoomID:    roomID,
+			UserID:    userID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to update last message")
+			return utils.NewError(http.StatusInternalServerError, "failed to send message", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("message_id", message.ID).Info("message sent")
+	result := newChatMessage(message, nil)
+	return &result, nil
+}
+
+// -------------------------------------------------------------
+// Attachments
+// -------------------------------------------------------------
+
+// CreateAttachment attaches an uploaded file to a message, only its author
+// may. The file is stored before the attachment is recorded and removed
+// again when recording fails.
+func (s *ChatService) CreateAttachment(ctx context.Context, orgID, roomID, messageID, userID, fileName string, content io.Reader) (*repository.Attachment, error) {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"room_id":    roomID,
+		"message_id": messageID,
+	})
+
+	if s.attachments == nil {
+		return nil, utils.NewError(http.StatusServiceUnavailable, "attachments are not available", nil)
+	}
+	authorMessage := func(q repository.Querier) error {
+		message, err := getChatMessage(ctx, q, orgID, roomID, messageID, userID)
+		if err != nil {
+			return err
+		}
+		if message.UserID != userID {
+			return utils.NewError(http.StatusForbidden, "only the author can attach files to this message", nil)
+		}
+		return nil
+	}
+	if err := s.tx.WithTx(ctx, authorMessage); err != nil {
+		return nil, err
+	}
+
+	file, err := s.attachments.store(ctx, orgID, fileName, content)
+	if err != nil {
+		logger.WithError(err).Warn("failed to store attachment")
+		return nil, err
+	}
+
+	var attachment repository.Attachment
+	err = s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// The user may have left the room while the file was uploaded
+		if err := authorMessage(q); err != nil {
+			return err
+		}
+
+		var err error
+		attachment, err = q.CreateAttachment(ctx, repository.CreateAttachmentParams{
+			ID:             gonanoid.Must(),
+			OrganisationID: orgID,
+			ChatMessageID:  utils.PtrToPgText(&messageID),
+			UploadedBy:     utils.PtrToPgText(&userID),
+			FileName:       file.FileName,
+			ContentType:    file.ContentType,
+			Size:           file.Size,
+			Checksum:       file.Checksum,
+			StorageKey:     file.Key,
+			ScanStatus:     file.ScanStatus,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create attachment")
+			return utils.NewError(http.StatusInternalServerError, "failed to create attachment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.attachments.discard(ctx, file.Key)
+		return nil, err
+	}
+
+	logger.WithField("attachment_id", attachment.ID).Info("attachment created")
+	return &attachment, nil
+}
+
+// DownloadAttachment returns an attachment with a signed link to its file
+func (s *ChatService) DownloadAttachment(ctx context.Context, orgID, roomID, messageID, attachmentID, userID string) (*dto.ChatAttachmentDownloadResponse, error) {
+	if s.attachments == nil {
+		return nil, utils.NewError(http.StatusServiceUnavailable, "attachments are not available", nil)
+	}
+
+	var attachment repository.Attachment
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		var err error
+		attachment, err = getChatAttachment(ctx, q, orgID, roomID, messageID, attachmentID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url, expiresAt, err := s.attachments.signedURL(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ChatAttachmentDownloadResponse{
+		Attachment: attachment,
+		URL:        url,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// DeleteAttachment removes an attachment, only its uploader may. The file is
+// removed from storage by the cleanup job.
+func (s *ChatService) DeleteAttachment(ctx context.Context, orgID, roomID, messageID, attachmentID, userID string) error {
+	logger := logging.WithLayer(ctx, "service", "chat").WithFields(logrus.Fields{
+		"org_id":        orgID,
+		"room_id":       roomID,
+		"message_id":    messageID,
+		"attachment_id": attachmentID,
+	})
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		attachment, err := getChatAttachment(ctx, q, orgID, roomID, messageID, attachmentID, userID)
+		if err != nil {
+			return err
+		}
+		if attachment.UploadedBy.String != userID {
+			return utils.NewError(http.StatusForbidden, "only the uploader can delete this attachment", nil)
+		}
+
+		if _, err := q.DeleteAttachment(ctx, attachmentID); err != nil {
+			logger.WithError(err).Error("failed to delete attachment")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete attachment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("attachment deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// getChatRoom fetches a room of the organisation the user is a member of
+func getChatRoom(ctx context.Context, q repository.Querier, orgID, roomID, userID string) (repository.ChatRoom, error) {
+	room, err := q.GetUserChatRoom(ctx, repository.GetUserChatRoomParams{
+		ID:             roomID,
+		OrganisationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return room, utils.NewError(http.StatusNotFound, "chat not found", err)
+		}
+		return room, utils.NewError(http.StatusInternalServerError, "failed to fetch chat", err)
+	}
+	return room, nil
+}
+
+// getChatMessage fetches a message of a room the user is a member of
+func getChatMessage(ctx context.Context, q repository.Querier, orgID, roomID, messageID, userID string) (repository.ChatMessage, error) {
+	if _, err := getChatRoom(ctx, q, orgID, roomID, userID); err != nil {
+		return repository.ChatMessage{}, err
+	}
+
+	message, err := q.GetChatMessage(ctx, repository.GetChatMessageParams{
+		ID:     messageID,
+		RoomID: roomID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return message, utils.NewError(http.StatusNotFound, "message not found", err)
+		}
+		return message, utils.NewError(http.StatusInternalServerError, "failed to fetch message", err)
+	}
+	return message, nil
+}
+
+// getChatAttachment fetches an attachment of a message of a room the user is
+// a member of
+func getChatAttachment(ctx context.Context, q repository.Querier, orgID, roomID, messageID, attachmentID, userID string) (repository.Attachment, error) {
+	if _, err := getChatMessage(ctx, q, orgID, roomID, messageID, userID); err != nil {
+		return repository.Attachment{}, err
+	}
+
+	attachment, err := q.GetChatMessageAttachment(ctx, repository.GetChatMessageAttachmentParams{
+		ID:            attachmentID,
+		ChatMessageID: utils.PtrToPgText(&messageID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return attachment, utils.NewError(http.StatusNotFound, "attachment not found", err)
+		}
+		return attachment, utils.NewError(http.StatusInternalServerError, "failed to fetch attachment", err)
+	}
+	return attachment, nil
+}
+
+// newChatMessage wraps a message with its attachments, nil is sent as empty
+func newChatMessage(message repository.ChatMessage, attachments []repository.Attachment) dto.ChatMessage {
+	if attachments == nil {
+		attachments = []repository.Attachment{}
+	}
+	return dto.ChatMessage{
+		ChatMessage: message,
+		Attachments: attachments,
+	}
+}
@@ -0,0 +1,1324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type KanbanServiceRepos struct {
+	Kanban *repositories.KanbanRepo
+}
+
+// KanbanChange is a committed change to a kanban
+type KanbanChange struct {
+	Type     utils.MessageType
+	KanbanID string
+	Payload  any
+}
+
+// KanbanPublisher is notified of every committed kanban change
+type KanbanPublisher interface {
+	Publish(ctx context.Context, change KanbanChange)
+}
+
+type KanbanService struct {
+	repos       *KanbanServiceRepos
+	tx          *repositories.TxManager
+	publisher   KanbanPublisher
+	attachments *AttachmentStore
+	reminders   *dueReminders
+	logger      *logrus.Logger
+}
+
+func NewKanbanService(repos KanbanServiceRepos, tx *repositories.TxManager, logger *logrus.Logger) *KanbanService {
+	return &KanbanService{
+		repos:  &repos,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// SetPublisher registers the receiver of committed changes, usually the realtime hub
+func (s *KanbanService) SetPublisher(publisher KanbanPublisher) {
+	s.publisher = publisher
+}
+
+// SetAttachments registers where the files attached to cards are kept
+func (s *KanbanService) SetAttachments(attachments *AttachmentStore) {
+	s.attachments = attachments
+}
+
+// -------------------------------------------------------------
+// Kanbans
+// -------------------------------------------------------------
+func (s *KanbanService) List(ctx context.Context, projectID string) ([]repository.Kanban, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+
+	kanbans, err := s.repos.Kanban.List(ctx, projectID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list kanbans")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list kanbans", err)
+	}
+
+	logger.Infof("fetched %d kanbans", len(kanbans))
+	return kanbans, nil
+}
+
+// Create a kanban together with its default category
+func (s *KanbanService) Create(ctx context.Context, projectID string, params dto.CreateKanbanInput) (*dto.KanbanBoard, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+	logger.Infof("creating kanban: %s", params.Name)
+
+	status := params.Status
+	if status == "" {
+		status = dto.KanbanPlanning
+	}
+
+	var board dto.KanbanBoard
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		kanban, err := q.CreateKanban(ctx, repository.CreateKanbanParams{
+			ID:        gonanoid.Must(),
+			ProjectID: projectID,
+			Name:      params.Name,
+			Status:    string(status),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a kanban with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to create kanban")
+			return utils.NewError(http.StatusInternalServerError, "failed to create kanban", err)
+		}
+
+		name := dto.DefaultKanbanCategory
+		category, err := q.CreateKanbanCategory(ctx, repository.CreateKanbanCategoryParams{
+			ID:       gonanoid.Must(),
+			KanbanID: kanban.ID,
+			Name:     utils.PtrToPgText(&name),
+			Rank:     utils.RankSequence(1)[0],
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create default category")
+			return utils.NewError(http.StatusInternalServerError, "failed to create kanban", err)
+		}
+		if err := recordActivity(ctx, q, kanban.ID, dto.KanbanEntityKanban, kanban.ID, dto.ActivityCreate, nil, kanban); err != nil {
+			return err
+		}
+		if err := recordActivity(ctx, q, kanban.ID, dto.KanbanEntityCategory, category.ID, dto.ActivityCreate, nil, category); err != nil {
+			return err
+		}
+
+		labels, err := q.ListBoardLabels(ctx, kanban.ID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list labels")
+			return utils.NewError(http.StatusInternalServerError, "failed to create kanban", err)
+		}
+
+		board = dto.KanbanBoard{
+			Kanban: kanban,
+			Categories: []dto.KanbanBoardCategory{{
+				KanbanCategory: category,
+				Items:          []dto.KanbanCard{},
+			}},
+			Labels: labels,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("kanban_id", board.Kanban.ID).Info("kanban created")
+	return &board, nil
+}
+
+// Board loads a kanban with its active categories and items, grouped into
+// lanes as laneBy asks
+func (s *KanbanService) Board(ctx context.Context, projectID, kanbanID string, laneBy dto.KanbanLaneGrouping) (*dto.KanbanBoard, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	kanban, err := s.getKanban(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to fetch kanban")
+		return nil, err
+	}
+
+	categories, err := s.repos.Kanban.ListCategories(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list categories")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	items, err := s.repos.Kanban.ListItems(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	assignees, err := s.repos.Kanban.ListAssignees(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list assignees")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	labels, err := s.repos.Kanban.ListBoardLabels(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list labels")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	labelLinks, err := s.repos.Kanban.ListLabelLinks(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list item labels")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	progress, err := s.repos.Kanban.ListChecklistProgress(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list checklist progress")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	blocked, err := s.repos.Kanban.ListBlockedItems(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list blocked items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+	}
+
+	var lanes []repository.KanbanLane
+	if laneBy == dto.LaneByCustom {
+		lanes, err = s.repos.Kanban.ListLanes(ctx, kanbanID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list lanes")
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to load kanban", err)
+		}
+	}
+
+	assigneesByItem := make(map[string][]string)
+	for _, a := range assignees {
+		assigneesByItem[a.ItemID] = append(assigneesByItem[a.ItemID], a.UserID)
+	}
+	labelsByItem := make(map[string][]string)
+	for _, l := range labelLinks {
+		labelsByItem[l.ItemID] = append(labelsByItem[l.ItemID], l.LabelID)
+	}
+
+	progressByItem := make(map[string]dto.KanbanChecklistProgress, len(progress))
+	for _, p := range progress {
+		progressByItem[p.ItemID] = dto.KanbanChecklistProgress{Done: p.Done, Total: p.Total}
+	}
+
+	blockedItems := make(map[string]bool, len(blocked))
+	for _, id := range blocked {
+		blockedItems[id] = true
+	}
+
+	byCategory := make(map[string][]dto.KanbanCard, len(categories))
+	for _, item := range items {
+		card := newKanbanCard(item, assigneesByItem[item.ID], labelsByItem[item.ID])
+		card.Checklist = progressByItem[item.ID]
+		card.Blocked = blockedItems[item.ID]
+		byCategory[item.KanbanCategoryID] = append(byCategory[item.KanbanCategoryID], card)
+	}
+
+	board := dto.KanbanBoard{
+		Kanban:     *kanban,
+		Categories: make([]dto.KanbanBoardCategory, 0, len(categories)),
+		Labels:     labels,
+	}
+	for _, category := range categories {
+		categoryItems := byCategory[category.ID]
+		if categoryItems == nil {
+			categoryItems = []dto.KanbanCard{}
+		}
+		board.Categories = append(board.Categories, dto.KanbanBoardCategory{
+			KanbanCategory: category,
+			Items:          categoryItems,
+		})
+	}
+	board.LaneBy = laneBy
+	board.Lanes = boardLanes(laneBy, lanes, board.Categories)
+
+	return &board, nil
+}
+
+// Update changes the settings of a kanban, with an expected version the
+// update is refused when someone else changed the kanban in the meantime
+func (s *KanbanService) Update(ctx context.Context, projectID, kanbanID string, params dto.UpdateKanbanInput, expectedVersion *int32) (*repository.Kanban, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+	logger.Info("attempting to update kanban")
+
+	var status string
+	if params.Status != nil {
+		status = string(*params.Status)
+	}
+	var blockedMovePolicy, wipPolicy *string
+	if params.BlockedMovePolicy != nil {
+		policy := string(*params.BlockedMovePolicy)
+		blockedMovePolicy = &policy
+	}
+	if params.WipPolicy != nil {
+		policy := string(*params.WipPolicy)
+		wipPolicy = &policy
+	}
+
+	var kanban repository.Kanban
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getKanbanInProject(ctx, q, projectID, kanbanID)
+		if err != nil {
+			return err
+		}
+
+		kanban, err = q.UpdateKanban(ctx, repository.UpdateKanbanParams{
+			ID:                kanbanID,
+			ProjectID:         projectID,
+			Name:              utils.PtrToPgText(params.Name),
+			Status:            status,
+			BlockedMovePolicy: utils.PtrToPgText(blockedMovePolicy),
+			WipPolicy:         utils.PtrToPgText(wipPolicy),
+			ExpectedVersion:   utils.PtrToPgInt4(expectedVersion),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Warn("kanban version conflict")
+				latest, err := getKanbanInProject(ctx, q, projectID, kanbanID)
+				if err != nil {
+					return err
+				}
+				return utils.NewConflict("kanban was changed by someone else", latest)
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a kanban with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to update kanban")
+			return utils.NewError(http.StatusInternalServerError, "failed to update kanban", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityKanban, kanbanID, dto.ActivityUpdate, current, kanban)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("kanban updated")
+	s.publish(ctx, utils.EditKanban, kanbanID, dto.KanbanEvent{
+		Kanban:   kanban,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &kanban, nil
+}
+
+func (s *KanbanService) Delete(ctx context.Context, projectID, kanbanID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	deleted, err := s.repos.Kanban.Delete(ctx, kanbanID, projectID)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete kanban")
+		return utils.NewError(http.StatusInternalServerError, "failed to delete kanban", err)
+	}
+	if deleted == 0 {
+		logger.Warn("kanban not found")
+		return utils.NewError(http.StatusNotFound, "kanban not found", nil)
+	}
+
+	logger.Info("kanban deleted")
+	s.publish(ctx, utils.KanbanDelete, kanbanID, dto.KanbanDeletedEvent{
+		ID:       kanbanID,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return nil
+}
+
+// Archive lists a page of the soft deleted categories and items of a kanban,
+// newest first, together with how many of each there are
+func (s *KanbanService) Archive(ctx context.Context, projectID, kanbanID string, pagination Pagination) (*dto.GetKanbanArchiveResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repos.Kanban.ListDeletedCategories(ctx, repository.ListDeletedKanbanCategoriesParams{
+		KanbanID: kanbanID,
+		Limit:    pagination.Limit,
+		Offset:   pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list archived categories")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load archive", err)
+	}
+	categoryTotal, err := s.repos.Kanban.CountDeletedCategories(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to count archived categories")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load archive", err)
+	}
+
+	items, err := s.repos.Kanban.ListDeletedItems(ctx, repository.ListDeletedKanbanItemsParams{
+		KanbanID: kanbanID,
+		Limit:    pagination.Limit,
+		Offset:   pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list archived items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load archive", err)
+	}
+	itemTotal, err := s.repos.Kanban.CountDeletedItems(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to count archived items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load archive", err)
+	}
+
+	return &dto.GetKanbanArchiveResponse{
+		Categories:    categories,
+		Items:         items,
+		CategoryTotal: categoryTotal,
+		ItemTotal:     itemTotal,
+	}, nil
+}
+
+// -------------------------------------------------------------
+// Categories
+// -------------------------------------------------------------
+func (s *KanbanService) CreateCategory(ctx context.Context, projectID, kanbanID string, params dto.CreateKanbanCategoryInput) (*repository.KanbanCategory, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var category repository.KanbanCategory
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		if err := q.LockKanban(ctx, kanbanID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create category", err)
+		}
+
+		id := gonanoid.Must()
+		rank, err := placeRank(categoryRankScope(ctx, q, kanbanID, id), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		category, err = q.CreateKanbanCategory(ctx, repository.CreateKanbanCategoryParams{
+			ID:       id,
+			KanbanID: kanbanID,
+			Name:     utils.PtrToPgText(&params.Name),
+			Rank:     rank,
+			Done:     params.Done,
+			WipLimit: utils.PtrToPgInt4(params.WipLimit),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create category")
+			return utils.NewError(http.StatusInternalServerError, "failed to create category", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, category.ID, dto.ActivityCreate, nil, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("category_id", category.ID).Info("category created")
+	s.publish(ctx, utils.NewKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &category, nil
+}
+
+// UpdateCategory changes a category, with an expected version the update is
+// refused when someone else changed the category in the meantime
+func (s *KanbanService) UpdateCategory(ctx context.Context, projectID, kanbanID, categoryID string, params dto.UpdateKanbanCategoryInput, expectedVersion *int32) (*repository.KanbanCategory, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	if params.WipLimit.Value != nil && *params.WipLimit.Value < 1 {
+		return nil, utils.NewFieldError("wipLimit", "must be at least 1")
+	}
+
+	var category repository.KanbanCategory
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		category, err = q.UpdateKanbanCategory(ctx, repository.UpdateKanbanCategoryParams{
+			ID:              categoryID,
+			KanbanID:        kanbanID,
+			Name:            utils.PtrToPgText(params.Name),
+			Done:            utils.PtrToPgBool(params.Done),
+			SetWipLimit:     params.WipLimit.Set,
+			WipLimit:        utils.PtrToPgInt4(params.WipLimit.Value),
+			ExpectedVersion: utils.PtrToPgInt4(expectedVersion),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Warn("category version conflict")
+				latest, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID)
+				if err != nil {
+					return err
+				}
+				return utils.NewConflict("category was changed by someone else", latest)
+			}
+			logger.WithError(err).Error("failed to update category")
+			return utils.NewError(http.StatusInternalServerError, "failed to update category", err)
+		}
+
+		// Cards already in the column are completed or reopened with it
+		if category.Done != current.Done {
+			if err := q.SetKanbanCategoryItemsCompleted(ctx, repository.SetKanbanCategoryItemsCompletedParams{
+				KanbanCategoryID: categoryID,
+				Done:             category.Done,
+			}); err != nil {
+				logger.WithError(err).Error("failed to update completion of items")
+				return utils.NewError(http.StatusInternalServerError, "failed to update category", err)
+			}
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, categoryID, dto.ActivityUpdate, current, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("category updated")
+	s.publish(ctx, utils.EditKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &category, nil
+}
+
+// DeleteCategory moves a category and its items to the archive
+func (s *KanbanService) DeleteCategory(ctx context.Context, projectID, kanbanID, categoryID string) (*repository.KanbanCategory, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var category repository.KanbanCategory
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		category, err = q.SoftDeleteKanbanCategory(ctx, repository.SoftDeleteKanbanCategoryParams{
+			ID:       current.ID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to delete category")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete category", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, categoryID, dto.ActivityDelete, current, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("category archived")
+	s.publish(ctx, utils.DeleteKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &category, nil
+}
+
+func (s *KanbanService) RestoreCategory(ctx context.Context, projectID, kanbanID, categoryID string) (*repository.KanbanCategory, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var category repository.KanbanCategory
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+
+		current, err := q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+			ID:       categoryID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "archived category not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch category")
+			return utils.NewError(http.StatusInternalServerError, "failed to restore category", err)
+		}
+
+		category, err = q.RestoreKanbanCategory(ctx, repository.RestoreKanbanCategoryParams{
+			ID:       categoryID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "archived category not found", err)
+			}
+			logger.WithError(err).Error("failed to restore category")
+			return utils.NewError(http.StatusInternalServerError, "failed to restore category", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, categoryID, dto.ActivityRestore, current, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("category restored")
+	s.publish(ctx, utils.RestoreKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &category, nil
+}
+
+// MoveCategory places a category after or before another category of the kanban
+func (s *KanbanService) MoveCategory(ctx context.Context, projectID, kanbanID, categoryID string, params dto.MoveKanbanCategoryInput) (*repository.KanbanCategory, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var (
+		category   repository.KanbanCategory
+		rebalanced []dto.KanbanRank
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID)
+		if err != nil {
+			return err
+		}
+		if err := q.LockKanban(ctx, kanbanID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to move category", err)
+		}
+
+		after, err := siblingCategoryRank(ctx, q, kanbanID, categoryID, params.AfterID)
+		if err != nil {
+			return err
+		}
+		before, err := siblingCategoryRank(ctx, q, kanbanID, categoryID, params.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		scope := categoryRankScope(ctx, q, kanbanID, categoryID)
+		rank, err := placeRank(scope, after, before)
+		if err != nil {
+			return err
+		}
+
+		category, err = q.SetKanbanCategoryRank(ctx, repository.SetKanbanCategoryRankParams{
+			ID:   categoryID,
+			Rank: rank,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to move category")
+			return utils.NewError(http.StatusInternalServerError, "failed to move category", err)
+		}
+
+		if len(rank) > maxRankLength {
+			logger.Info("rebalancing category order")
+			rebalanced, err = rebalance(scope)
+			if err != nil {
+				return err
+			}
+			category.Rank = rankOf(rebalanced, categoryID)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, categoryID, dto.ActivityMove, current, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("category moved")
+	s.publish(ctx, utils.MoveKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	if rebalanced != nil {
+		s.publish(ctx, utils.RebalanceKanbanCategories, kanbanID, dto.KanbanRebalanceEvent{
+			Ranks:    rebalanced,
+			SenderID: utils.GetUserIDFromContext(ctx),
+		})
+	}
+	return &category, nil
+}
+
+// PermaDeleteCategory removes an archived category and all of its items
+func (s *KanbanService) PermaDeleteCategory(ctx context.Context, projectID, kanbanID, categoryID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": categoryID,
+	})
+
+	var category repository.KanbanCategory
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+
+		var err error
+		category, err = q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+			ID:       categoryID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "archived category not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch category")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete category", err)
+		}
+
+		deleted, err := q.PermaDeleteKanbanCategory(ctx, repository.PermaDeleteKanbanCategoryParams{
+			ID:       categoryID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to delete category")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete category", err)
+		}
+		if deleted == 0 {
+			logger.Warn("category is not archived")
+			return utils.NewError(http.StatusConflict, "category must be archived before it can be deleted", nil)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityCategory, categoryID, dto.ActivityPermaDelete, category, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("category permanently deleted")
+	s.publish(ctx, utils.PermaDeleteKanbanCategory, kanbanID, dto.KanbanCategoryEvent{
+		Category: category,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return nil
+}
+
+// -------------------------------------------------------------
+// Items
+// -------------------------------------------------------------
+func (s *KanbanService) CreateItem(ctx context.Context, projectID, kanbanID string, params dto.CreateKanbanItemInput) (*repository.KanbanItem, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"category_id": params.CategoryID,
+	})
+
+	if dueDateInPast(params.DueDate) {
+		return nil, utils.NewFieldError("dueDate", "must not be in the past")
+	}
+
+	priority := params.Priority
+	if priority == "" {
+		priority = dto.PriorityNone
+	}
+
+	var item repository.KanbanItem
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		lane, err := itemLane(ctx, q, kanbanID, params.LaneID)
+		if err != nil {
+			return err
+		}
+		item, err = createItem(ctx, q, projectID, kanbanID, params.CategoryID, repository.CreateKanbanItemParams{
+			Title:         params.Title,
+			Description:   utils.PtrToPgText(params.Description),
+			Priority:      string(priority),
+			DueDate:       utils.PtrToPgTimestamptz(params.DueDate),
+			EstimatedTime: utils.PtrToPgInt4(params.EstimatedTime),
+			LaneID:        lane,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("item_id", item.ID).Info("item created")
+	s.publish(ctx, utils.NewKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	s.runRules(ctx, ruleEvent{
+		Event:      dto.RuleItemCreated,
+		ProjectID:  projectID,
+		KanbanID:   kanbanID,
+		ItemID:     item.ID,
+		CategoryID: item.KanbanCategoryID,
+	})
+	return &item, nil
+}
+
+// UpdateItem changes the content of an item, with an expected version the
+// update is refused when someone else changed the item in the meantime
+func (s *KanbanService) UpdateItem(ctx context.Context, projectID, kanbanID, itemID string, params dto.UpdateKanbanItemInput, expectedVersion *int32) (*repository.KanbanItem, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if params.EstimatedTime.Value != nil && *params.EstimatedTime.Value < 0 {
+		return nil, utils.NewFieldError("estimatedTime", "must be at least 0")
+	}
+
+	var priority string
+	if params.Priority != nil {
+		priority = string(*params.Priority)
+	}
+
+	var item repository.KanbanItem
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+		// An overdue card can be saved with its due date unchanged
+		if dueDateInPast(params.DueDate.Value) && !sameDueDate(current.DueDate, params.DueDate.Value) {
+			return utils.NewFieldError("dueDate", "must not be in the past")
+		}
+
+		item, err = q.UpdateKanbanItem(ctx, repository.UpdateKanbanItemParams{
+			ID:               itemID,
+			Title:            utils.PtrToPgText(params.Title),
+			Priority:         priority,
+			SetDescription:   params.Description.Set,
+			Description:      utils.PtrToPgText(params.Description.Value),
+			SetDueDate:       params.DueDate.Set,
+			DueDate:          utils.PtrToPgTimestamptz(params.DueDate.Value),
+			SetEstimatedTime: params.EstimatedTime.Set,
+			EstimatedTime:    utils.PtrToPgInt4(params.EstimatedTime.Value),
+			ExpectedVersion:  utils.PtrToPgInt4(expectedVersion),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Warn("item version conflict")
+				latest, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+				if err != nil {
+					return err
+				}
+				return utils.NewConflict("item was changed by someone else", latest)
+			}
+			logger.WithError(err).Error("failed to update item")
+			return utils.NewError(http.StatusInternalServerError, "failed to update item", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityUpdate, current, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("item updated")
+	s.publish(ctx, utils.EditKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &item, nil
+}
+
+// MoveItem moves an item into an active category of the same kanban, placing
+// it after or before a sibling, and optionally into another lane. Concurrent
+// moves into a category are serialised by locking the category, so every
+// move sees the ranks of the previous one. Moving over a WIP limit or moving
+// a blocked item into a done column is refused or warned about depending on
+// the kanban.
+func (s *KanbanService) MoveItem(ctx context.Context, projectID, kanbanID, itemID string, params dto.MoveKanbanItemInput) (*dto.MoveKanbanItemResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"category_id": params.CategoryID,
+	})
+
+	var (
+		item          repository.KanbanItem
+		oldCategoryID string
+		oldLaneID     *string
+		rebalanced    []dto.KanbanRank
+		warnings      = []utils.APIError{}
+		doneChanged   bool
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+		target, err := getActiveCategory(ctx, q, projectID, kanbanID, params.CategoryID)
+		if err != nil {
+			return err
+		}
+		oldCategoryID = current.KanbanCategoryID
+		oldLaneID = utils.PgTextToPtr(current.LaneID)
+
+		lane := current.LaneID
+		if params.LaneID.Set {
+			lane, err = itemLane(ctx, q, kanbanID, params.LaneID.Value)
+			if err != nil {
+				return err
+			}
+		}
+		// Changing only the lane keeps the place of the item in its category
+		reorder := current.KanbanCategoryID != params.CategoryID || params.AfterID != nil || params.BeforeID != nil
+		if !reorder && lane == current.LaneID {
+			item = current
+			return nil
+		}
+		if err := q.LockKanbanCategory(ctx, params.CategoryID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to move item", err)
+		}
+
+		if current.KanbanCategoryID != params.CategoryID {
+			source, err := getActiveCategory(ctx, q, projectID, kanbanID, current.KanbanCategoryID)
+			if err != nil {
+				return err
+			}
+			doneChanged = source.Done != target.Done
+
+			kanban, err := getKanbanInProject(ctx, q, projectID, kanbanID)
+			if err != nil {
+				return err
+			}
+			warning, err := checkWipLimit(ctx, q, kanban, target, itemID)
+			if err != nil {
+				logger.WithError(err).Warn("move over WIP limit refused")
+				return err
+			}
+			if warning != nil {
+				warnings = append(warnings, *warning)
+			}
+
+			if target.Done && !source.Done {
+				warning, err := checkBlockedMove(ctx, q, kanban, itemID)
+				if err != nil {
+					logger.WithError(err).Warn("move into done column refused")
+					return err
+				}
+				if warning != nil {
+					warnings = append(warnings, *warning)
+				}
+			}
+		}
+
+		scope := itemRankScope(ctx, q, params.CategoryID, itemID)
+		rank := current.Rank
+		if reorder {
+			after, err := siblingItemRank(ctx, q, projectID, kanbanID, params.CategoryID, itemID, params.AfterID)
+			if err != nil {
+				return err
+			}
+			before, err := siblingItemRank(ctx, q, projectID, kanbanID, params.CategoryID, itemID, params.BeforeID)
+			if err != nil {
+				return err
+			}
+			rank, err = placeRank(scope, after, before)
+			if err != nil {
+				return err
+			}
+		}
+
+		item, err = q.MoveKanbanItem(ctx, repository.MoveKanbanItemParams{
+			ID:               itemID,
+			KanbanCategoryID: params.CategoryID,
+			Rank:             rank,
+			SetLane:          params.LaneID.Set,
+			LaneID:           lane,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to move item")
+			return utils.NewError(http.StatusInternalServerError, "failed to move item", err)
+		}
+
+		if reorder && len(rank) > maxRankLength {
+			logger.Info("rebalancing item order")
+			rebalanced, err = rebalance(scope)
+			if err != nil {
+				return err
+			}
+			item.Rank = rankOf(rebalanced, itemID)
+		}
+		if current.KanbanCategoryID != item.KanbanCategoryID {
+			if err := recordTransition(ctx, q, kanbanID, itemID, &current.KanbanCategoryID, item.KanbanCategoryID); err != nil {
+				return err
+			}
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityMove, current, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("item moved")
+	s.publish(ctx, utils.MoveKanbanItem, kanbanID, dto.KanbanItemMoveEvent{
+		ItemID:        item.ID,
+		OldCategoryID: oldCategoryID,
+		NewCategoryID: item.KanbanCategoryID,
+		OldLaneID:     oldLaneID,
+		NewLaneID:     utils.PgTextToPtr(item.LaneID),
+		Item:          item,
+		SenderID:      utils.GetUserIDFromContext(ctx),
+	})
+	if rebalanced != nil {
+		s.publish(ctx, utils.RebalanceKanbanItems, kanbanID, dto.KanbanRebalanceEvent{
+			CategoryID: item.KanbanCategoryID,
+			Ranks:      rebalanced,
+			SenderID:   utils.GetUserIDFromContext(ctx),
+		})
+	}
+	if doneChanged {
+		s.publishDependents(ctx, itemID)
+	}
+	if oldCategoryID != item.KanbanCategoryID {
+		s.runRules(ctx, ruleEvent{
+			Event:      dto.RuleItemMoved,
+			ProjectID:  projectID,
+			KanbanID:   kanbanID,
+			ItemID:     itemID,
+			CategoryID: item.KanbanCategoryID,
+		})
+	}
+	return &dto.MoveKanbanItemResponse{
+		Item:     item,
+		Warnings: warnings,
+	}, nil
+}
+
+// DeleteItem moves an item to the archive
+func (s *KanbanService) DeleteItem(ctx context.Context, projectID, kanbanID, itemID string) (*repository.KanbanItem, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var item repository.KanbanItem
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+
+		item, err = q.SoftDeleteKanbanItem(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to delete item")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete item", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityDelete, current, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("item archived")
+	s.publish(ctx, utils.DeleteKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	s.publishDependents(ctx, itemID)
+	return &item, nil
+}
+
+// RestoreItem brings an archived item back, its category must be active
+func (s *KanbanService) RestoreItem(ctx context.Context, projectID, kanbanID, itemID string) (*repository.KanbanItem, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var item repository.KanbanItem
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+		if !current.DeletedAt.Valid {
+			return utils.NewError(http.StatusConflict, "item is not archived", nil)
+		}
+
+		category, err := q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+			ID:       current.KanbanCategoryID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to fetch item category")
+			return utils.NewError(http.StatusInternalServerError, "failed to restore item", err)
+		}
+		if category.DeletedAt.Valid {
+			return utils.NewError(http.StatusConflict, "item category is archived, restore it first", nil)
+		}
+
+		item, err = q.RestoreKanbanItem(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to restore item")
+			return utils.NewError(http.StatusInternalServerError, "failed to restore item", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityRestore, current, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("item restored")
+	s.publish(ctx, utils.RestoreKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	s.publishDependents(ctx, itemID)
+	return &item, nil
+}
+
+// PermaDeleteItem removes an archived item
+func (s *KanbanService) PermaDeleteItem(ctx context.Context, projectID, kanbanID, itemID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var item repository.KanbanItem
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		var err error
+		item, err = getItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.PermaDeleteKanbanItem(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to delete item")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete item", err)
+		}
+		if deleted == 0 {
+			logger.Warn("item is not archived")
+			return utils.NewError(http.StatusConflict, "item must be archived before it can be deleted", nil)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityPermaDelete, item, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("item permanently deleted")
+	s.publish(ctx, utils.PermaDeleteKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// dueDateInPast reports whether a due date falls before today, a card can
+// still be due later on the current day
+func dueDateInPast(dueDate *time.Time) bool {
+	if dueDate == nil {
+		return false
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return dueDate.Before(today)
+}
+
+// sameDueDate reports whether a due date is the one stored on an item
+func sameDueDate(stored pgtype.Timestamptz, dueDate *time.Time) bool {
+	return stored.Valid && dueDate != nil && stored.Time.Equal(*dueDate)
+}
+
+// publish hands a committed change to the publisher, if one is registered
+func (s *KanbanService) publish(ctx context.Context, typ utils.MessageType, kanbanID string, payload any) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(ctx, KanbanChange{
+		Type:     typ,
+		KanbanID: kanbanID,
+		Payload:  payload,
+	})
+}
+
+func (s *KanbanService) getKanban(ctx context.Context, projectID, kanbanID string) (*repository.Kanban, error) {
+	kanban, err := s.repos.Kanban.Get(ctx, kanbanID, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "kanban not found", err)
+		}
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch kanban", err)
+	}
+	return &kanban, nil
+}
+
+// createItem adds an item last in an active category of a kanban, the id,
+// category and rank of params are filled in
+func createItem(ctx context.Context, q repository.Querier, projectID, kanbanID, categoryID string, params repository.CreateKanbanItemParams) (repository.KanbanItem, error) {
+	if _, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID); err != nil {
+		return repository.KanbanItem{}, err
+	}
+	if err := q.LockKanbanCategory(ctx, categoryID); err != nil {
+		return repository.KanbanItem{}, utils.NewError(http.StatusInternalServerError, "failed to create item", err)
+	}
+
+	params.ID = gonanoid.Must()
+	params.KanbanCategoryID = categoryID
+	rank, err := placeRank(itemRankScope(ctx, q, categoryID, params.ID), nil, nil)
+	if err != nil {
+		return repository.KanbanItem{}, err
+	}
+	params.Rank = rank
+
+	item, err := q.CreateKanbanItem(ctx, params)
+	if err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithError(err).Error("failed to create item")
+		return item, utils.NewError(http.StatusInternalServerError, "failed to create item", err)
+	}
+	if err := recordTransition(ctx, q, kanbanID, item.ID, nil, item.KanbanCategoryID); err != nil {
+		return item, err
+	}
+	return item, recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, item.ID, dto.ActivityCreate, nil, item)
+}
+
+// getKanbanInProject is the transactional variant of getKanban
+func getKanbanInProject(ctx context.Context, q repository.Querier, projectID, kanbanID string) (repository.Kanban, error) {
+	kanban, err := q.GetKanban(ctx, repository.GetKanbanParams{
+		ID:        kanbanID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return kanban, utils.NewError(http.StatusNotFound, "kanban not found", err)
+		}
+		return kanban, utils.NewError(http.StatusInternalServerError, "failed to fetch kanban", err)
+	}
+	return kanban, nil
+}
+
+// getActiveCategory fetches a category of the kanban that is not archived
+func getActiveCategory(ctx context.Context, q repository.Querier, projectID, kanbanID, categoryID string) (repository.KanbanCategory, error) {
+	if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+		return repository.KanbanCategory{}, err
+	}
+
+	category, err := q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+		ID:       categoryID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return category, utils.NewError(http.StatusNotFound, "category not found", err)
+		}
+		return category, utils.NewError(http.StatusInternalServerError, "failed to fetch category", err)
+	}
+	if category.DeletedAt.Valid {
+		return category, utils.NewError(http.StatusConflict, "category is archived", nil)
+	}
+	return category, nil
+}
+
+// getItem fetches an item of the kanban, archived or not
+func getItem(ctx context.Context, q repository.Querier, projectID, kanbanID, itemID string) (repository.KanbanItem, error) {
+	if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+		return repository.KanbanItem{}, err
+	}
+
+	item, err := q.GetKanbanItem(ctx, repository.GetKanbanItemParams{
+		ID:       itemID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return item, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		return item, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+	return item, nil
+}
+
+// getActiveItem fetches an item of the kanban that is not archived
+func getActiveItem(ctx context.Context, q repository.Querier, projectID, kanbanID, itemID string) (repository.KanbanItem, error) {
+	item, err := getItem(ctx, q, projectID, kanbanID, itemID)
+	if err != nil {
+		return item, err
+	}
+	if item.DeletedAt.Valid {
+		return item, utils.NewError(http.StatusConflict, "item is archived", nil)
+	}
+	return item, nil
+}
+
+// siblingItemRank returns the rank of a neighbour item given by id, it
+// must be another item in the target category
+func siblingItemRank(ctx context.Context, q repository.Querier, projectID, kanbanID, categoryID, itemID string, siblingID *string) (*string, error) {
+	if siblingID == nil {
+		return nil, nil
+	}
+	if *siblingID == itemID {
+		return nil, utils.NewError(http.StatusBadRequest, "an item can't be placed next to itself", nil)
+	}
+
+	sibling, err := getItem(ctx, q, projectID, kanbanID, *siblingID)
+	if err != nil {
+		return nil, err
+	}
+	if sibling.KanbanCategoryID != categoryID {
+		return nil, utils.NewError(http.StatusBadRequest, "neighbour item is in another category", nil)
+	}
+	return &sibling.Rank, nil
+}
+
+// siblingCategoryRank returns the rank of a neighbour category given by id
+func siblingCategoryRank(ctx context.Context, q repository.Querier, kanbanID, categoryID string, siblingID *string) (*string, error) {
+	if siblingID == nil {
+		return nil, nil
+	}
+	if *siblingID == categoryID {
+		return nil, utils.NewError(http.StatusBadRequest, "a category can't be placed next to itself", nil)
+	}
+
+	sibling, err := q.GetKanbanCategory(ctx, repository.GetKanbanCategoryParams{
+		ID:       *siblingID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "neighbour category not found", err)
+		}
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch category", err)
+	}
+	return &sibling.Rank, nil
+}
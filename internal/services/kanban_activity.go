@@ -0,0 +1,487 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields left out of the activity log, they change with every write
+var ignoredActivityFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+// Item fields a card can be reverted to, placement and archiving are left alone
+var revertableItemFields = map[string]func(item *repository.KanbanItem) any{
+	"title":          func(item *repository.KanbanItem) any { return &item.Title },
+	"priority":       func(item *repository.KanbanItem) any { return &item.Priority },
+	"description":    func(item *repository.KanbanItem) any { return &item.Description },
+	"due_date":       func(item *repository.KanbanItem) any { return &item.DueDate },
+	"estimated_time": func(item *repository.KanbanItem) any { return &item.EstimatedTime },
+}
+
+// The assignees and labels of an item are logged as fields of the item, as
+// sorted id lists
+type activityAssignees struct {
+	Assignees []string `json:"assignees"`
+}
+
+type activityLabels struct {
+	Labels []string `json:"labels"`
+}
+
+// -------------------------------------------------------------
+// Activity
+// -------------------------------------------------------------
+
+// Activity pages through the activity log of a kanban, newest first
+func (s *KanbanService) Activity(ctx context.Context, projectID, kanbanID string, pagination Pagination) ([]dto.KanbanActivity, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repos.Kanban.ListActivity(ctx, repository.ListKanbanActivityParams{
+		KanbanID: kanbanID,
+		Limit:    pagination.Limit,
+		Offset:   pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list activity")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list activity", err)
+	}
+
+	activity := make([]dto.KanbanActivity, 0, len(rows))
+	for _, row := range rows {
+		activity = append(activity, dto.NewKanbanActivity(row))
+	}
+	return activity, nil
+}
+
+// ItemHistory pages through the changes made to an item, newest first
+func (s *KanbanService) ItemHistory(ctx context.Context, projectID, kanbanID, itemID string, pagination Pagination) ([]dto.KanbanActivity, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	rows, err := s.repos.Kanban.ListItemActivity(ctx, repository.ListKanbanItemActivityParams{
+		ItemID: itemID,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list item history")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list item history", err)
+	}
+
+	history := make([]dto.KanbanActivity, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, dto.NewKanbanActivity(repository.ListKanbanActivityRow(row)))
+	}
+	return history, nil
+}
+
+// RevertItem puts the content of an item back to how it was right after an
+// entry of its history, by undoing every later change newest first. The
+// revert is itself logged so it can be undone the same way.
+func (s *KanbanService) RevertItem(ctx context.Context, projectID, kanbanID, itemID, activityID string) (*repository.KanbanItem, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"activity_id": activityID,
+	})
+
+	var (
+		item           repository.KanbanItem
+		assigneesAfter []repository.ListKanbanItemAssigneesRow
+		labelsAfter    []repository.KanbanLabel
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+		if err := q.LockKanbanItem(ctx, itemID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+
+		entry, err := q.GetKanbanItemActivity(ctx, repository.GetKanbanItemActivityParams{
+			ID:     activityID,
+			ItemID: itemID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "history entry not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch history entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+
+		later, err := q.ListKanbanItemActivitySince(ctx, repository.ListKanbanItemActivitySinceParams{
+			ItemID:    itemID,
+			CreatedAt: entry.CreatedAt,
+			ID:        entry.ID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to list later changes")
+			return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+
+		state := current
+		var assignees, labels []string
+		revertAssignees, revertLabels := false, false
+		for _, a := range later {
+			var changes []dto.KanbanFieldChange
+			if err := json.Unmarshal(a.Changes, &changes); err != nil {
+				logger.WithError(err).WithField("activity_id", a.ID).Error("unreadable history entry")
+				return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+			}
+			for _, change := range changes {
+				if len(change.Before) == 0 {
+					continue
+				}
+				switch change.Field {
+				case "assignees":
+					if err := json.Unmarshal(change.Before, &assignees); err != nil {
+						logger.WithError(err).WithField("field", change.Field).Error("unreadable history value")
+						return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+					}
+					revertAssignees = true
+					continue
+				case "labels":
+					if err := json.Unmarshal(change.Before, &labels); err != nil {
+						logger.WithError(err).WithField("field", change.Field).Error("unreadable history value")
+						return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+					}
+					revertLabels = true
+					continue
+				}
+				field, ok := revertableItemFields[change.Field]
+				if !ok {
+					continue
+				}
+				if err := json.Unmarshal(change.Before, field(&state)); err != nil {
+					logger.WithError(err).WithField("field", change.Field).Error("unreadable history value")
+					return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+				}
+			}
+		}
+
+		item, err = q.UpdateKanbanItem(ctx, repository.UpdateKanbanItemParams{
+			ID:               itemID,
+			Title:            utils.PtrToPgText(&state.Title),
+			Priority:         state.Priority,
+			SetDescription:   true,
+			Description:      state.Description,
+			SetDueDate:       true,
+			DueDate:          state.DueDate,
+			SetEstimatedTime: true,
+			EstimatedTime:    state.EstimatedTime,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to revert item")
+			return utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+		if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityRevert, current, item); err != nil {
+			return err
+		}
+
+		if revertAssignees {
+			if assigneesAfter, err = revertItemAssignees(ctx, q, projectID, kanbanID, itemID, assignees); err != nil {
+				return err
+			}
+		}
+		if revertLabels {
+			if labelsAfter, err = revertItemLabels(ctx, q, kanbanID, itemID, labels); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("item reverted")
+	s.publish(ctx, utils.EditKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	if assigneesAfter != nil {
+		s.publishItemUsers(ctx, utils.KanbanItemAssignees, kanbanID, itemID, assigneeIDs(assigneesAfter))
+	}
+	if labelsAfter != nil {
+		s.publishItemLabels(ctx, kanbanID, itemID, labelsAfter)
+	}
+	return &item, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// recordActivity appends a change to the activity log of a kanban, before is
+// nil for created rows and after is nil for deleted ones
+func recordActivity(ctx context.Context, q repository.Querier, kanbanID string, entity dto.KanbanEntityType, entityID string, action dto.KanbanActivityAction, before, after any) error {
+	changes, err := diffFields(before, after)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+
+	var itemID *string
+	if entity == dto.KanbanEntityItem {
+		itemID = &entityID
+	}
+
+	if _, err := q.CreateKanbanActivity(ctx, repository.CreateKanbanActivityParams{
+		ID:         gonanoid.Must(),
+		KanbanID:   kanbanID,
+		ActorID:    utils.PtrToPgText(userIDFromContext(ctx)),
+		RuleID:     utils.PtrToPgText(ruleIDFromContext(ctx)),
+		EntityType: string(entity),
+		EntityID:   entityID,
+		ItemID:     utils.PtrToPgText(itemID),
+		Action:     string(action),
+		Changes:    data,
+	}); err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithError(err).Error("failed to record activity")
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+	return nil
+}
+
+// recordAssignees logs a change of the assignees of an item, nothing is
+// logged when they stayed the same
+func recordAssignees(ctx context.Context, q repository.Querier, kanbanID, itemID string, action dto.KanbanActivityAction, before, after []string) error {
+	before, after = sortedIDs(before), sortedIDs(after)
+	if slices.Equal(before, after) {
+		return nil
+	}
+	return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, action,
+		activityAssignees{Assignees: before},
+		activityAssignees{Assignees: after},
+	)
+}
+
+// recordLabels logs a change of the labels of an item, nothing is logged
+// when they stayed the same
+func recordLabels(ctx context.Context, q repository.Querier, kanbanID, itemID string, action dto.KanbanActivityAction, before, after []string) error {
+	before, after = sortedIDs(before), sortedIDs(after)
+	if slices.Equal(before, after) {
+		return nil
+	}
+	return recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, action,
+		activityLabels{Labels: before},
+		activityLabels{Labels: after},
+	)
+}
+
+// revertItemAssignees sets the assignees of an item back to ids, users that
+// have left the project since are not assigned again
+func revertItemAssignees(ctx context.Context, q repository.Querier, projectID, kanbanID, itemID string, ids []string) ([]repository.ListKanbanItemAssigneesRow, error) {
+	before, err := q.ListKanbanItemAssignees(ctx, itemID)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+	}
+
+	target := map[string]bool{}
+	for _, id := range ids {
+		target[id] = true
+	}
+	for _, a := range before {
+		if target[a.UserID] {
+			delete(target, a.UserID)
+			continue
+		}
+		if _, err := q.RemoveKanbanItemAssignee(ctx, repository.RemoveKanbanItemAssigneeParams{
+			ItemID: itemID,
+			UserID: a.UserID,
+		}); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+	}
+	for _, id := range sortedIDs(mapKeys(target)) {
+		if err := requireProjectMember(ctx, q, projectID, id); err != nil {
+			var apiErr utils.APIError
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+				continue
+			}
+			return nil, err
+		}
+		if _, err := q.AddKanbanItemAssignee(ctx, repository.AddKanbanItemAssigneeParams{
+			ItemID: itemID,
+			UserID: id,
+		}); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+	}
+
+	after, err := q.ListKanbanItemAssignees(ctx, itemID)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+	}
+	return after, recordAssignees(ctx, q, kanbanID, itemID, dto.ActivityRevert, assigneeIDs(before), assigneeIDs(after))
+}
+
+// revertItemLabels sets the labels of an item back to ids, labels that have
+// been deleted since are left off
+func revertItemLabels(ctx context.Context, q repository.Querier, kanbanID, itemID string, ids []string) ([]repository.KanbanLabel, error) {
+	before, err := q.ListKanbanItemLabels(ctx, itemID)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+	}
+
+	target := map[string]bool{}
+	for _, id := range ids {
+		target[id] = true
+	}
+	for _, l := range before {
+		if target[l.ID] {
+			delete(target, l.ID)
+			continue
+		}
+		if _, err := q.RemoveKanbanItemLabel(ctx, repository.RemoveKanbanItemLabelParams{
+			ItemID:  itemID,
+			LabelID: l.ID,
+		}); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+	}
+	for _, id := range sortedIDs(mapKeys(target)) {
+		if _, err := q.GetBoardLabel(ctx, repository.GetBoardLabelParams{
+			ID:       id,
+			KanbanID: kanbanID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+		if _, err := q.AddKanbanItemLabel(ctx, repository.AddKanbanItemLabelParams{
+			ItemID:  itemID,
+			LabelID: id,
+		}); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+		}
+	}
+
+	after, err := q.ListKanbanItemLabels(ctx, itemID)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to revert item", err)
+	}
+	return after, recordLabels(ctx, q, kanbanID, itemID, dto.ActivityRevert, labelIDs(before), labelIDs(after))
+}
+
+// sortedIDs returns a sorted copy of ids, never nil
+func sortedIDs(ids []string) []string {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func mapKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// diffFields compares two rows by their JSON fields and returns the fields
+// that differ in name order
+func diffFields(before, after any) ([]dto.KanbanFieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(afterFields))
+	for name := range afterFields {
+		names = append(names, name)
+	}
+	for name := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []dto.KanbanFieldChange{}
+	for _, name := range names {
+		if ignoredActivityFields[name] {
+			continue
+		}
+		b, a := nullIfMissing(beforeFields[name]), nullIfMissing(afterFields[name])
+		if bytes.Equal(b, a) {
+			continue
+		}
+		changes = append(changes, dto.KanbanFieldChange{
+			Field:  name,
+			Before: b,
+			After:  a,
+		})
+	}
+	return changes, nil
+}
+
+func jsonFields(row any) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if row == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func nullIfMissing(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+const (
+	// Archived categories and cards purged per sweep, the rest wait for the next one
+	archivePurgeBatch = 100
+	// Longest retention an organisation can set for its kanban archives
+	maxArchiveRetentionDays = 3650
+)
+
+// PurgeArchives permanently deletes archived categories and cards kept longer
+// than the retention of their organisation, organisations without one keep
+// their archives forever. Each kanban room gets one event listing what was
+// removed.
+func (s *KanbanService) PurgeArchives(ctx context.Context) error {
+	logger := logging.WithLayer(ctx, "service", "kanban")
+
+	purged := map[string]*dto.KanbanArchivePurgeEvent{}
+	event := func(kanbanID string) *dto.KanbanArchivePurgeEvent {
+		if purged[kanbanID] == nil {
+			purged[kanbanID] = &dto.KanbanArchivePurgeEvent{
+				CategoryIDs: []string{},
+				ItemIDs:     []string{},
+			}
+		}
+		return purged[kanbanID]
+	}
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// Categories go first, their cards are removed with them
+		categories, err := q.ListExpiredKanbanCategories(ctx, archivePurgeBatch)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to list expired categories", err)
+		}
+		for _, category := range categories {
+			if _, err := q.PermaDeleteKanbanCategory(ctx, repository.PermaDeleteKanbanCategoryParams{
+				ID:       category.ID,
+				KanbanID: category.KanbanID,
+			}); err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to purge category", err)
+			}
+			if err := recordActivity(ctx, q, category.KanbanID, dto.KanbanEntityCategory, category.ID, dto.ActivityPermaDelete, category, nil); err != nil {
+				return err
+			}
+			e := event(category.KanbanID)
+			e.CategoryIDs = append(e.CategoryIDs, category.ID)
+		}
+
+		items, err := q.ListExpiredKanbanItems(ctx, archivePurgeBatch)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to list expired items", err)
+		}
+		for _, row := range items {
+			item, err := q.GetKanbanItem(ctx, repository.GetKanbanItemParams{
+				ID:       row.ID,
+				KanbanID: row.KanbanID,
+			})
+			if err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+			}
+			if _, err := q.PermaDeleteKanbanItem(ctx, row.ID); err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to purge item", err)
+			}
+			if err := recordActivity(ctx, q, row.KanbanID, dto.KanbanEntityItem, row.ID, dto.ActivityPermaDelete, item, nil); err != nil {
+				return err
+			}
+			e := event(row.KanbanID)
+			e.ItemIDs = append(e.ItemIDs, row.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for kanbanID, e := range purged {
+		logger.WithField("kanban_id", kanbanID).Infof("purged %d categories and %d items from the archive", len(e.CategoryIDs), len(e.ItemIDs))
+		s.publish(ctx, utils.KanbanArchivePurge, kanbanID, *e)
+	}
+	return nil
+}
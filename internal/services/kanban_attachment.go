@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// -------------------------------------------------------------
+// Attachments
+// -------------------------------------------------------------
+
+// Attachments lists the files attached to an item, oldest first
+func (s *KanbanService) Attachments(ctx context.Context, projectID, kanbanID, itemID string) ([]repository.Attachment, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	attachments, err := s.repos.Kanban.ListAttachments(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list attachments")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list attachments", err)
+	}
+	return attachments, nil
+}
+
+// CreateAttachment attaches an uploaded file to an active item. The file is
+// stored before the attachment is recorded and removed again when recording
+// fails.
+func (s *KanbanService) CreateAttachment(ctx context.Context, orgID, projectID, kanbanID, itemID, fileName string, content io.Reader) (*repository.Attachment, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if s.attachments == nil {
+		return nil, utils.NewError(http.StatusServiceUnavailable, "attachments are not available", nil)
+	}
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		_, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.attachments.store(ctx, orgID, fileName, content)
+	if err != nil {
+		logger.WithError(err).Warn("failed to store attachment")
+		return nil, err
+	}
+
+	var attachment repository.Attachment
+	err = s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// The item may have been archived while the file was uploaded
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		var err error
+		attachment, err = q.CreateAttachment(ctx, repository.CreateAttachmentParams{
+			ID:             gonanoid.Must(),
+			OrganisationID: orgID,
+			ItemID:         utils.PtrToPgText(&itemID),
+			UploadedBy:     utils.PtrToPgText(userIDFromContext(ctx)),
+			FileName:       file.FileName,
+			ContentType:    file.ContentType,
+			Size:           file.Size,
+			Checksum:       file.Checksum,
+			StorageKey:     file.Key,
+			ScanStatus:     file.ScanStatus,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create attachment")
+			return utils.NewError(http.StatusInternalServerError, "failed to create attachment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.attachments.discard(ctx, file.Key)
+		return nil, err
+	}
+
+	logger.WithField("attachment_id", attachment.ID).Info("attachment created")
+	s.publish(ctx, utils.NewKanbanItemAttachment, kanbanID, dto.KanbanAttachmentEvent{
+		ItemID:     itemID,
+		Attachment: attachment,
+		SenderID:   utils.GetUserIDFromContext(ctx),
+	})
+	return &attachment, nil
+}
+
+// DownloadAttachment returns an attachment with a signed link to its file
+func (s *KanbanService) DownloadAttachment(ctx context.Context, projectID, kanbanID, itemID, attachmentID string) (*dto.KanbanAttachmentDownloadResponse, error) {
+	if s.attachments == nil {
+		return nil, utils.NewError(http.StatusServiceUnavailable, "attachments are not available", nil)
+	}
+
+	var attachment repository.Attachment
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		var err error
+		attachment, err = getAttachment(ctx, q, projectID, kanbanID, itemID, attachmentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url, expiresAt, err := s.attachments.signedURL(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.KanbanAttachmentDownloadResponse{
+		Attachment: attachment,
+		URL:        url,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// DeleteAttachment removes an attachment, only its uploader or a moderator
+// may. The file is removed from storage by the cleanup job.
+func (s *KanbanService) DeleteAttachment(ctx context.Context, projectID, kanbanID, itemID, attachmentID, userID string, moderator bool) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"item_id":       itemID,
+		"attachment_id": attachmentID,
+	})
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		attachment, err := getAttachment(ctx, q, projectID, kanbanID, itemID, attachmentID)
+		if err != nil {
+			return err
+		}
+		if !moderator && attachment.UploadedBy.String != userID {
+			return utils.NewError(http.StatusForbidden, "only the uploader can delete this attachment", nil)
+		}
+
+		if _, err := q.DeleteAttachment(ctx, attachmentID); err != nil {
+			logger.WithError(err).Error("failed to delete attachment")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete attachment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("attachment deleted")
+	s.publish(ctx, utils.DeleteKanbanItemAttachment, kanbanID, dto.KanbanAttachmentDeleteEvent{
+		ItemID:       itemID,
+		AttachmentID: attachmentID,
+		SenderID:     utils.GetUserIDFromContext(ctx),
+	})
+	return nil
+}
+
+// getAttachment fetches an attachment of an item of the kanban
+func getAttachment(ctx context.Context, q repository.Querier, projectID, kanbanID, itemID, attachmentID string) (repository.Attachment, error) {
+	if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+		return repository.Attachment{}, err
+	}
+
+	attachment, err := q.GetItemAttachment(ctx, repository.GetItemAttachmentParams{
+		ID:     attachmentID,
+		ItemID: utils.PtrToPgText(&itemID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return attachment, utils.NewError(http.StatusNotFound, "attachment not found", err)
+		}
+		return attachment, utils.NewError(http.StatusInternalServerError, "failed to fetch attachment", err)
+	}
+	return attachment, nil
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// BulkItems applies one action to a set of items in a single transaction, if
+// any item can't be changed nothing is. The room gets one event for the whole
+// batch. Moves follow the same WIP and blocked rules as single moves, adding
+// a label or assignee a card already has and removing one it doesn't have
+// are no-ops.
+func (s *KanbanService) BulkItems(ctx context.Context, projectID, kanbanID string, params dto.BulkKanbanItemsInput) (*dto.BulkKanbanItemsResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"action":     params.Action,
+		"items":      len(params.ItemIDs),
+	})
+
+	if err := validateBulkItems(params); err != nil {
+		return nil, err
+	}
+
+	event := dto.KanbanItemsBulkEvent{
+		Action:   params.Action,
+		Items:    make([]repository.KanbanItem, 0, len(params.ItemIDs)),
+		SenderID: utils.GetUserIDFromContext(ctx),
+	}
+	var (
+		warnings    = []utils.APIError{}
+		rebalanced  []dto.KanbanRank
+		doneChanged []string
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+
+		switch params.Action {
+		case dto.BulkMove:
+			event.OldCategoryIDs = make(map[string]string, len(params.ItemIDs))
+			result, err := bulkMoveItems(ctx, q, projectID, kanbanID, *params.CategoryID, params.ItemIDs)
+			if err != nil {
+				return err
+			}
+			for _, moved := range result.moved {
+				event.Items = append(event.Items, moved.item)
+				event.OldCategoryIDs[moved.item.ID] = moved.oldCategoryID
+				if moved.doneChanged {
+					doneChanged = append(doneChanged, moved.item.ID)
+				}
+			}
+			warnings = append(warnings, result.warnings...)
+			rebalanced = result.rebalanced
+
+		case dto.BulkLabel, dto.BulkUnlabel:
+			if params.Action == dto.BulkLabel {
+				if _, err := q.GetBoardLabel(ctx, repository.GetBoardLabelParams{
+					ID:       *params.LabelID,
+					KanbanID: kanbanID,
+				}); err != nil {
+					if errors.Is(err, pgx.ErrNoRows) {
+						return utils.NewFieldError("labelId", "label not found")
+					}
+					logger.WithError(err).Error("failed to fetch label")
+					return utils.NewError(http.StatusInternalServerError, "failed to fetch label", err)
+				}
+			}
+			event.Labels = make(map[string][]string, len(params.ItemIDs))
+			for i, itemID := range params.ItemIDs {
+				item, err := getBulkItem(ctx, q, kanbanID, params.Action == dto.BulkLabel, i, itemID)
+				if err != nil {
+					return err
+				}
+				before, err := q.ListKanbanItemLabels(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).Error("failed to list labels")
+					return utils.NewError(http.StatusInternalServerError, "failed to change labels", err)
+				}
+				if params.Action == dto.BulkLabel {
+					_, err = q.AddKanbanItemLabel(ctx, repository.AddKanbanItemLabelParams{
+						ItemID:  itemID,
+						LabelID: *params.LabelID,
+					})
+				} else {
+					_, err = q.RemoveKanbanItemLabel(ctx, repository.RemoveKanbanItemLabelParams{
+						ItemID:  itemID,
+						LabelID: *params.LabelID,
+					})
+				}
+				if err != nil {
+					logger.WithError(err).WithField("item_id", itemID).Error("failed to change item label")
+					return utils.NewError(http.StatusInternalServerError, "failed to change labels", err)
+				}
+
+				labels, err := q.ListKanbanItemLabels(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).Error("failed to list labels")
+					return utils.NewError(http.StatusInternalServerError, "failed to change labels", err)
+				}
+				if err := recordLabels(ctx, q, kanbanID, itemID, dto.ActivityUpdate, labelIDs(before), labelIDs(labels)); err != nil {
+					return err
+				}
+				event.Items = append(event.Items, item)
+				event.Labels[itemID] = labelIDs(labels)
+			}
+
+		case dto.BulkAssign, dto.BulkUnassign:
+			if params.Action == dto.BulkAssign {
+				if err := requireProjectMember(ctx, q, projectID, *params.UserID); err != nil {
+					logger.WithError(err).Warn("invalid assignee")
+					return err
+				}
+			}
+			event.Assignees = make(map[string][]string, len(params.ItemIDs))
+			for i, itemID := range params.ItemIDs {
+				item, err := getBulkItem(ctx, q, kanbanID, params.Action == dto.BulkAssign, i, itemID)
+				if err != nil {
+					return err
+				}
+				before, err := q.ListKanbanItemAssignees(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).Error("failed to list assignees")
+					return utils.NewError(http.StatusInternalServerError, "failed to change assignees", err)
+				}
+				if params.Action == dto.BulkAssign {
+					_, err = q.AddKanbanItemAssignee(ctx, repository.AddKanbanItemAssigneeParams{
+						ItemID: itemID,
+						UserID: *params.UserID,
+					})
+				} else {
+					_, err = q.RemoveKanbanItemAssignee(ctx, repository.RemoveKanbanItemAssigneeParams{
+						ItemID: itemID,
+						UserID: *params.UserID,
+					})
+				}
+				if err != nil {
+					logger.WithError(err).WithField("item_id", itemID).Error("failed to change item assignee")
+					return utils.NewError(http.StatusInternalServerError, "failed to change assignees", err)
+				}
+
+				assignees, err := q.ListKanbanItemAssignees(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).Error("failed to list assignees")
+					return utils.NewError(http.StatusInternalServerError, "failed to change assignees", err)
+				}
+				if err := recordAssignees(ctx, q, kanbanID, itemID, dto.ActivityUpdate, assigneeIDs(before), assigneeIDs(assignees)); err != nil {
+					return err
+				}
+				event.Items = append(event.Items, item)
+				event.Assignees[itemID] = assigneeIDs(assignees)
+			}
+
+		case dto.BulkArchive:
+			for i, itemID := range params.ItemIDs {
+				current, err := getBulkItem(ctx, q, kanbanID, true, i, itemID)
+				if err != nil {
+					return err
+				}
+				item, err := q.SoftDeleteKanbanItem(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).WithField("item_id", itemID).Error("failed to archive item")
+					return utils.NewError(http.StatusInternalServerError, "failed to archive items", err)
+				}
+				if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityDelete, current, item); err != nil {
+					return err
+				}
+				event.Items = append(event.Items, item)
+			}
+
+		case dto.BulkDelete:
+			for i, itemID := range params.ItemIDs {
+				item, err := getBulkItem(ctx, q, kanbanID, false, i, itemID)
+				if err != nil {
+					return err
+				}
+				deleted, err := q.PermaDeleteKanbanItem(ctx, itemID)
+				if err != nil {
+					logger.WithError(err).WithField("item_id", itemID).Error("failed to delete item")
+					return utils.NewError(http.StatusInternalServerError, "failed to delete items", err)
+				}
+				if deleted == 0 {
+					return utils.NewFieldError(fmt.Sprintf("itemIds[%d]", i), "item must be archived before it can be deleted")
+				}
+				if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityPermaDelete, item, nil); err != nil {
+					return err
+				}
+				event.Items = append(event.Items, item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("bulk item change rolled back")
+		return nil, err
+	}
+
+	logger.Info("bulk item change applied")
+	s.publish(ctx, utils.BulkKanbanItems, kanbanID, event)
+	if rebalanced != nil {
+		s.publish(ctx, utils.RebalanceKanbanItems, kanbanID, dto.KanbanRebalanceEvent{
+			CategoryID: *params.CategoryID,
+			Ranks:      rebalanced,
+			SenderID:   utils.GetUserIDFromContext(ctx),
+		})
+	}
+
+	switch params.Action {
+	case dto.BulkMove:
+		for _, itemID := range doneChanged {
+			s.publishDependents(ctx, itemID)
+		}
+		for _, item := range event.Items {
+			if event.OldCategoryIDs[item.ID] == item.KanbanCategoryID {
+				continue
+			}
+			s.runRules(ctx, ruleEvent{
+				Event:      dto.RuleItemMoved,
+				ProjectID:  projectID,
+				KanbanID:   kanbanID,
+				ItemID:     item.ID,
+				CategoryID: item.KanbanCategoryID,
+			})
+		}
+	case dto.BulkArchive:
+		for _, item := range event.Items {
+			s.publishDependents(ctx, item.ID)
+		}
+	}
+	return &dto.BulkKanbanItemsResponse{
+		Items:    event.Items,
+		Warnings: warnings,
+	}, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+type bulkMovedItem struct {
+	item          repository.KanbanItem
+	oldCategoryID string
+	doneChanged   bool
+}
+
+type bulkMoveResult struct {
+	moved      []bulkMovedItem
+	warnings   []utils.APIError
+	rebalanced []dto.KanbanRank
+}
+
+// bulkMoveItems appends the items to a category in the given order. The
+// category stays locked for the whole batch, so each WIP check counts the
+// items moved before it.
+func bulkMoveItems(ctx context.Context, q repository.Querier, projectID, kanbanID, categoryID string, itemIDs []string) (*bulkMoveResult, error) {
+	target, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID)
+	if err != nil {
+		return nil, notFoundAsField(err, "categoryId", "category not found")
+	}
+	if err := q.LockKanbanCategory(ctx, categoryID); err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to move items", err)
+	}
+	kanban, err := getKanbanInProject(ctx, q, projectID, kanbanID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &bulkMoveResult{warnings: []utils.APIError{}}
+	seen := make(map[string]bool)
+	var longest int
+	for i, itemID := range itemIDs {
+		current, err := getBulkItem(ctx, q, kanbanID, true, i, itemID)
+		if err != nil {
+			return nil, err
+		}
+
+		var doneChanged bool
+		if current.KanbanCategoryID != categoryID {
+			source, err := getActiveCategory(ctx, q, projectID, kanbanID, current.KanbanCategoryID)
+			if err != nil {
+				return nil, err
+			}
+			doneChanged = source.Done != target.Done
+
+			warning, err := checkWipLimit(ctx, q, kanban, target, itemID)
+			if err != nil {
+				return nil, err
+			}
+			if warning != nil && !seen[warning.Message] {
+				seen[warning.Message] = true
+				result.warnings = append(result.warnings, *warning)
+			}
+
+			if target.Done && !source.Done {
+				warning, err := checkBlockedMove(ctx, q, kanban, itemID)
+				if err != nil {
+					return nil, err
+				}
+				if warning != nil && !seen[warning.Message] {
+					seen[warning.Message] = true
+					result.warnings = append(result.warnings, *warning)
+				}
+			}
+		}
+
+		rank, err := placeRank(itemRankScope(ctx, q, categoryID, itemID), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		item, err := q.MoveKanbanItem(ctx, repository.MoveKanbanItemParams{
+			ID:               itemID,
+			KanbanCategoryID: categoryID,
+			Rank:             rank,
+		})
+		if err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to move items", err)
+		}
+		longest = max(longest, len(rank))
+
+		if current.KanbanCategoryID != categoryID {
+			if err := recordTransition(ctx, q, kanbanID, itemID, &current.KanbanCategoryID, categoryID); err != nil {
+				return nil, err
+			}
+		}
+		if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, itemID, dto.ActivityMove, current, item); err != nil {
+			return nil, err
+		}
+		result.moved = append(result.moved, bulkMovedItem{
+			item:          item,
+			oldCategoryID: current.KanbanCategoryID,
+			doneChanged:   doneChanged,
+		})
+	}
+
+	if longest > maxRankLength {
+		result.rebalanced, err = rebalance(itemRankScope(ctx, q, categoryID, ""))
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.moved {
+			result.moved[i].item.Rank = rankOf(result.rebalanced, result.moved[i].item.ID)
+		}
+	}
+	return result, nil
+}
+
+// getBulkItem fetches one item of a bulk request, a missing or archived item
+// is reported on its position in the request. The kanban must already be
+// known to belong to the project.
+func getBulkItem(ctx context.Context, q repository.Querier, kanbanID string, active bool, index int, itemID string) (repository.KanbanItem, error) {
+	field := fmt.Sprintf("itemIds[%d]", index)
+	item, err := q.GetKanbanItem(ctx, repository.GetKanbanItemParams{
+		ID:       itemID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return item, utils.NewFieldError(field, "item not found")
+		}
+		return item, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+	if active && item.DeletedAt.Valid {
+		return item, utils.NewFieldError(field, "item is archived")
+	}
+	return item, nil
+}
+
+// validateBulkItems checks the action names what it needs
+func validateBulkItems(params dto.BulkKanbanItemsInput) error {
+	switch params.Action {
+	case dto.BulkMove:
+		if params.CategoryID == nil {
+			return utils.NewFieldError("categoryId", "is required")
+		}
+	case dto.BulkLabel, dto.BulkUnlabel:
+		if params.LabelID == nil {
+			return utils.NewFieldError("labelId", "is required")
+		}
+	case dto.BulkAssign, dto.BulkUnassign:
+		if params.UserID == nil {
+			return utils.NewFieldError("userId", "is required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// KanbanItemFilter narrows down the items listed across a project, nil
+// fields are not filtered on
+type KanbanItemFilter struct {
+	KanbanID   *string
+	AssigneeID *string
+	WatcherID  *string
+	LabelID    *string
+}
+
+// -------------------------------------------------------------
+// Cards
+// -------------------------------------------------------------
+
+// Item loads a single item with its assignees, watchers, labels and links
+func (s *KanbanService) Item(ctx context.Context, projectID, kanbanID, itemID string) (*dto.KanbanItemDetail, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	assignees, err := s.repos.Kanban.ListItemAssignees(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list assignees")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	watchers, err := s.repos.Kanban.ListItemWatchers(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list watchers")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	labels, err := s.repos.Kanban.ListItemLabels(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list labels")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	links, err := s.repos.Kanban.ListItemLinks(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list links")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	blockers, err := s.repos.Kanban.ListItemBlockers(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list blockers")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	return &dto.KanbanItemDetail{
+		Item:      item,
+		Assignees: assignees,
+		Watchers:  watchers,
+		Labels:    labels,
+		Links:     links,
+		Blocked:   len(blockers) > 0,
+	}, nil
+}
+
+// ListProjectItems lists active items across every kanban of a project
+func (s *KanbanService) ListProjectItems(ctx context.Context, projectID string, filter KanbanItemFilter, pagination Pagination) ([]repository.ListProjectKanbanItemsRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+
+	items, err := s.repos.Kanban.ListProjectItems(ctx, repository.ListProjectKanbanItemsParams{
+		ProjectID:  projectID,
+		KanbanID:   utils.PtrToPgText(filter.KanbanID),
+		AssigneeID: utils.PtrToPgText(filter.AssigneeID),
+		WatcherID:  utils.PtrToPgText(filter.WatcherID),
+		LabelID:    utils.PtrToPgText(filter.LabelID),
+		Limit:      pagination.Limit,
+		Offset:     pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list items", err)
+	}
+
+	logger.Infof("fetched %d items", len(items))
+	return items, nil
+}
+
+// -------------------------------------------------------------
+// Assignees
+// -------------------------------------------------------------
+
+// AddAssignee assigns a project member to an item
+func (s *KanbanService) AddAssignee(ctx context.Context, projectID, kanbanID, itemID, userID string) ([]repository.ListKanbanItemAssigneesRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"assignee_id": userID,
+	})
+
+	var assignees []repository.ListKanbanItemAssigneesRow
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		if err := requireProjectMember(ctx, q, projectID, userID); err != nil {
+			logger.WithError(err).Warn("invalid assignee")
+			return err
+		}
+
+		before, err := q.ListKanbanItemAssignees(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list assignees")
+			return utils.NewError(http.StatusInternalServerError, "failed to add assignee", err)
+		}
+
+		if _, err := q.AddKanbanItemAssignee(ctx, repository.AddKanbanItemAssigneeParams{
+			ItemID: itemID,
+			UserID: userID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to add assignee")
+			return utils.NewError(http.StatusInternalServerError, "failed to add assignee", err)
+		}
+
+		assignees, err = q.ListKanbanItemAssignees(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list assignees")
+			return utils.NewError(http.StatusInternalServerError, "failed to add assignee", err)
+		}
+		return recordAssignees(ctx, q, kanbanID, itemID, dto.ActivityUpdate, assigneeIDs(before), assigneeIDs(assignees))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("assignee added")
+	s.publishItemUsers(ctx, utils.KanbanItemAssignees, kanbanID, itemID, assigneeIDs(assignees))
+	return assignees, nil
+}
+
+func (s *KanbanService) RemoveAssignee(ctx context.Context, projectID, kanbanID, itemID, userID string) ([]repository.ListKanbanItemAssigneesRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":  projectID,
+		"kanban_id":   kanbanID,
+		"item_id":     itemID,
+		"assignee_id": userID,
+	})
+
+	var assignees []repository.ListKanbanItemAssigneesRow
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		before, err := q.ListKanbanItemAssignees(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list assignees")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove assignee", err)
+		}
+
+		rows, err := q.RemoveKanbanItemAssignee(ctx, repository.RemoveKanbanItemAssigneeParams{
+			ItemID: itemID,
+			UserID: userID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to remove assignee")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove assignee", err)
+		}
+		if rows == 0 {
+			return utils.NewError(http.StatusNotFound, "user is not assigned to the item", nil)
+		}
+
+		assignees, err = q.ListKanbanItemAssignees(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list assignees")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove assignee", err)
+		}
+		return recordAssignees(ctx, q, kanbanID, itemID, dto.ActivityUpdate, assigneeIDs(before), assigneeIDs(assignees))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("assignee removed")
+	s.publishItemUsers(ctx, utils.KanbanItemAssignees, kanbanID, itemID, assigneeIDs(assignees))
+	return assignees, nil
+}
+
+// -------------------------------------------------------------
+// Watchers
+// -------------------------------------------------------------
+
+// AddWatcher lets a member of the organisation follow an item
+func (s *KanbanService) AddWatcher(ctx context.Context, orgID, projectID, kanbanID, itemID, userID string) ([]repository.ListKanbanItemWatchersRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"watcher_id": userID,
+	})
+
+	var watchers []repository.ListKanbanItemWatchersRow
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		isMember, err := q.OrganisationMemberExists(ctx, repository.OrganisationMemberExistsParams{
+			OrganisationID: orgID,
+			UserID:         userID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to check organisation membership")
+			return utils.NewError(http.StatusInternalServerError, "failed to check membership", err)
+		}
+		if !isMember {
+			logger.Warn("user is not a member of the organisation")
+			return utils.NewError(http.StatusBadRequest, "user is not a member of the organisation", nil)
+		}
+
+		if _, err := q.AddKanbanItemWatcher(ctx, repository.AddKanbanItemWatcherParams{
+			ItemID: itemID,
+			UserID: userID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to add watcher")
+			return utils.NewError(http.StatusInternalServerError, "failed to add watcher", err)
+		}
+
+		watchers, err = q.ListKanbanItemWatchers(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list watchers")
+			return utils.NewError(http.StatusInternalServerError, "failed to add watcher", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("watcher added")
+	s.publishItemUsers(ctx, utils.KanbanItemWatchers, kanbanID, itemID, watcherIDs(watchers))
+	return watchers, nil
+}
+
+func (s *KanbanService) RemoveWatcher(ctx context.Context, projectID, kanbanID, itemID, userID string) ([]repository.ListKanbanItemWatchersRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"watcher_id": userID,
+	})
+
+	var watchers []repository.ListKanbanItemWatchersRow
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		rows, err := q.RemoveKanbanItemWatcher(ctx, repository.RemoveKanbanItemWatcherParams{
+			ItemID: itemID,
+			UserID: userID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to remove watcher")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove watcher", err)
+		}
+		if rows == 0 {
+			return utils.NewError(http.StatusNotFound, "user is not watching the item", nil)
+		}
+
+		watchers, err = q.ListKanbanItemWatchers(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list watchers")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove watcher", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("watcher removed")
+	s.publishItemUsers(ctx, utils.KanbanItemWatchers, kanbanID, itemID, watcherIDs(watchers))
+	return watchers, nil
+}
+
+// -------------------------------------------------------------
+// Item labels
+// -------------------------------------------------------------
+
+// AddItemLabel puts an organisation or kanban label on an item
+func (s *KanbanService) AddItemLabel(ctx context.Context, projectID, kanbanID, itemID, labelID string) ([]repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"label_id":   labelID,
+	})
+
+	var labels []repository.KanbanLabel
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		_, err := q.GetBoardLabel(ctx, repository.GetBoardLabelParams{
+			ID:       labelID,
+			KanbanID: kanbanID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "label not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch label")
+			return utils.NewError(http.StatusInternalServerError, "failed to fetch label", err)
+		}
+
+		before, err := q.ListKanbanItemLabels(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list labels")
+			return utils.NewError(http.StatusInternalServerError, "failed to add label", err)
+		}
+
+		if _, err := q.AddKanbanItemLabel(ctx, repository.AddKanbanItemLabelParams{
+			ItemID:  itemID,
+			LabelID: labelID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to add label")
+			return utils.NewError(http.StatusInternalServerError, "failed to add label", err)
+		}
+
+		labels, err = q.ListKanbanItemLabels(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list labels")
+			return utils.NewError(http.StatusInternalServerError, "failed to add label", err)
+		}
+		return recordLabels(ctx, q, kanbanID, itemID, dto.ActivityUpdate, labelIDs(before), labelIDs(labels))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("label added")
+	s.publishItemLabels(ctx, kanbanID, itemID, labels)
+	return labels, nil
+}
+
+func (s *KanbanService) RemoveItemLabel(ctx context.Context, projectID, kanbanID, itemID, labelID string) ([]repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"label_id":   labelID,
+	})
+
+	var labels []repository.KanbanLabel
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		before, err := q.ListKanbanItemLabels(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list labels")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove label", err)
+		}
+
+		rows, err := q.RemoveKanbanItemLabel(ctx, repository.RemoveKanbanItemLabelParams{
+			ItemID:  itemID,
+			LabelID: labelID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to remove label")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove label", err)
+		}
+		if rows == 0 {
+			return utils.NewError(http.StatusNotFound, "label is not on the item", nil)
+		}
+
+		labels, err = q.ListKanbanItemLabels(ctx, itemID)
+		if err != nil {
+			logger.WithError(err).Error("failed to list labels")
+			return utils.NewError(http.StatusInternalServerError, "failed to remove label", err)
+		}
+		return recordLabels(ctx, q, kanbanID, itemID, dto.ActivityUpdate, labelIDs(before), labelIDs(labels))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("label removed")
+	s.publishItemLabels(ctx, kanbanID, itemID, labels)
+	return labels, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+func (s *KanbanService) publishItemUsers(ctx context.Context, typ utils.MessageType, kanbanID, itemID string, ids []string) {
+	s.publish(ctx, typ, kanbanID, dto.KanbanItemUsersEvent{
+		ItemID:   itemID,
+		UserIDs:  ids,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+}
+
+func (s *KanbanService) publishItemLabels(ctx context.Context, kanbanID, itemID string, labels []repository.KanbanLabel) {
+	s.publish(ctx, utils.KanbanItemLabels, kanbanID, dto.KanbanItemLabelsEvent{
+		ItemID:   itemID,
+		LabelIDs: labelIDs(labels),
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+}
+
+// requireProjectMember makes sure a user can be assigned work in a project
+func requireProjectMember(ctx context.Context, q repository.Querier, projectID, userID string) error {
+	_, err := q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError(http.StatusBadRequest, "user is not a member of the project", err)
+		}
+		return utils.NewError(http.StatusInternalServerError, "failed to check membership", err)
+	}
+	return nil
+}
+
+// newKanbanCard wraps an item for the board, nil lists are sent as empty
+func newKanbanCard(item repository.KanbanItem, assignees, labels []string) dto.KanbanCard {
+	if assignees == nil {
+		assignees = []string{}
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	return dto.KanbanCard{
+		KanbanItem: item,
+		Assignees:  assignees,
+		Labels:     labels,
+	}
+}
+
+func assigneeIDs(rows []repository.ListKanbanItemAssigneesRow) []string {
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.UserID)
+	}
+	return ids
+}
+
+func labelIDs(labels []repository.KanbanLabel) []string {
+	ids := make([]string, 0, len(labels))
+	for _, l := range labels {
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
+
+func watcherIDs(rows []repository.ListKanbanItemWatchersRow) []string {
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.UserID)
+	}
+	return ids
+}
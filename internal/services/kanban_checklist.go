@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Card titles are shorter than checklist titles, promoted entries are cut
+const maxItemTitleLength = 40
+
+// -------------------------------------------------------------
+// Checklists
+// -------------------------------------------------------------
+
+// Checklist lists the entries of an item in order with its progress
+func (s *KanbanService) Checklist(ctx context.Context, projectID, kanbanID, itemID string) (*dto.KanbanChecklistResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	entries, err := s.repos.Kanban.ListChecklist(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list checklist")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list checklist", err)
+	}
+	return newKanbanChecklist(entries), nil
+}
+
+func (s *KanbanService) CreateChecklistEntry(ctx context.Context, projectID, kanbanID, itemID string, params dto.CreateKanbanChecklistEntryInput) (*repository.KanbanChecklistEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	var (
+		entry   repository.KanbanChecklistEntry
+		entries []repository.KanbanChecklistEntry
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+		if err := q.LockKanbanItem(ctx, itemID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create checklist entry", err)
+		}
+		if params.AssigneeID != nil {
+			if err := requireProjectMember(ctx, q, projectID, *params.AssigneeID); err != nil {
+				logger.WithError(err).Warn("invalid checklist assignee")
+				return err
+			}
+		}
+
+		id := gonanoid.Must()
+		rank, err := placeRank(checklistRankScope(ctx, q, itemID, id), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		entry, err = q.CreateKanbanChecklistEntry(ctx, repository.CreateKanbanChecklistEntryParams{
+			ID:         id,
+			ItemID:     itemID,
+			Title:      params.Title,
+			AssigneeID: utils.PtrToPgText(params.AssigneeID),
+			DueDate:    utils.PtrToPgTimestamptz(params.DueDate),
+			Rank:       rank,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to create checklist entry", err)
+		}
+
+		entries, err = q.ListKanbanChecklistEntries(ctx, itemID)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create checklist entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("entry_id", entry.ID).Info("checklist entry created")
+	s.publishChecklist(ctx, kanbanID, itemID, entries)
+	return &entry, nil
+}
+
+func (s *KanbanService) UpdateChecklistEntry(ctx context.Context, projectID, kanbanID, itemID, entryID string, params dto.UpdateKanbanChecklistEntryInput) (*repository.KanbanChecklistEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var (
+		entry   repository.KanbanChecklistEntry
+		entries []repository.KanbanChecklistEntry
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+		if _, err := getChecklistEntry(ctx, q, itemID, entryID); err != nil {
+			return err
+		}
+		if params.AssigneeID.Value != nil {
+			if err := requireProjectMember(ctx, q, projectID, *params.AssigneeID.Value); err != nil {
+				logger.WithError(err).Warn("invalid checklist assignee")
+				return err
+			}
+		}
+
+		var err error
+		entry, err = q.UpdateKanbanChecklistEntry(ctx, repository.UpdateKanbanChecklistEntryParams{
+			ID:            entryID,
+			Title:         utils.PtrToPgText(params.Title),
+			Done:          utils.PtrToPgBool(params.Done),
+			SetAssigneeID: params.AssigneeID.Set,
+			AssigneeID:    utils.PtrToPgText(params.AssigneeID.Value),
+			SetDueDate:    params.DueDate.Set,
+			DueDate:       utils.PtrToPgTimestamptz(params.DueDate.Value),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to update checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to update checklist entry", err)
+		}
+
+		entries, err = q.ListKanbanChecklistEntries(ctx, itemID)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to update checklist entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("checklist entry updated")
+	s.publishChecklist(ctx, kanbanID, itemID, entries)
+	return &entry, nil
+}
+
+// MoveChecklistEntry places an entry after or before another entry of the
+// same checklist, the item is locked so concurrent moves don't collide
+func (s *KanbanService) MoveChecklistEntry(ctx context.Context, projectID, kanbanID, itemID, entryID string, params dto.MoveKanbanChecklistEntryInput) (*repository.KanbanChecklistEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var (
+		entry   repository.KanbanChecklistEntry
+		entries []repository.KanbanChecklistEntry
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+		if err := q.LockKanbanItem(ctx, itemID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to move checklist entry", err)
+		}
+		if _, err := getChecklistEntry(ctx, q, itemID, entryID); err != nil {
+			return err
+		}
+
+		after, err := siblingChecklistRank(ctx, q, itemID, entryID, params.AfterID)
+		if err != nil {
+			return err
+		}
+		before, err := siblingChecklistRank(ctx, q, itemID, entryID, params.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		scope := checklistRankScope(ctx, q, itemID, entryID)
+		rank, err := placeRank(scope, after, before)
+		if err != nil {
+			return err
+		}
+		if err := q.SetKanbanChecklistRank(ctx, repository.SetKanbanChecklistRankParams{
+			ID:   entryID,
+			Rank: rank,
+		}); err != nil {
+			logger.WithError(err).Error("failed to move checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to move checklist entry", err)
+		}
+
+		if len(rank) > maxRankLength {
+			logger.Info("rebalancing checklist order")
+			if _, err := rebalance(scope); err != nil {
+				return err
+			}
+		}
+
+		entry, err = getChecklistEntry(ctx, q, itemID, entryID)
+		if err != nil {
+			return err
+		}
+		entries, err = q.ListKanbanChecklistEntries(ctx, itemID)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to move checklist entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("checklist entry moved")
+	s.publishChecklist(ctx, kanbanID, itemID, entries)
+	return &entry, nil
+}
+
+func (s *KanbanService) DeleteChecklistEntry(ctx context.Context, projectID, kanbanID, itemID, entryID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var entries []repository.KanbanChecklistEntry
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		rows, err := q.DeleteKanbanChecklistEntry(ctx, repository.DeleteKanbanChecklistEntryParams{
+			ID:     entryID,
+			ItemID: itemID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to delete checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete checklist entry", err)
+		}
+		if rows == 0 {
+			return utils.NewError(http.StatusNotFound, "checklist entry not found", nil)
+		}
+
+		entries, err = q.ListKanbanChecklistEntries(ctx, itemID)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to delete checklist entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("checklist entry deleted")
+	s.publishChecklist(ctx, kanbanID, itemID, entries)
+	return nil
+}
+
+// PromoteChecklistEntry turns a checklist entry into a card of its own. The
+// new card links back to the card it came from and the entry links to the
+// new card, an entry can only be promoted once.
+func (s *KanbanService) PromoteChecklistEntry(ctx context.Context, projectID, kanbanID, itemID, entryID string, params dto.PromoteKanbanChecklistEntryInput) (*dto.PromoteKanbanChecklistEntryResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	var (
+		item      repository.KanbanItem
+		entry     repository.KanbanChecklistEntry
+		entries   []repository.KanbanChecklistEntry
+		assignees []string
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		parent, err := getActiveItem(ctx, q, projectID, kanbanID, itemID)
+		if err != nil {
+			return err
+		}
+		if err := q.LockKanbanItem(ctx, itemID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+		entry, err = getChecklistEntry(ctx, q, itemID, entryID)
+		if err != nil {
+			return err
+		}
+		if entry.PromotedItemID.Valid {
+			return utils.NewError(http.StatusConflict, "checklist entry is already a card", nil)
+		}
+
+		categoryID := parent.KanbanCategoryID
+		if params.CategoryID != nil {
+			categoryID = *params.CategoryID
+		}
+		if _, err := getActiveCategory(ctx, q, projectID, kanbanID, categoryID); err != nil {
+			return err
+		}
+		if err := q.LockKanbanCategory(ctx, categoryID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+
+		id := gonanoid.Must()
+		rank, err := placeRank(itemRankScope(ctx, q, categoryID, id), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.CreateKanbanItem(ctx, repository.CreateKanbanItemParams{
+			ID:               id,
+			KanbanCategoryID: categoryID,
+			Title:            truncateTitle(entry.Title),
+			Priority:         string(dto.PriorityNone),
+			DueDate:          entry.DueDate,
+			Rank:             rank,
+		}); err != nil {
+			logger.WithError(err).Error("failed to create item from checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+		item, err = q.SetKanbanItemParent(ctx, repository.SetKanbanItemParentParams{
+			ID:           id,
+			ParentItemID: utils.PtrToPgText(&itemID),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to link item to its parent")
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+		if err := recordTransition(ctx, q, kanbanID, id, nil, item.KanbanCategoryID); err != nil {
+			return err
+		}
+		if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, id, dto.ActivityCreate, nil, item); err != nil {
+			return err
+		}
+
+		// Carry the assignee over while they are still on the project
+		if entry.AssigneeID.Valid {
+			if err := requireProjectMember(ctx, q, projectID, entry.AssigneeID.String); err == nil {
+				if _, err := q.AddKanbanItemAssignee(ctx, repository.AddKanbanItemAssigneeParams{
+					ItemID: id,
+					UserID: entry.AssigneeID.String,
+				}); err != nil {
+					logger.WithError(err).Error("failed to assign promoted item")
+					return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+				}
+				assignees = append(assignees, entry.AssigneeID.String)
+			}
+		}
+
+		entry, err = q.SetKanbanChecklistEntryPromoted(ctx, repository.SetKanbanChecklistEntryPromotedParams{
+			ID:             entryID,
+			PromotedItemID: utils.PtrToPgText(&id),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to link checklist entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+
+		entries, err = q.ListKanbanChecklistEntries(ctx, itemID)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to promote checklist entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("promoted_item_id", item.ID).Info("checklist entry promoted")
+	s.publish(ctx, utils.NewKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item:     item,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	if len(assignees) > 0 {
+		s.publishItemUsers(ctx, utils.KanbanItemAssignees, kanbanID, item.ID, assignees)
+	}
+	s.publishChecklist(ctx, kanbanID, itemID, entries)
+	s.runRules(ctx, ruleEvent{
+		Event:      dto.RuleItemCreated,
+		ProjectID:  projectID,
+		KanbanID:   kanbanID,
+		ItemID:     item.ID,
+		CategoryID: item.KanbanCategoryID,
+	})
+	return &dto.PromoteKanbanChecklistEntryResponse{
+		Item:  item,
+		Entry: entry,
+	}, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+func (s *KanbanService) publishChecklist(ctx context.Context, kanbanID, itemID string, entries []repository.KanbanChecklistEntry) {
+	checklist := newKanbanChecklist(entries)
+	s.publish(ctx, utils.KanbanItemChecklist, kanbanID, dto.KanbanChecklistEvent{
+		ItemID:   itemID,
+		Entries:  checklist.Entries,
+		Progress: checklist.Progress,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+}
+
+func getChecklistEntry(ctx context.Context, q repository.Querier, itemID, entryID string) (repository.KanbanChecklistEntry, error) {
+	entry, err := q.GetKanbanChecklistEntry(ctx, repository.GetKanbanChecklistEntryParams{
+		ID:     entryID,
+		ItemID: itemID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entry, utils.NewError(http.StatusNotFound, "checklist entry not found", err)
+		}
+		return entry, utils.NewError(http.StatusInternalServerError, "failed to fetch checklist entry", err)
+	}
+	return entry, nil
+}
+
+// siblingChecklistRank returns the rank of a neighbour entry given by id
+func siblingChecklistRank(ctx context.Context, q repository.Querier, itemID, entryID string, siblingID *string) (*string, error) {
+	if siblingID == nil {
+		return nil, nil
+	}
+	if *siblingID == entryID {
+		return nil, utils.NewError(http.StatusBadRequest, "an entry can't be placed next to itself", nil)
+	}
+
+	sibling, err := getChecklistEntry(ctx, q, itemID, *siblingID)
+	if err != nil {
+		return nil, err
+	}
+	return &sibling.Rank, nil
+}
+
+// newKanbanChecklist counts the done entries of a checklist
+func newKanbanChecklist(entries []repository.KanbanChecklistEntry) *dto.KanbanChecklistResponse {
+	if entries == nil {
+		entries = []repository.KanbanChecklistEntry{}
+	}
+
+	progress := dto.KanbanChecklistProgress{Total: int64(len(entries))}
+	for _, e := range entries {
+		if e.Done {
+			progress.Done++
+		}
+	}
+	return &dto.KanbanChecklistResponse{
+		Entries:  entries,
+		Progress: progress,
+	}
+}
+
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= maxItemTitleLength {
+		return title
+	}
+	return string(runes[:maxItemTitleLength])
+}
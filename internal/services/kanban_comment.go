@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Actions stored with previous comment bodies
+const (
+	commentRevisionEdit   = "edit"
+	commentRevisionDelete = "delete"
+)
+
+// -------------------------------------------------------------
+// Comments
+// -------------------------------------------------------------
+
+// Comments lists the comment thread of an item, replies reference their
+// parent and deleted comments are kept without a body
+func (s *KanbanService) Comments(ctx context.Context, projectID, kanbanID, itemID string) ([]dto.KanbanComment, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	rows, err := s.repos.Kanban.ListComments(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list comments")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list comments", err)
+	}
+
+	mentions, err := s.repos.Kanban.ListCommentMentions(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list mentions")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list comments", err)
+	}
+
+	byComment := make(map[string][]string)
+	for _, m := range mentions {
+		byComment[m.CommentID] = append(byComment[m.CommentID], m.UserID)
+	}
+
+	comments := make([]dto.KanbanComment, 0, len(rows))
+	for _, row := range rows {
+		commentMentions := byComment[row.ID]
+		if commentMentions == nil || row.DeletedAt.Valid {
+			commentMentions = []string{}
+		}
+		comments = append(comments, dto.KanbanComment{
+			ListKanbanItemCommentsRow: row,
+			Mentions:                  commentMentions,
+		})
+	}
+	return comments, nil
+}
+
+// CreateComment posts a comment on an item and notifies the organisation
+// members mentioned in it
+func (s *KanbanService) CreateComment(ctx context.Context, orgID, projectID, kanbanID, itemID, authorID string, params dto.CreateKanbanCommentInput) (*dto.KanbanCommentResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"author_id":  authorID,
+	})
+
+	var result dto.KanbanCommentResponse
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		if params.ParentID != nil {
+			parent, err := getComment(ctx, q, itemID, *params.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.DeletedAt.Valid {
+				return utils.NewError(http.StatusConflict, "can't reply to a deleted comment", nil)
+			}
+		}
+
+		comment, err := q.CreateKanbanItemComment(ctx, repository.CreateKanbanItemCommentParams{
+			ID:       gonanoid.Must(),
+			ItemID:   itemID,
+			ParentID: utils.PtrToPgText(params.ParentID),
+			AuthorID: utils.PtrToPgText(&authorID),
+			Body:     params.Body,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create comment")
+			return utils.NewError(http.StatusInternalServerError, "failed to create comment", err)
+		}
+
+		mentions, err := s.syncMentions(ctx, q, orgID, projectID, kanbanID, authorID, comment)
+		if err != nil {
+			logger.WithError(err).Error("failed to store mentions")
+			return err
+		}
+
+		result = dto.KanbanCommentResponse{
+			Comment:  comment,
+			Mentions: mentions,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("comment_id", result.Comment.ID).Info("comment created")
+	s.publishComment(ctx, utils.NewKanbanItemComment, kanbanID, result)
+	return &result, nil
+}
+
+// UpdateComment changes the body of a comment, only its author may edit it.
+// The previous body is kept in the comment history.
+func (s *KanbanService) UpdateComment(ctx context.Context, orgID, projectID, kanbanID, itemID, commentID, userID string, params dto.UpdateKanbanCommentInput) (*dto.KanbanCommentResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	var result dto.KanbanCommentResponse
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		current, err := getComment(ctx, q, itemID, commentID)
+		if err != nil {
+			return err
+		}
+		if current.DeletedAt.Valid {
+			return utils.NewError(http.StatusConflict, "comment is deleted", nil)
+		}
+		if current.AuthorID.String != userID {
+			return utils.NewError(http.StatusForbidden, "only the author can edit a comment", nil)
+		}
+
+		if err := q.CreateKanbanItemCommentRevision(ctx, repository.CreateKanbanItemCommentRevisionParams{
+			ID:        gonanoid.Must(),
+			CommentID: commentID,
+			EditorID:  utils.PtrToPgText(&userID),
+			Action:    commentRevisionEdit,
+			Body:      current.Body,
+		}); err != nil {
+			logger.WithError(err).Error("failed to store comment revision")
+			return utils.NewError(http.StatusInternalServerError, "failed to update comment", err)
+		}
+
+		comment, err := q.UpdateKanbanItemComment(ctx, repository.UpdateKanbanItemCommentParams{
+			ID:   commentID,
+			Body: params.Body,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to update comment")
+			return utils.NewError(http.StatusInternalServerError, "failed to update comment", err)
+		}
+
+		mentions, err := s.syncMentions(ctx, q, orgID, projectID, kanbanID, userID, comment)
+		if err != nil {
+			logger.WithError(err).Error("failed to store mentions")
+			return err
+		}
+
+		result = dto.KanbanCommentResponse{
+			Comment:  comment,
+			Mentions: mentions,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("comment updated")
+	s.publishComment(ctx, utils.EditKanbanItemComment, kanbanID, result)
+	return &result, nil
+}
+
+// DeleteComment soft deletes a comment so its replies keep their place in
+// the thread. Authors can delete their own comments, moderators any comment.
+func (s *KanbanService) DeleteComment(ctx context.Context, projectID, kanbanID, itemID, commentID, userID string, moderator bool) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	var comment repository.KanbanItemComment
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		current, err := getComment(ctx, q, itemID, commentID)
+		if err != nil {
+			return err
+		}
+		if current.DeletedAt.Valid {
+			return utils.NewError(http.StatusNotFound, "comment not found", nil)
+		}
+		if current.AuthorID.String != userID && !moderator {
+			return utils.NewError(http.StatusForbidden, "not allowed to delete this comment", nil)
+		}
+
+		if err := q.CreateKanbanItemCommentRevision(ctx, repository.CreateKanbanItemCommentRevisionParams{
+			ID:        gonanoid.Must(),
+			CommentID: commentID,
+			EditorID:  utils.PtrToPgText(&userID),
+			Action:    commentRevisionDelete,
+			Body:      current.Body,
+		}); err != nil {
+			logger.WithError(err).Error("failed to store comment revision")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete comment", err)
+		}
+
+		comment, err = q.SoftDeleteKanbanItemComment(ctx, commentID)
+		if err != nil {
+			logger.WithError(err).Error("failed to delete comment")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete comment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("comment deleted")
+	comment.Body = ""
+	s.publishComment(ctx, utils.DeleteKanbanItemComment, kanbanID, dto.KanbanCommentResponse{
+		Comment:  comment,
+		Mentions: []string{},
+	})
+	return nil
+}
+
+// CommentHistory lists the previous bodies of a comment, oldest first
+func (s *KanbanService) CommentHistory(ctx context.Context, projectID, kanbanID, itemID, commentID string) ([]repository.ListKanbanItemCommentRevisionsRow, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"comment_id": commentID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+	if _, err := s.repos.Kanban.GetComment(ctx, commentID, itemID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "comment not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch comment")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch comment", err)
+	}
+
+	revisions, err := s.repos.Kanban.ListCommentRevisions(ctx, commentID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list comment history")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list comment history", err)
+	}
+	return revisions, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// syncMentions resolves the @usernames in a comment to organisation members,
+// replaces the stored mentions and notifies users mentioned for the first time.
+// It returns the ids of every mentioned user.
+func (s *KanbanService) syncMentions(ctx context.Context, q repository.Querier, orgID, projectID, kanbanID, actorID string, comment repository.KanbanItemComment) ([]string, error) {
+	mentioned := []string{}
+	if names := utils.ParseMentions(comment.Body); len(names) > 0 {
+		users, err := q.ResolveOrganisationUsernames(ctx, repository.ResolveOrganisationUsernamesParams{
+			OrganisationID: orgID,
+			Usernames:      names,
+		})
+		if err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to resolve mentions", err)
+		}
+		for _, u := range users {
+			mentioned = append(mentioned, u.ID)
+		}
+	}
+
+	if err := q.RemoveKanbanItemCommentMentions(ctx, repository.RemoveKanbanItemCommentMentionsParams{
+		CommentID:   comment.ID,
+		KeepUserIds: mentioned,
+	}); err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store mentions", err)
+	}
+	if len(mentioned) == 0 {
+		return mentioned, nil
+	}
+
+	added, err := q.AddKanbanItemCommentMentions(ctx, repository.AddKanbanItemCommentMentionsParams{
+		CommentID: comment.ID,
+		UserIds:   mentioned,
+	})
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to store mentions", err)
+	}
+
+	if err := notify(ctx, q, orgID, actorID, dto.NotificationCommentMention, dto.KanbanCommentNotification{
+		ProjectID: projectID,
+		KanbanID:  kanbanID,
+		ItemID:    comment.ItemID,
+		CommentID: comment.ID,
+	}, added); err != nil {
+		return nil, err
+	}
+	return mentioned, nil
+}
+
+func (s *KanbanService) publishComment(ctx context.Context, typ utils.MessageType, kanbanID string, comment dto.KanbanCommentResponse) {
+	s.publish(ctx, typ, kanbanID, dto.KanbanCommentEvent{
+		Comment:  comment.Comment,
+		Mentions: comment.Mentions,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+}
+
+// getComment fetches a comment of an item, deleted or not
+func getComment(ctx context.Context, q repository.Querier, itemID, commentID string) (repository.KanbanItemComment, error) {
+	comment, err := q.GetKanbanItemComment(ctx, repository.GetKanbanItemCommentParams{
+		ID:     commentID,
+		ItemID: itemID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return comment, utils.NewError(http.StatusNotFound, "comment not found", err)
+		}
+		return comment, utils.NewError(http.StatusInternalServerError, "failed to fetch comment", err)
+	}
+	return comment, nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// Cards read per query while exporting a category
+const exportItemBatch = 200
+
+// KanbanExport is a board ready to be streamed in one format. It is prepared
+// by Export so a missing board fails before anything is written.
+type KanbanExport struct {
+	Kanban          repository.Kanban
+	Format          dto.KanbanExportFormat
+	IncludeArchived bool
+	repos           *KanbanServiceRepos
+}
+
+// Export prepares the export of a kanban, archived categories and cards are
+// only included when asked for
+func (s *KanbanService) Export(ctx context.Context, projectID, kanbanID string, format dto.KanbanExportFormat, includeArchived bool) (*KanbanExport, error) {
+	kanban, err := s.getKanban(ctx, projectID, kanbanID)
+	if err != nil {
+		return nil, err
+	}
+	return &KanbanExport{
+		Kanban:          *kanban,
+		Format:          format,
+		IncludeArchived: includeArchived,
+		repos:           s.repos,
+	}, nil
+}
+
+// ContentType is the media type of the export
+func (e *KanbanExport) ContentType() string {
+	switch e.Format {
+	case dto.ExportCSV:
+		return "text/csv; charset=utf-8"
+	case dto.ExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// FileName is the name the export is downloaded as
+func (e *KanbanExport) FileName() string {
+	ext := string(e.Format)
+	if e.Format == dto.ExportMarkdown {
+		ext = "md"
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '-'
+		}
+		return -1
+	}, e.Kanban.Name)
+	if name == "" {
+		name = e.Kanban.ID
+	}
+	return name + "." + ext
+}
+
+// Write streams the board to w. Categories are written in board order and
+// their cards are read in batches, so only one batch is held in memory. The
+// output is flushed after every batch when w supports it.
+func (e *KanbanExport) Write(ctx context.Context, w io.Writer) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("kanban_id", e.Kanban.ID)
+
+	var out kanbanExportWriter
+	switch e.Format {
+	case dto.ExportCSV:
+		out = newCSVExportWriter(w)
+	case dto.ExportMarkdown:
+		out = newMarkdownExportWriter(w)
+	default:
+		out = newJSONExportWriter(w)
+	}
+
+	labels, err := e.repos.Kanban.ListBoardLabels(ctx, e.Kanban.ID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list labels")
+		return utils.NewError(http.StatusInternalServerError, "failed to export kanban", err)
+	}
+	if err := out.begin(e.Kanban, labels); err != nil {
+		return err
+	}
+
+	categories, err := e.repos.Kanban.ListExportCategories(ctx, e.Kanban.ID, e.IncludeArchived)
+	if err != nil {
+		logger.WithError(err).Error("failed to list categories")
+		return utils.NewError(http.StatusInternalServerError, "failed to export kanban", err)
+	}
+	for _, category := range categories {
+		if err := out.category(dto.KanbanExportCategory{
+			Name:     category.Name.String,
+			Done:     category.Done,
+			WipLimit: utils.PgInt4ToPtr(category.WipLimit),
+			Archived: category.DeletedAt.Valid,
+		}); err != nil {
+			return err
+		}
+		if err := e.writeItems(ctx, out, category.ID); err != nil {
+			return err
+		}
+		if err := out.endCategory(); err != nil {
+			return err
+		}
+	}
+	return out.end()
+}
+
+// writeItems writes the cards of a category one batch at a time
+func (e *KanbanExport) writeItems(ctx context.Context, out kanbanExportWriter, categoryID string) error {
+	params := repository.ListKanbanExportItemsParams{
+		KanbanCategoryID: categoryID,
+		IncludeArchived:  e.IncludeArchived,
+		Limit:            exportItemBatch,
+	}
+	for {
+		items, err := e.repos.Kanban.ListExportItems(ctx, params)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to export kanban", err)
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		labels, err := e.repos.Kanban.ListExportItemLabels(ctx, ids)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to export kanban", err)
+		}
+		assignees, err := e.repos.Kanban.ListExportItemAssignees(ctx, ids)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to export kanban", err)
+		}
+		itemLabels := map[string][]string{}
+		for _, l := range labels {
+			itemLabels[l.ItemID] = append(itemLabels[l.ItemID], l.Name)
+		}
+		itemAssignees := map[string][]dto.KanbanExportUser{}
+		for _, a := range assignees {
+			itemAssignees[a.ItemID] = append(itemAssignees[a.ItemID], dto.KanbanExportUser{
+				Username: a.Username,
+				Email:    a.Email,
+			})
+		}
+
+		for _, item := range items {
+			exported := dto.KanbanExportItem{
+				Title:         item.Title,
+				Description:   utils.PgTextToPtr(item.Description),
+				Priority:      dto.KanbanPriority(item.Priority),
+				DueDate:       utils.PgTimestamptzToPtr(item.DueDate),
+				EstimatedTime: utils.PgInt4ToPtr(item.EstimatedTime),
+				CompletedAt:   utils.PgTimestamptzToPtr(item.CompletedAt),
+				Archived:      item.DeletedAt.Valid,
+				Labels:        itemLabels[item.ID],
+				Assignees:     itemAssignees[item.ID],
+			}
+			if exported.Labels == nil {
+				exported.Labels = []string{}
+			}
+			if exported.Assignees == nil {
+				exported.Assignees = []dto.KanbanExportUser{}
+			}
+			if err := out.item(exported); err != nil {
+				return err
+			}
+		}
+		if err := out.flush(); err != nil {
+			return err
+		}
+
+		if len(items) < exportItemBatch {
+			return nil
+		}
+		last := items[len(items)-1]
+		params.AfterRank = last.Rank
+		params.AfterID = last.ID
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// kanbanExportWriter writes an export as the board is read, each category is
+// followed by its cards
+type kanbanExportWriter interface {
+	begin(kanban repository.Kanban, labels []repository.KanbanLabel) error
+	category(category dto.KanbanExportCategory) error
+	item(item dto.KanbanExportItem) error
+	endCategory() error
+	// flush hands what was written so far to the client
+	flush() error
+	end() error
+}
+
+// flushWriter flushes a buffer and the response under it
+func flushWriter(buf *bufio.Writer, w io.Writer) error {
+	if err := buf.Flush(); err != nil {
+		return exportWriteError(err)
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func exportWriteError(err error) error {
+	return utils.NewError(http.StatusInternalServerError, "failed to write export", err)
+}
+
+// -------------------------------------------------------------
+// JSON
+// -------------------------------------------------------------
+
+// jsonExportWriter writes a dto.KanbanExport. The board and every category
+// are marshalled with an empty list that is opened in place, the entries of
+// the list follow as they are read.
+type jsonExportWriter struct {
+	w          io.Writer
+	buf        *bufio.Writer
+	categories int
+	items      int
+}
+
+func newJSONExportWriter(w io.Writer) *jsonExportWriter {
+	return &jsonExportWriter{w: w, buf: bufio.NewWriter(w)}
+}
+
+func (j *jsonExportWriter) begin(kanban repository.Kanban, labels []repository.KanbanLabel) error {
+	export := dto.KanbanExport{
+		Format:     dto.KanbanExportName,
+		Version:    dto.KanbanExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Kanban: dto.KanbanExportKanban{
+			Name:   kanban.Name,
+			Status: dto.KanbanStatus(kanban.Status),
+		},
+		Labels: make([]dto.KanbanExportLabel, 0, len(labels)),
+	}
+	for _, l := range labels {
+		export.Labels = append(export.Labels, dto.KanbanExportLabel{Name: l.Name, Color: l.Color})
+	}
+	return j.open(export)
+}
+
+func (j *jsonExportWriter) category(category dto.KanbanExportCategory) error {
+	if j.categories > 0 {
+		j.buf.WriteByte(',')
+	}
+	j.categories++
+	j.items = 0
+	return j.open(category)
+}
+
+func (j *jsonExportWriter) item(item dto.KanbanExportItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return exportWriteError(err)
+	}
+	if j.items > 0 {
+		j.buf.WriteByte(',')
+	}
+	j.items++
+	_, err = j.buf.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) endCategory() error {
+	_, err := j.buf.WriteString("]}")
+	return err
+}
+
+func (j *jsonExportWriter) flush() error {
+	return flushWriter(j.buf, j.w)
+}
+
+func (j *jsonExportWriter) end() error {
+	j.buf.WriteString("]}\n")
+	return j.flush()
+}
+
+// open writes v without its closing brace and with its last field, a nil
+// list, left open
+func (j *jsonExportWriter) open(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return exportWriteError(err)
+	}
+	data, ok := bytes.CutSuffix(data, []byte("null}"))
+	if !ok {
+		return exportWriteError(fmt.Errorf("unexpected export layout: %s", data))
+	}
+	j.buf.Write(data)
+	_, err = j.buf.WriteString("[")
+	return err
+}
+
+// -------------------------------------------------------------
+// CSV
+// -------------------------------------------------------------
+
+// csvExportWriter writes one row per card, the first columns are the ones the
+// CSV importer reads
+type csvExportWriter struct {
+	w       io.Writer
+	csv     *csv.Writer
+	current dto.KanbanExportCategory
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+// csvExportHeader is the header row of a CSV export, imports recognise
+// exported files by it
+var csvExportHeader = []string{
+	"column", "title", "description", "due", "labels", "assignee email",
+	"priority", "estimated time", "completed", "archived",
+}
+
+func (c *csvExportWriter) begin(repository.Kanban, []repository.KanbanLabel) error {
+	return c.csv.Write(csvExportHeader)
+}
+
+func (c *csvExportWriter) category(category dto.KanbanExportCategory) error {
+	c.current = category
+	return nil
+}
+
+func (c *csvExportWriter) item(item dto.KanbanExportItem) error {
+	emails := make([]string, len(item.Assignees))
+	for i, a := range item.Assignees {
+		emails[i] = a.Email
+	}
+	estimate := ""
+	if item.EstimatedTime != nil {
+		estimate = strconv.Itoa(int(*item.EstimatedTime))
+	}
+	description := ""
+	if item.Description != nil {
+		description = *item.Description
+	}
+	return c.csv.Write([]string{
+		utils.CSVCell(c.current.Name),
+		utils.CSVCell(item.Title),
+		utils.CSVCell(description),
+		exportTime(item.DueDate),
+		utils.CSVCell(strings.Join(item.Labels, "; ")),
+		utils.CSVCell(strings.Join(emails, "; ")),
+		string(item.Priority),
+		estimate,
+		exportTime(item.CompletedAt),
+		strconv.FormatBool(item.Archived || c.current.Archived),
+	})
+}
+
+func (c *csvExportWriter) endCategory() error {
+	return nil
+}
+
+func (c *csvExportWriter) flush() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return exportWriteError(err)
+	}
+	if f, ok := c.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (c *csvExportWriter) end() error {
+	return c.flush()
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// -------------------------------------------------------------
+// Markdown
+// -------------------------------------------------------------
+
+// markdownExportWriter writes a status report, a section per category with a
+// task list of its cards
+type markdownExportWriter struct {
+	w          io.Writer
+	buf        *bufio.Writer
+	categories int
+	items      int
+	total      int
+}
+
+func newMarkdownExportWriter(w io.Writer) *markdownExportWriter {
+	return &markdownExportWriter{w: w, buf: bufio.NewWriter(w)}
+}
+
+func (m *markdownExportWriter) begin(kanban repository.Kanban, _ []repository.KanbanLabel) error {
+	_, err := fmt.Fprintf(m.buf, "# %s\n\nStatus: %s · Exported %s\n",
+		markdownText(kanban.Name), kanban.Status, time.Now().UTC().Format(time.DateOnly))
+	return err
+}
+
+func (m *markdownExportWriter) category(category dto.KanbanExportCategory) error {
+	m.categories++
+	m.items = 0
+	var notes []string
+	if category.Done {
+		notes = append(notes, "done")
+	}
+	if category.WipLimit != nil {
+		notes = append(notes, fmt.Sprintf("limit %d", *category.WipLimit))
+	}
+	if category.Archived {
+		notes = append(notes, "archived")
+	}
+	heading := "\n## " + markdownText(category.Name)
+	if len(notes) > 0 {
+		heading += " (" + strings.Join(notes, ", ") + ")"
+	}
+	_, err := m.buf.WriteString(heading + "\n\n")
+	return err
+}
+
+func (m *markdownExportWriter) item(item dto.KanbanExportItem) error {
+	m.items++
+	m.total++
+
+	box := "[ ]"
+	if item.CompletedAt != nil {
+		box = "[x]"
+	}
+	var details []string
+	if item.Priority != "" && item.Priority != dto.PriorityNone {
+		details = append(details, string(item.Priority))
+	}
+	if item.DueDate != nil {
+		details = append(details, "due "+item.DueDate.UTC().Format(time.DateOnly))
+	}
+	for _, a := range item.Assignees {
+		details = append(details, "@"+markdownText(a.Username))
+	}
+	for _, l := range item.Labels {
+		details = append(details, "`"+strings.ReplaceAll(l, "`", "")+"`")
+	}
+	if item.Archived {
+		details = append(details, "archived")
+	}
+
+	line := "- " + box + " " + markdownText(item.Title)
+	if len(details) > 0 {
+		line += " — " + strings.Join(details, " · ")
+	}
+	_, err := m.buf.WriteString(line + "\n")
+	return err
+}
+
+func (m *markdownExportWriter) endCategory() error {
+	if m.items == 0 {
+		_, err := m.buf.WriteString("_No cards_\n")
+		return err
+	}
+	return nil
+}
+
+func (m *markdownExportWriter) flush() error {
+	return flushWriter(m.buf, m.w)
+}
+
+func (m *markdownExportWriter) end() error {
+	fmt.Fprintf(m.buf, "\n---\n\n%d cards in %d categories\n", m.total, m.categories)
+	return m.flush()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`, "\r", "", "\n", " ",
+)
+
+// markdownText escapes user text so it renders as written on one line
+func markdownText(value string) string {
+	return markdownEscaper.Replace(value)
+}
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	// Most cards or CSV rows a single import may hold
+	maxImportRows = 2000
+	// Longest kanban, category and label name
+	maxImportNameLength = 50
+)
+
+// errImportDryRun rolls back the transaction of a dry run once the board
+// has been built
+var errImportDryRun = errors.New("kanban import dry run")
+
+// Import creates a kanban from a Trello board export, a CSV file or a JSON
+// export of this API in one transaction. Rows that can't be imported are
+// skipped and reported, the rest of the file still goes in. A dry run builds the board the same way
+// and rolls it back, so the result previews exactly what an import would do.
+// Either way the issues are kept for the per-row report.
+func (s *KanbanService) Import(ctx context.Context, orgID, projectID string, params dto.ImportKanbanInput, fileName string, data []byte) (*dto.KanbanImportResult, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+
+	source := importFormat(params.Format, fileName, data)
+	logger.Infof("importing kanban from %s file: %s", source, fileName)
+
+	var board *importBoard
+	var err error
+	switch source {
+	case dto.ImportTrello:
+		board, err = parseTrello(data, maxImportRows)
+	case dto.ImportJSON:
+		board, err = parseExport(data, maxImportRows)
+	default:
+		board, err = parseCSV(data, maxImportRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = strings.TrimSpace(board.Name)
+	}
+	if name == "" {
+		name = strings.TrimSpace(strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName)))
+	}
+	if name == "" || name == "." {
+		name = "Imported board"
+	}
+	name = truncateName(name)
+
+	unknownUsers := params.UnknownUsers
+	if unknownUsers == "" {
+		unknownUsers = dto.ImportUnassignedUsers
+	}
+
+	result := &dto.KanbanImportResult{
+		ID:          gonanoid.Must(),
+		Source:      source,
+		DryRun:      params.DryRun,
+		Name:        name,
+		Columns:     []dto.KanbanImportColumn{},
+		Labels:      []string{},
+		Invitations: []string{},
+		Rows:        board.Rows,
+	}
+
+	var kanban repository.Kanban
+	err = s.tx.WithTx(ctx, func(q repository.Querier) error {
+		imp := &kanbanImport{
+			q:            q,
+			orgID:        orgID,
+			projectID:    projectID,
+			unknownUsers: unknownUsers,
+			board:        board,
+			result:       result,
+			users:        map[importUser]*importAssignee{},
+		}
+		kanban, err = imp.run(ctx, name)
+		if err != nil {
+			return err
+		}
+		if params.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+
+	result.Skipped = result.Rows - result.Imported
+	result.Issues = board.Issues
+	if result.Issues == nil {
+		result.Issues = []dto.KanbanImportIssue{}
+	}
+	if !params.DryRun {
+		result.Kanban = &kanban
+	}
+
+	issues, err := json.Marshal(result.Issues)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to save import", err)
+	}
+	var kanbanID *string
+	if result.Kanban != nil {
+		kanbanID = &kanban.ID
+	}
+	if _, err := s.repos.Kanban.CreateImport(ctx, repository.CreateKanbanImportParams{
+		ID:            result.ID,
+		ProjectID:     projectID,
+		KanbanID:      utils.PtrToPgText(kanbanID),
+		Source:        string(source),
+		FileName:      truncateFileName(fileName),
+		DryRun:        params.DryRun,
+		RowCount:      int32(result.Rows),
+		ImportedCount: int32(result.Imported),
+		Issues:        issues,
+		CreatedBy:     utils.PtrToPgText(userIDFromContext(ctx)),
+	}); err != nil {
+		// The board is committed at this point, only the report is lost
+		logger.WithError(err).Error("failed to save import")
+	}
+
+	logger.WithField("import_id", result.ID).Infof("imported %d of %d rows (dry run: %t)", result.Imported, result.Rows, params.DryRun)
+	return result, nil
+}
+
+// ImportReport returns an import of the project with its row issues
+func (s *KanbanService) ImportReport(ctx context.Context, projectID, importID string) (*repository.KanbanImport, []dto.KanbanImportIssue, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+
+	imp, err := s.repos.Kanban.GetImport(ctx, importID, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, utils.NewError(http.StatusNotFound, "import not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch import")
+		return nil, nil, utils.NewError(http.StatusInternalServerError, "failed to fetch import", err)
+	}
+
+	var issues []dto.KanbanImportIssue
+	if err := json.Unmarshal(imp.Issues, &issues); err != nil {
+		return nil, nil, utils.NewError(http.StatusInternalServerError, "failed to read import", err)
+	}
+	return &imp, issues, nil
+}
+
+// kanbanImport builds a parsed board inside an import transaction
+type kanbanImport struct {
+	q            repository.Querier
+	orgID        string
+	projectID    string
+	unknownUsers dto.KanbanImportUsers
+	board        *importBoard
+	result       *dto.KanbanImportResult
+	// Labels usable on the new kanban by lower case name
+	labels map[string]string
+	// Assignees already looked up, by how the file names them
+	users map[importUser]*importAssignee
+}
+
+// importAssignee is what an assignee of a file resolved to, a member to
+// assign or an email to invite. Neither is set for users that are skipped.
+type importAssignee struct {
+	UserID string
+	Email  string
+}
+
+func (imp *kanbanImport) run(ctx context.Context, name string) (repository.Kanban, error) {
+	q := imp.q
+
+	status := imp.board.Status
+	if status == "" {
+		status = dto.KanbanPlanning
+	}
+	kanban, err := q.CreateKanban(ctx, repository.CreateKanbanParams{
+		ID:        gonanoid.Must(),
+		ProjectID: imp.projectID,
+		Name:      name,
+		Status:    string(status),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return kanban, utils.NewError(http.StatusConflict, "a kanban with that name already exists", err)
+		}
+		return kanban, utils.NewError(http.StatusInternalServerError, "failed to create kanban", err)
+	}
+	if err := recordActivity(ctx, q, kanban.ID, dto.KanbanEntityKanban, kanban.ID, dto.ActivityCreate, nil, kanban); err != nil {
+		return kanban, err
+	}
+
+	labels, err := q.ListBoardLabels(ctx, kanban.ID)
+	if err != nil {
+		return kanban, utils.NewError(http.StatusInternalServerError, "failed to list labels", err)
+	}
+	imp.labels = make(map[string]string, len(labels))
+	for _, l := range labels {
+		imp.labels[strings.ToLower(l.Name)] = l.ID
+	}
+	// Exports bring the labels of the board, used or not
+	for _, label := range imp.board.Labels {
+		if _, err := imp.label(ctx, kanban.ID, label); err != nil {
+			return kanban, err
+		}
+	}
+
+	columns := imp.board.Columns
+	if len(columns) == 0 {
+		columns = []*importColumn{{Name: dto.DefaultKanbanCategory}}
+	}
+	ranks := utils.RankSequence(len(columns))
+	for i, column := range columns {
+		columnName := truncateName(strings.TrimSpace(column.Name))
+		if columnName == "" {
+			columnName = dto.DefaultKanbanCategory
+		}
+		category, err := q.CreateKanbanCategory(ctx, repository.CreateKanbanCategoryParams{
+			ID:       gonanoid.Must(),
+			KanbanID: kanban.ID,
+			Name:     utils.PtrToPgText(&columnName),
+			Rank:     ranks[i],
+			Done:     column.Done,
+			WipLimit: utils.PtrToPgInt4(column.WipLimit),
+		})
+		if err != nil {
+			return kanban, utils.NewError(http.StatusInternalServerError, "failed to create category", err)
+		}
+		if err := recordActivity(ctx, q, kanban.ID, dto.KanbanEntityCategory, category.ID, dto.ActivityCreate, nil, category); err != nil {
+			return kanban, err
+		}
+
+		imported := 0
+		for _, card := range column.Cards {
+			ok, err := imp.card(ctx, kanban.ID, category.ID, column.Name, card)
+			if err != nil {
+				return kanban, err
+			}
+			if ok {
+				imported++
+			}
+		}
+		imp.result.Imported += imported
+
+		// Archived categories are filled first, their cards go with them
+		if column.Archived {
+			archived, err := q.SoftDeleteKanbanCategory(ctx, repository.SoftDeleteKanbanCategoryParams{
+				ID:       category.ID,
+				KanbanID: kanban.ID,
+			})
+			if err != nil {
+				return kanban, utils.NewError(http.StatusInternalServerError, "failed to archive category", err)
+			}
+			if err := recordActivity(ctx, q, kanban.ID, dto.KanbanEntityCategory, category.ID, dto.ActivityDelete, category, archived); err != nil {
+				return kanban, err
+			}
+		}
+		imp.result.Columns = append(imp.result.Columns, dto.KanbanImportColumn{
+			Name:  columnName,
+			Items: imported,
+		})
+	}
+	return kanban, nil
+}
+
+// card imports one card, rows that can't be imported are reported and
+// skipped without failing the import
+func (imp *kanbanImport) card(ctx context.Context, kanbanID, categoryID, column string, card importCard) (bool, error) {
+	q := imp.q
+
+	title := strings.TrimSpace(card.Title)
+	if title == "" {
+		imp.board.issue(dto.ImportIssueError, card.Row, column, card.Title, "title is empty")
+		return false, nil
+	}
+	if truncated := truncateTitle(title); truncated != title {
+		imp.board.issue(dto.ImportIssueWarning, card.Row, column, card.Title, fmt.Sprintf("title is longer than %d characters and was shortened", maxItemTitleLength))
+		title = truncated
+	}
+
+	var description *string
+	if d := strings.TrimSpace(card.Description); d != "" {
+		description = &d
+	}
+
+	priority := card.Priority
+	if priority == "" {
+		priority = dto.PriorityNone
+	}
+
+	item, err := createItem(ctx, q, imp.projectID, kanbanID, categoryID, repository.CreateKanbanItemParams{
+		Title:         title,
+		Description:   utils.PtrToPgText(description),
+		Priority:      string(priority),
+		DueDate:       utils.PtrToPgTimestamptz(card.DueDate),
+		EstimatedTime: utils.PtrToPgInt4(card.EstimatedTime),
+	})
+	if err != nil {
+		return false, err
+	}
+	if card.Archived {
+		archived, err := q.SoftDeleteKanbanItem(ctx, item.ID)
+		if err != nil {
+			return false, utils.NewError(http.StatusInternalServerError, "failed to archive item", err)
+		}
+		if err := recordActivity(ctx, q, kanbanID, dto.KanbanEntityItem, item.ID, dto.ActivityDelete, item, archived); err != nil {
+			return false, err
+		}
+	}
+
+	for _, label := range card.Labels {
+		labelID, err := imp.label(ctx, kanbanID, label)
+		if err != nil {
+			return false, err
+		}
+		if _, err := q.AddKanbanItemLabel(ctx, repository.AddKanbanItemLabelParams{
+			ItemID:  item.ID,
+			LabelID: labelID,
+		}); err != nil {
+			return false, utils.NewError(http.StatusInternalServerError, "failed to add label", err)
+		}
+	}
+
+	for _, user := range card.Assignees {
+		assignee, err := imp.assignee(ctx, user)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case assignee.UserID != "":
+			if _, err := q.AddKanbanItemAssignee(ctx, repository.AddKanbanItemAssigneeParams{
+				ItemID: item.ID,
+				UserID: assignee.UserID,
+			}); err != nil {
+				return false, utils.NewError(http.StatusInternalServerError, "failed to add assignee", err)
+			}
+		case assignee.Email != "":
+			if _, err := q.CreateKanbanItemInvitation(ctx, repository.CreateKanbanItemInvitationParams{
+				ItemID:    item.ID,
+				Email:     assignee.Email,
+				ProjectID: imp.projectID,
+				InvitedBy: utils.PtrToPgText(userIDFromContext(ctx)),
+			}); err != nil {
+				return false, utils.NewError(http.StatusInternalServerError, "failed to invite assignee", err)
+			}
+			imp.board.issue(dto.ImportIssueWarning, card.Row, column, card.Title, fmt.Sprintf("%s is not a member of the project and will be assigned after joining", user))
+		case imp.unknownUsers == dto.ImportInviteUsers:
+			imp.board.issue(dto.ImportIssueWarning, card.Row, column, card.Title, fmt.Sprintf("%s has no known email to invite and was left unassigned", user))
+		default:
+			imp.board.issue(dto.ImportIssueWarning, card.Row, column, card.Title, fmt.Sprintf("%s is not a member of the project and was left unassigned", user))
+		}
+	}
+	return true, nil
+}
+
+// label returns the label with the name of an imported one, board and
+// organisation labels are reused and missing ones are added to the kanban
+func (imp *kanbanImport) label(ctx context.Context, kanbanID string, label importLabel) (string, error) {
+	name := truncateName(strings.TrimSpace(label.Name))
+	if id, ok := imp.labels[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+
+	created, err := imp.q.CreateKanbanLabel(ctx, repository.CreateKanbanLabelParams{
+		ID:             gonanoid.Must(),
+		OrganisationID: imp.orgID,
+		KanbanID:       utils.PtrToPgText(&kanbanID),
+		Name:           name,
+		Color:          label.Color,
+	})
+	if err != nil {
+		return "", utils.NewError(http.StatusInternalServerError, "failed to create label", err)
+	}
+	imp.labels[strings.ToLower(name)] = created.ID
+	imp.result.Labels = append(imp.result.Labels, created.Name)
+	return created.ID, nil
+}
+
+// assignee resolves a user named by the file, once per import. Users that
+// are not project members are invited by email when the import asks for it.
+func (imp *kanbanImport) assignee(ctx context.Context, user importUser) (*importAssignee, error) {
+	if a, ok := imp.users[user]; ok {
+		return a, nil
+	}
+
+	a := &importAssignee{}
+	imp.users[user] = a
+
+	var found repository.User
+	var err error
+	if user.Email != "" {
+		found, err = imp.q.GetByEmail(ctx, user.Email)
+	} else {
+		found, err = imp.q.GetByUsername(ctx, user.Username)
+	}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch user", err)
+	default:
+		_, err := imp.q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+			ProjectID: imp.projectID,
+			UserID:    found.ID,
+		})
+		if err == nil {
+			a.UserID = found.ID
+			return a, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to check membership", err)
+		}
+	}
+
+	if imp.unknownUsers != dto.ImportInviteUsers {
+		return a, nil
+	}
+	switch {
+	case user.Email != "":
+		a.Email = user.Email
+	case found.Email != "":
+		a.Email = strings.ToLower(found.Email)
+	default:
+		return a, nil
+	}
+	imp.result.Invitations = append(imp.result.Invitations, a.Email)
+	return a, nil
+}
+
+func truncateName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= maxImportNameLength {
+		return name
+	}
+	return string(runes[:maxImportNameLength])
+}
+
+func truncateFileName(name string) string {
+	runes := []rune(filepath.Base(name))
+	if len(runes) <= 255 {
+		return string(runes)
+	}
+	return string(runes[:255])
+}
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// Label color used when a file names a label without one
+const defaultImportLabelColor = "#6b778c"
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Hex values of the label colors Trello exports by name, dark and light
+// variants use the base color
+var trelloLabelColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+// importBoard is a parsed file, columns keep the order of the file. Only
+// JSON exports carry a status and labels of their own.
+type importBoard struct {
+	Name    string
+	Status  dto.KanbanStatus
+	Labels  []importLabel
+	Columns []*importColumn
+	Rows    int
+	Issues  []dto.KanbanImportIssue
+}
+
+type importColumn struct {
+	Name     string
+	Done     bool
+	WipLimit *int32
+	Archived bool
+	Cards    []importCard
+}
+
+type importCard struct {
+	Row           int
+	Title         string
+	Description   string
+	Priority      dto.KanbanPriority
+	DueDate       *time.Time
+	EstimatedTime *int32
+	Archived      bool
+	Labels        []importLabel
+	Assignees     []importUser
+}
+
+type importLabel struct {
+	Name  string
+	Color string
+}
+
+// importUser is an assignee as the file names it, CSV files give emails and
+// Trello exports usernames
+type importUser struct {
+	Email    string
+	Username string
+}
+
+func (u importUser) String() string {
+	if u.Email != "" {
+		return u.Email
+	}
+	return u.Username
+}
+
+// column returns the column with the given name, adding it when it's new
+func (b *importBoard) column(name string) *importColumn {
+	for _, c := range b.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	c := &importColumn{Name: name}
+	b.Columns = append(b.Columns, c)
+	return c
+}
+
+func (b *importBoard) issue(level dto.KanbanImportIssueLevel, row int, column, title, message string) {
+	b.Issues = append(b.Issues, dto.KanbanImportIssue{
+		Row:     row,
+		Column:  column,
+		Title:   title,
+		Level:   level,
+		Message: message,
+	})
+}
+
+// importFormat picks the format of an uploaded file, from the request, the
+// file extension or its first character. JSON files are exports of this API
+// when they say so and Trello boards otherwise.
+func importFormat(format dto.KanbanImportSource, fileName string, data []byte) dto.KanbanImportSource {
+	if format != "" {
+		return format
+	}
+	isJSON := false
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		isJSON = true
+	case ".csv":
+		return dto.ImportCSV
+	default:
+		isJSON = bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	}
+	if !isJSON {
+		return dto.ImportCSV
+	}
+
+	var header struct {
+		Format string `json:"format"`
+	}
+	if json.Unmarshal(data, &header) == nil && header.Format == dto.KanbanExportName {
+		return dto.ImportJSON
+	}
+	return dto.ImportTrello
+}
+
+// -------------------------------------------------------------
+// JSON export
+// -------------------------------------------------------------
+
+var (
+	importStatuses   = []dto.KanbanStatus{dto.KanbanPlanning, dto.KanbanInProgress, dto.KanbanDone, dto.KanbanArchived}
+	importPriorities = []dto.KanbanPriority{dto.PriorityExtreme, dto.PriorityHigh, dto.PriorityMedium, dto.PriorityLow, dto.PriorityNone}
+)
+
+// parseExport reads a board exported by this API as JSON, rows are the
+// positions of the cards in the file
+func parseExport(data []byte, maxRows int) (*importBoard, error) {
+	var export dto.KanbanExport
+	if err := json.Unmarshal(data, &export); err != nil || export.Format != dto.KanbanExportName {
+		return nil, utils.NewFieldError("file", "is not a kanban export")
+	}
+	if export.Version > dto.KanbanExportVersion {
+		return nil, utils.NewFieldError("file", fmt.Sprintf("is a version %d export, the newest supported is %d", export.Version, dto.KanbanExportVersion))
+	}
+
+	board := &importBoard{Name: export.Kanban.Name}
+	if slices.Contains(importStatuses, export.Kanban.Status) {
+		board.Status = export.Kanban.Status
+	}
+	colors := make(map[string]string, len(export.Labels))
+	for _, l := range export.Labels {
+		if l.Name == "" {
+			continue
+		}
+		color := l.Color
+		if !hexColor.MatchString(color) {
+			color = defaultImportLabelColor
+		}
+		colors[strings.ToLower(l.Name)] = color
+		board.Labels = append(board.Labels, importLabel{Name: l.Name, Color: color})
+	}
+
+	for _, category := range export.Categories {
+		board.Rows += len(category.Items)
+	}
+	if board.Rows > maxRows {
+		return nil, utils.NewFieldError("file", fmt.Sprintf("has more than %d cards", maxRows))
+	}
+
+	row := 0
+	for _, category := range export.Categories {
+		column := &importColumn{
+			Name:     category.Name,
+			Done:     category.Done,
+			WipLimit: category.WipLimit,
+			Archived: category.Archived,
+		}
+		if column.WipLimit != nil && *column.WipLimit < 1 {
+			board.issue(dto.ImportIssueWarning, row+1, category.Name, "", "WIP limit is not positive and was dropped")
+			column.WipLimit = nil
+		}
+		board.Columns = append(board.Columns, column)
+
+		for _, item := range category.Items {
+			row++
+			c := importCard{
+				Row:           row,
+				Title:         item.Title,
+				Priority:      item.Priority,
+				DueDate:       item.DueDate,
+				EstimatedTime: item.EstimatedTime,
+				Archived:      item.Archived,
+			}
+			if item.Description != nil {
+				c.Description = *item.Description
+			}
+			if !slices.Contains(importPriorities, c.Priority) {
+				if c.Priority != "" {
+					board.issue(dto.ImportIssueWarning, row, category.Name, item.Title, fmt.Sprintf("priority %q is unknown and was dropped", c.Priority))
+				}
+				c.Priority = dto.PriorityNone
+			}
+			if c.EstimatedTime != nil && *c.EstimatedTime < 0 {
+				board.issue(dto.ImportIssueWarning, row, category.Name, item.Title, "estimated time is negative and was dropped")
+				c.EstimatedTime = nil
+			}
+			for _, name := range item.Labels {
+				color, ok := colors[strings.ToLower(name)]
+				if !ok {
+					color = defaultImportLabelColor
+				}
+				c.Labels = append(c.Labels, importLabel{Name: name, Color: color})
+			}
+			for _, a := range item.Assignees {
+				user := importUser{Email: strings.ToLower(a.Email), Username: a.Username}
+				if user.Email != "" || user.Username != "" {
+					c.Assignees = append(c.Assignees, user)
+				}
+			}
+			column.Cards = append(column.Cards, c)
+		}
+	}
+	return board, nil
+}
+
+// -------------------------------------------------------------
+// Trello
+// -------------------------------------------------------------
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloExport struct {
+	Name  string       `json:"name"`
+	Lists []trelloList `json:"lists"`
+	Cards []struct {
+		Name      string   `json:"name"`
+		Desc      string   `json:"desc"`
+		IDList    string   `json:"idList"`
+		Closed    bool     `json:"closed"`
+		Pos       float64  `json:"pos"`
+		Due       *string  `json:"due"`
+		IDLabels  []string `json:"idLabels"`
+		IDMembers []string `json:"idMembers"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+}
+
+// parseTrello reads a Trello board export. Archived lists and cards are left
+// out, rows are the positions of the cards in the export.
+func parseTrello(data []byte, maxRows int) (*importBoard, error) {
+	var export trelloExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, utils.NewFieldError("file", "is not a Trello board export")
+	}
+	if export.Lists == nil || export.Cards == nil {
+		return nil, utils.NewFieldError("file", "is not a Trello board export")
+	}
+	if len(export.Cards) > maxRows {
+		return nil, utils.NewFieldError("file", fmt.Sprintf("has more than %d cards", maxRows))
+	}
+
+	board := &importBoard{Name: export.Name, Rows: len(export.Cards)}
+
+	labels := make(map[string]importLabel, len(export.Labels))
+	for _, l := range export.Labels {
+		base, _, _ := strings.Cut(l.Color, "_")
+		color, ok := trelloLabelColors[base]
+		if !ok {
+			color = defaultImportLabelColor
+		}
+		name := l.Name
+		if name == "" {
+			name = base
+		}
+		if name == "" {
+			continue
+		}
+		labels[l.ID] = importLabel{Name: name, Color: color}
+	}
+	members := make(map[string]string, len(export.Members))
+	for _, m := range export.Members {
+		members[m.ID] = m.Username
+	}
+
+	lists := export.Lists
+	slices.SortStableFunc(lists, func(a, b trelloList) int {
+		switch {
+		case a.Pos < b.Pos:
+			return -1
+		case a.Pos > b.Pos:
+			return 1
+		}
+		return 0
+	})
+	listNames := make(map[string]string, len(lists))
+	closedLists := make(map[string]bool)
+	for _, l := range lists {
+		listNames[l.ID] = l.Name
+		if l.Closed {
+			closedLists[l.ID] = true
+			continue
+		}
+		board.column(l.Name)
+	}
+
+	type position struct {
+		row int
+		pos float64
+	}
+	order := make([]position, 0, len(export.Cards))
+	for i, card := range export.Cards {
+		order = append(order, position{row: i + 1, pos: card.Pos})
+	}
+	slices.SortStableFunc(order, func(a, b position) int {
+		switch {
+		case a.pos < b.pos:
+			return -1
+		case a.pos > b.pos:
+			return 1
+		}
+		return 0
+	})
+
+	for _, p := range order {
+		card := export.Cards[p.row-1]
+		listName, ok := listNames[card.IDList]
+		switch {
+		case !ok:
+			board.issue(dto.ImportIssueError, p.row, "", card.Name, "card belongs to an unknown list")
+			continue
+		case card.Closed || closedLists[card.IDList]:
+			board.issue(dto.ImportIssueWarning, p.row, listName, card.Name, "card is archived in Trello and was skipped")
+			continue
+		}
+
+		c := importCard{
+			Row:         p.row,
+			Title:       card.Name,
+			Description: card.Desc,
+		}
+		if card.Due != nil && *card.Due != "" {
+			due, err := time.Parse(time.RFC3339, *card.Due)
+			if err != nil {
+				board.issue(dto.ImportIssueWarning, p.row, listName, card.Name, "due date is not a valid date and was dropped")
+			} else {
+				c.DueDate = &due
+			}
+		}
+		for _, id := range card.IDLabels {
+			if label, ok := labels[id]; ok {
+				c.Labels = append(c.Labels, label)
+			}
+		}
+		for _, id := range card.IDMembers {
+			if username, ok := members[id]; ok && username != "" {
+				c.Assignees = append(c.Assignees, importUser{Username: username})
+			}
+		}
+
+		column := board.column(listName)
+		column.Cards = append(column.Cards, c)
+	}
+	return board, nil
+}
+
+// -------------------------------------------------------------
+// CSV
+// -------------------------------------------------------------
+
+// Header names accepted for each CSV field
+var csvImportHeaders = map[string][]string{
+	"column":      {"column", "list", "category", "status"},
+	"title":       {"title", "name", "card"},
+	"description": {"description", "desc"},
+	"due":         {"due", "due date", "duedate"},
+	"labels":      {"labels", "label"},
+	"assignee":    {"assignee email", "assignee", "email", "assignees"},
+}
+
+// parseCSV reads a CSV file with a header row, only the title column is
+// required. Labels and assignees may list several values separated by
+// semicolons or commas. Rows are numbered as lines of the file. Cells of
+// files written by the CSV export lose the quote added against formulas.
+func parseCSV(data []byte, maxRows int) (*importBoard, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, utils.NewFieldError("file", "is not a CSV file with a header row")
+	}
+	fields := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, names := range csvImportHeaders {
+			if _, taken := fields[field]; !taken && slices.Contains(names, name) {
+				fields[field] = i
+			}
+		}
+	}
+	if _, ok := fields["title"]; !ok {
+		return nil, utils.NewFieldError("file", "needs a title column")
+	}
+	exported := slices.Equal(header, csvExportHeader)
+
+	board := &importBoard{}
+	row := 1
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, utils.NewFieldError("file", fmt.Sprintf("line %d is not valid CSV", parseErr.Line))
+			}
+			return nil, utils.NewError(http.StatusBadRequest, "failed to read file", err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		board.Rows++
+		if board.Rows > maxRows {
+			return nil, utils.NewFieldError("file", fmt.Sprintf("has more than %d rows", maxRows))
+		}
+
+		get := func(field string) string {
+			i, ok := fields[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			if exported {
+				return strings.TrimSpace(utils.UnescapeCSVCell(record[i]))
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		columnName := get("column")
+		if columnName == "" {
+			columnName = dto.DefaultKanbanCategory
+		}
+		c := importCard{
+			Row:         row,
+			Title:       get("title"),
+			Description: get("description"),
+		}
+		if due := get("due"); due != "" {
+			t, err := parseImportDate(due)
+			if err != nil {
+				board.issue(dto.ImportIssueWarning, row, columnName, c.Title, "due date is not a valid date and was dropped")
+			} else {
+				c.DueDate = &t
+			}
+		}
+		for _, name := range splitImportList(get("labels")) {
+			c.Labels = append(c.Labels, importLabel{Name: name, Color: defaultImportLabelColor})
+		}
+		for _, email := range splitImportList(get("assignee")) {
+			c.Assignees = append(c.Assignees, importUser{Email: strings.ToLower(email)})
+		}
+
+		column := board.column(columnName)
+		column.Cards = append(column.Cards, c)
+	}
+	return board, nil
+}
+
+// parseImportDate reads an RFC 3339 time or a date, dates are due at midnight UTC
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func splitImportList(value string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
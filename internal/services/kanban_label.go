@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// -------------------------------------------------------------
+// Organisation labels
+// -------------------------------------------------------------
+
+// OrganisationLabels lists the labels shared by every kanban of an organisation
+func (s *KanbanService) OrganisationLabels(ctx context.Context, orgID string) ([]repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("org_id", orgID)
+
+	labels, err := s.repos.Kanban.ListOrganisationLabels(ctx, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list labels")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list labels", err)
+	}
+	return labels, nil
+}
+
+func (s *KanbanService) CreateOrganisationLabel(ctx context.Context, orgID string, params dto.CreateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	return s.createLabel(ctx, orgID, nil, params)
+}
+
+func (s *KanbanService) UpdateOrganisationLabel(ctx context.Context, orgID, labelID string, params dto.UpdateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	return s.updateLabel(ctx, orgID, nil, labelID, params)
+}
+
+func (s *KanbanService) DeleteOrganisationLabel(ctx context.Context, orgID, labelID string) error {
+	return s.deleteLabel(ctx, orgID, nil, labelID)
+}
+
+// -------------------------------------------------------------
+// Kanban labels
+// -------------------------------------------------------------
+
+// BoardLabels lists the organisation and kanban labels usable on a kanban
+func (s *KanbanService) BoardLabels(ctx context.Context, projectID, kanbanID string) ([]repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	labels, err := s.repos.Kanban.ListBoardLabels(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list labels")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list labels", err)
+	}
+	return labels, nil
+}
+
+func (s *KanbanService) CreateBoardLabel(ctx context.Context, orgID, projectID, kanbanID string, params dto.CreateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	return s.createLabel(ctx, orgID, &kanbanID, params)
+}
+
+func (s *KanbanService) UpdateBoardLabel(ctx context.Context, orgID, projectID, kanbanID, labelID string, params dto.UpdateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	return s.updateLabel(ctx, orgID, &kanbanID, labelID, params)
+}
+
+func (s *KanbanService) DeleteBoardLabel(ctx context.Context, orgID, projectID, kanbanID, labelID string) error {
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return err
+	}
+	return s.deleteLabel(ctx, orgID, &kanbanID, labelID)
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// createLabel creates a label in the organisation, scoped to a kanban when
+// kanbanID is set
+func (s *KanbanService) createLabel(ctx context.Context, orgID string, kanbanID *string, params dto.CreateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("org_id", orgID)
+	logger.Infof("creating label: %s", params.Name)
+
+	label, err := s.repos.Kanban.CreateLabel(ctx, repository.CreateKanbanLabelParams{
+		ID:             gonanoid.Must(),
+		OrganisationID: orgID,
+		KanbanID:       utils.PtrToPgText(kanbanID),
+		Name:           params.Name,
+		Color:          params.Color,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, utils.NewError(http.StatusConflict, "a label with that name already exists", err)
+		}
+		logger.WithError(err).Error("failed to create label")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to create label", err)
+	}
+
+	logger.WithField("label_id", label.ID).Info("label created")
+	s.publishLabel(ctx, utils.NewKanbanLabel, orgID, label)
+	return &label, nil
+}
+
+func (s *KanbanService) updateLabel(ctx context.Context, orgID string, kanbanID *string, labelID string, params dto.UpdateKanbanLabelInput) (*repository.KanbanLabel, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"org_id":   orgID,
+		"label_id": labelID,
+	})
+
+	var label repository.KanbanLabel
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getScopedLabel(ctx, q, orgID, kanbanID, labelID); err != nil {
+			return err
+		}
+
+		var err error
+		label, err = q.UpdateKanbanLabel(ctx, repository.UpdateKanbanLabelParams{
+			ID:             labelID,
+			OrganisationID: orgID,
+			Name:           utils.PtrToPgText(params.Name),
+			Color:          utils.PtrToPgText(params.Color),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a label with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to update label")
+			return utils.NewError(http.StatusInternalServerError, "failed to update label", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("label updated")
+	s.publishLabel(ctx, utils.EditKanbanLabel, orgID, label)
+	return &label, nil
+}
+
+func (s *KanbanService) deleteLabel(ctx context.Context, orgID string, kanbanID *string, labelID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"org_id":   orgID,
+		"label_id": labelID,
+	})
+
+	var label repository.KanbanLabel
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		var err error
+		label, err = getScopedLabel(ctx, q, orgID, kanbanID, labelID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := q.DeleteKanbanLabel(ctx, repository.DeleteKanbanLabelParams{
+			ID:             labelID,
+			OrganisationID: orgID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to delete label")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete label", err)
+		}
+		if rows == 0 {
+			return utils.NewError(http.StatusNotFound, "label not found", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("label deleted")
+	s.publishLabel(ctx, utils.DeleteKanbanLabel, orgID, label)
+	return nil
+}
+
+// getScopedLabel fetches a label of the organisation and makes sure it
+// belongs to the given kanban, or to no kanban when kanbanID is nil
+func getScopedLabel(ctx context.Context, q repository.Querier, orgID string, kanbanID *string, labelID string) (repository.KanbanLabel, error) {
+	label, err := q.GetKanbanLabel(ctx, repository.GetKanbanLabelParams{
+		ID:             labelID,
+		OrganisationID: orgID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return label, utils.NewError(http.StatusNotFound, "label not found", err)
+		}
+		return label, utils.NewError(http.StatusInternalServerError, "failed to fetch label", err)
+	}
+
+	if kanbanID == nil && label.KanbanID.Valid {
+		return label, utils.NewError(http.StatusNotFound, "label not found", nil)
+	}
+	if kanbanID != nil && label.KanbanID.String != *kanbanID {
+		return label, utils.NewError(http.StatusNotFound, "label not found", nil)
+	}
+	return label, nil
+}
+
+// publishLabel sends a label change to its kanban, organisation labels are
+// sent to every kanban of the organisation
+func (s *KanbanService) publishLabel(ctx context.Context, typ utils.MessageType, orgID string, label repository.KanbanLabel) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := dto.KanbanLabelEvent{
+		Label:    label,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	}
+	if label.KanbanID.Valid {
+		s.publish(ctx, typ, label.KanbanID.String, event)
+		return
+	}
+
+	kanbanIDs, err := s.repos.Kanban.ListOrganisationKanbanIDs(ctx, orgID)
+	if err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithError(err).Warn("failed to list kanbans for label change")
+		return
+	}
+	for _, kanbanID := range kanbanIDs {
+		s.publish(ctx, typ, kanbanID, event)
+	}
+}
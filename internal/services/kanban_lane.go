@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Lanes lists the swimlanes of a kanban from top to bottom
+func (s *KanbanService) Lanes(ctx context.Context, projectID, kanbanID string) ([]repository.KanbanLane, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	lanes, err := s.repos.Kanban.ListLanes(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list lanes")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list lanes", err)
+	}
+	return lanes, nil
+}
+
+// CreateLane adds a swimlane below the others
+func (s *KanbanService) CreateLane(ctx context.Context, projectID, kanbanID string, params dto.CreateKanbanLaneInput) (*repository.KanbanLane, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	var lane repository.KanbanLane
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		if err := q.LockKanban(ctx, kanbanID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create lane", err)
+		}
+
+		id := gonanoid.Must()
+		rank, err := placeRank(laneRankScope(ctx, q, kanbanID, id), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		lane, err = q.CreateKanbanLane(ctx, repository.CreateKanbanLaneParams{
+			ID:       id,
+			KanbanID: kanbanID,
+			Name:     params.Name,
+			Color:    utils.PtrToPgText(params.Color),
+			Rank:     rank,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a lane with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to create lane")
+			return utils.NewError(http.StatusInternalServerError, "failed to create lane", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityLane, lane.ID, dto.ActivityCreate, nil, lane)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("lane_id", lane.ID).Info("lane created")
+	s.publish(ctx, utils.NewKanbanLane, kanbanID, dto.KanbanLaneEvent{
+		Lane:     lane,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &lane, nil
+}
+
+// UpdateLane renames a lane or changes its color, with an expected version
+// the update is refused when someone else changed the lane in the meantime
+func (s *KanbanService) UpdateLane(ctx context.Context, projectID, kanbanID, laneID string, params dto.UpdateKanbanLaneInput, expectedVersion *int32) (*repository.KanbanLane, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	if params.Color.Value != nil && !hexColor.MatchString(*params.Color.Value) {
+		return nil, utils.NewFieldError("color", "must be a hex color like #1a2b3c")
+	}
+
+	var lane repository.KanbanLane
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getLane(ctx, q, projectID, kanbanID, laneID)
+		if err != nil {
+			return err
+		}
+
+		lane, err = q.UpdateKanbanLane(ctx, repository.UpdateKanbanLaneParams{
+			ID:              laneID,
+			KanbanID:        kanbanID,
+			Name:            utils.PtrToPgText(params.Name),
+			SetColor:        params.Color.Set,
+			Color:           utils.PtrToPgText(params.Color.Value),
+			ExpectedVersion: utils.PtrToPgInt4(expectedVersion),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Warn("lane version conflict")
+				latest, err := getLane(ctx, q, projectID, kanbanID, laneID)
+				if err != nil {
+					return err
+				}
+				return utils.NewConflict("lane was changed by someone else", latest)
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a lane with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to update lane")
+			return utils.NewError(http.StatusInternalServerError, "failed to update lane", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityLane, laneID, dto.ActivityUpdate, current, lane)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("lane updated")
+	s.publish(ctx, utils.EditKanbanLane, kanbanID, dto.KanbanLaneEvent{
+		Lane:     lane,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return &lane, nil
+}
+
+// MoveLane places a lane after or before another lane of the kanban
+func (s *KanbanService) MoveLane(ctx context.Context, projectID, kanbanID, laneID string, params dto.MoveKanbanLaneInput) (*repository.KanbanLane, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	var (
+		lane       repository.KanbanLane
+		rebalanced []dto.KanbanRank
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		current, err := getLane(ctx, q, projectID, kanbanID, laneID)
+		if err != nil {
+			return err
+		}
+		if err := q.LockKanban(ctx, kanbanID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to move lane", err)
+		}
+
+		after, err := siblingLaneRank(ctx, q, kanbanID, laneID, params.AfterID)
+		if err != nil {
+			return err
+		}
+		before, err := siblingLaneRank(ctx, q, kanbanID, laneID, params.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		scope := laneRankScope(ctx, q, kanbanID, laneID)
+		rank, err := placeRank(scope, after, before)
+		if err != nil {
+			return err
+		}
+
+		lane, err = q.SetKanbanLaneRank(ctx, repository.SetKanbanLaneRankParams{
+			ID:   laneID,
+			Rank: rank,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to move lane")
+			return utils.NewError(http.StatusInternalServerError, "failed to move lane", err)
+		}
+
+		if len(rank) > maxRankLength {
+			logger.Info("rebalancing lane order")
+			rebalanced, err = rebalance(scope)
+			if err != nil {
+				return err
+			}
+			lane.Rank = rankOf(rebalanced, laneID)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityLane, laneID, dto.ActivityMove, current, lane)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("lane moved")
+	s.publish(ctx, utils.MoveKanbanLane, kanbanID, dto.KanbanLaneEvent{
+		Lane:     lane,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	if rebalanced != nil {
+		s.publish(ctx, utils.RebalanceKanbanLanes, kanbanID, dto.KanbanRebalanceEvent{
+			Ranks:    rebalanced,
+			SenderID: utils.GetUserIDFromContext(ctx),
+		})
+	}
+	return &lane, nil
+}
+
+// DeleteLane removes a lane, its cards stay on the board without a lane
+func (s *KanbanService) DeleteLane(ctx context.Context, projectID, kanbanID, laneID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"lane_id":    laneID,
+	})
+
+	var itemIDs []string
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		lane, err := getLane(ctx, q, projectID, kanbanID, laneID)
+		if err != nil {
+			return err
+		}
+		itemIDs, err = q.ListKanbanLaneItemIDs(ctx, utils.PtrToPgText(&laneID))
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to delete lane", err)
+		}
+		if _, err := q.DeleteKanbanLane(ctx, repository.DeleteKanbanLaneParams{
+			ID:       laneID,
+			KanbanID: kanbanID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to delete lane")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete lane", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityLane, laneID, dto.ActivityPermaDelete, lane, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if itemIDs == nil {
+		itemIDs = []string{}
+	}
+	logger.Info("lane deleted")
+	s.publish(ctx, utils.DeleteKanbanLane, kanbanID, dto.KanbanLaneDeleteEvent{
+		LaneID:   laneID,
+		ItemIDs:  itemIDs,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+	return nil
+}
+
+// boardLanes groups the cards of a loaded board by lane and category. Custom
+// lanes keep their order, assignee lanes follow the board and an item is put
+// in the lane of its first assignee.
+func boardLanes(laneBy dto.KanbanLaneGrouping, lanes []repository.KanbanLane, categories []dto.KanbanBoardCategory) []dto.KanbanBoardLane {
+	var laneKey func(card dto.KanbanCard) string
+	var keys []string
+	switch laneBy {
+	case dto.LaneByAssignee:
+		laneKey = func(card dto.KanbanCard) string {
+			if len(card.Assignees) == 0 {
+				return ""
+			}
+			return card.Assignees[0]
+		}
+		seen := make(map[string]bool)
+		for _, category := range categories {
+			for _, card := range category.Items {
+				if key := laneKey(card); key != "" && !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+	case dto.LaneByPriority:
+		laneKey = func(card dto.KanbanCard) string {
+			if card.Priority == string(dto.PriorityNone) {
+				return ""
+			}
+			return card.Priority
+		}
+		keys = []string{
+			string(dto.PriorityExtreme),
+			string(dto.PriorityHigh),
+			string(dto.PriorityMedium),
+			string(dto.PriorityLow),
+		}
+	default:
+		laneKey = func(card dto.KanbanCard) string {
+			return card.LaneID.String
+		}
+		for _, lane := range lanes {
+			keys = append(keys, lane.ID)
+		}
+	}
+	keys = append(keys, "")
+
+	result := make([]dto.KanbanBoardLane, len(keys))
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+		result[i] = dto.KanbanBoardLane{
+			Key:        key,
+			Categories: make([]dto.KanbanBoardLaneCategory, len(categories)),
+		}
+		if i < len(lanes) {
+			result[i].Lane = &lanes[i]
+		}
+		for j, category := range categories {
+			result[i].Categories[j] = dto.KanbanBoardLaneCategory{
+				CategoryID: category.ID,
+				ItemIDs:    []string{},
+			}
+		}
+	}
+
+	for j, category := range categories {
+		for _, card := range category.Items {
+			i, ok := index[laneKey(card)]
+			if !ok {
+				i = len(keys) - 1
+			}
+			result[i].Categories[j].ItemIDs = append(result[i].Categories[j].ItemIDs, card.ID)
+		}
+	}
+	return result
+}
+
+// getLane fetches a lane of a kanban in the project
+func getLane(ctx context.Context, q repository.Querier, projectID, kanbanID, laneID string) (repository.KanbanLane, error) {
+	if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+		return repository.KanbanLane{}, err
+	}
+
+	lane, err := q.GetKanbanLane(ctx, repository.GetKanbanLaneParams{
+		ID:       laneID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return lane, utils.NewError(http.StatusNotFound, "lane not found", err)
+		}
+		return lane, utils.NewError(http.StatusInternalServerError, "failed to fetch lane", err)
+	}
+	return lane, nil
+}
+
+// itemLane checks that a lane given for an item belongs to its kanban, no
+// lane is valid
+func itemLane(ctx context.Context, q repository.Querier, kanbanID string, laneID *string) (pgtype.Text, error) {
+	if laneID == nil {
+		return pgtype.Text{}, nil
+	}
+	if _, err := q.GetKanbanLane(ctx, repository.GetKanbanLaneParams{
+		ID:       *laneID,
+		KanbanID: kanbanID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgtype.Text{}, utils.NewFieldError("laneId", "is not a lane of this kanban")
+		}
+		return pgtype.Text{}, utils.NewError(http.StatusInternalServerError, "failed to fetch lane", err)
+	}
+	return utils.PtrToPgText(laneID), nil
+}
+
+// siblingLaneRank returns the rank of a neighbour lane given by id
+func siblingLaneRank(ctx context.Context, q repository.Querier, kanbanID, laneID string, siblingID *string) (*string, error) {
+	if siblingID == nil {
+		return nil, nil
+	}
+	if *siblingID == laneID {
+		return nil, utils.NewError(http.StatusBadRequest, "a lane can't be placed next to itself", nil)
+	}
+
+	sibling, err := q.GetKanbanLane(ctx, repository.GetKanbanLaneParams{
+		ID:       *siblingID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "neighbour lane not found", err)
+		}
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch lane", err)
+	}
+	return &sibling.Rank, nil
+}
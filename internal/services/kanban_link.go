@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// -------------------------------------------------------------
+// Links
+// -------------------------------------------------------------
+
+// ItemLinks lists the links of an item and whether it is blocked
+func (s *KanbanService) ItemLinks(ctx context.Context, projectID, kanbanID, itemID string) (*dto.GetKanbanItemLinksResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	links, err := s.repos.Kanban.ListItemLinks(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list links")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list links", err)
+	}
+	blockers, err := s.repos.Kanban.ListItemBlockers(ctx, itemID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list blockers")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list links", err)
+	}
+
+	return &dto.GetKanbanItemLinksResponse{
+		Links:   links,
+		Blocked: len(blockers) > 0,
+	}, nil
+}
+
+// CreateItemLink links an item to another card of the same project, which
+// may be on another kanban. Blocking links are refused when they would
+// close a cycle, link changes in a project are serialised so two concurrent
+// links can't form one together.
+func (s *KanbanService) CreateItemLink(ctx context.Context, projectID, kanbanID, itemID string, params dto.CreateKanbanItemLinkInput) (*repository.KanbanItemLink, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":     projectID,
+		"kanban_id":      kanbanID,
+		"item_id":        itemID,
+		"linked_item_id": params.ItemID,
+		"type":           params.Type,
+	})
+
+	if params.ItemID == itemID {
+		return nil, utils.NewError(http.StatusBadRequest, "an item can't be linked to itself", nil)
+	}
+
+	var (
+		link          repository.KanbanItemLink
+		linkedKanban  string
+		targetBlocked bool
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+		linked, err := getProjectItem(ctx, q, projectID, params.ItemID)
+		if err != nil {
+			return err
+		}
+		if linked.DeletedAt.Valid {
+			return utils.NewError(http.StatusConflict, "linked item is archived", nil)
+		}
+		linkedKanban = linked.KanbanID
+
+		if err := q.LockKanbanItemLinks(ctx, projectID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to link items", err)
+		}
+
+		// blocked_by is stored as the other item blocking this one
+		from, to, typ := itemID, params.ItemID, string(params.Type)
+		if params.Type == dto.LinkBlockedBy {
+			from, to, typ = params.ItemID, itemID, string(dto.LinkBlocks)
+		}
+
+		if typ == string(dto.LinkBlocks) {
+			cycle, err := q.KanbanItemBlockPathExists(ctx, repository.KanbanItemBlockPathExistsParams{
+				FromItemID: to,
+				ToItemID:   from,
+			})
+			if err != nil {
+				logger.WithError(err).Error("failed to check for dependency cycles")
+				return utils.NewError(http.StatusInternalServerError, "failed to link items", err)
+			}
+			if cycle {
+				return utils.NewError(http.StatusConflict, "link would create a dependency cycle", nil)
+			}
+		}
+
+		link, err = q.CreateKanbanItemLink(ctx, repository.CreateKanbanItemLinkParams{
+			ID:           gonanoid.Must(),
+			ProjectID:    projectID,
+			ItemID:       from,
+			LinkedItemID: to,
+			Type:         typ,
+			CreatedBy:    utils.PtrToPgText(userIDFromContext(ctx)),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "items are already linked", err)
+			}
+			logger.WithError(err).Error("failed to link items")
+			return utils.NewError(http.StatusInternalServerError, "failed to link items", err)
+		}
+
+		if link.Type == string(dto.LinkBlocks) {
+			targetBlocked, err = isItemBlocked(ctx, q, link.LinkedItemID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("link_id", link.ID).Info("items linked")
+	s.publishLink(ctx, utils.NewKanbanItemLink, link, kanbanID, linkedKanban)
+	if link.Type == string(dto.LinkBlocks) {
+		s.publishBlocked(ctx, blockedKanban(link, itemID, kanbanID, linkedKanban), link.LinkedItemID, targetBlocked)
+	}
+	return &link, nil
+}
+
+func (s *KanbanService) DeleteItemLink(ctx context.Context, projectID, kanbanID, itemID, linkID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"link_id":    linkID,
+	})
+
+	var (
+		link          repository.KanbanItemLink
+		linkedKanban  string
+		targetBlocked bool
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		var err error
+		link, err = q.GetKanbanItemLink(ctx, repository.GetKanbanItemLinkParams{
+			ID:     linkID,
+			ItemID: itemID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "link not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch link")
+			return utils.NewError(http.StatusInternalServerError, "failed to fetch link", err)
+		}
+
+		otherID := link.LinkedItemID
+		if otherID == itemID {
+			otherID = link.ItemID
+		}
+		other, err := getProjectItem(ctx, q, projectID, otherID)
+		if err != nil {
+			return err
+		}
+		linkedKanban = other.KanbanID
+
+		if err := q.LockKanbanItemLinks(ctx, projectID); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to delete link", err)
+		}
+		if _, err := q.DeleteKanbanItemLink(ctx, linkID); err != nil {
+			logger.WithError(err).Error("failed to delete link")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete link", err)
+		}
+
+		if link.Type == string(dto.LinkBlocks) {
+			targetBlocked, err = isItemBlocked(ctx, q, link.LinkedItemID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("link deleted")
+	s.publishLink(ctx, utils.DeleteKanbanItemLink, link, kanbanID, linkedKanban)
+	if link.Type == string(dto.LinkBlocks) {
+		s.publishBlocked(ctx, blockedKanban(link, itemID, kanbanID, linkedKanban), link.LinkedItemID, targetBlocked)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// checkBlockedMove is the hook run before an item moves into a done column.
+// Blocked items are refused or, when the kanban only warns, a warning is
+// returned and the move goes ahead.
+func checkBlockedMove(ctx context.Context, q repository.Querier, kanban repository.Kanban, itemID string) (*utils.APIError, error) {
+	blockers, err := q.ListKanbanItemBlockers(ctx, itemID)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to check blocking items", err)
+	}
+	if len(blockers) == 0 {
+		return nil, nil
+	}
+
+	titles := make([]string, 0, len(blockers))
+	for _, b := range blockers {
+		titles = append(titles, b.Title)
+	}
+	message := fmt.Sprintf("item is blocked by unfinished items: %s", strings.Join(titles, ", "))
+	return applyMovePolicy(kanban.BlockedMovePolicy, message)
+}
+
+// publishDependents tells the kanbans of the items blocked by an item about
+// their blocked state, after the item was finished, reopened or archived
+func (s *KanbanService) publishDependents(ctx context.Context, itemID string) {
+	if s.publisher == nil {
+		return
+	}
+
+	dependents, err := s.repos.Kanban.ListItemDependents(ctx, itemID)
+	if err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithError(err).Warn("failed to list dependent items")
+		return
+	}
+	for _, d := range dependents {
+		s.publishBlocked(ctx, d.KanbanID, d.ID, d.Blocked)
+	}
+}
+
+func (s *KanbanService) publishBlocked(ctx context.Context, kanbanID, itemID string, blocked bool) {
+	s.publish(ctx, utils.KanbanItemBlocked, kanbanID, dto.KanbanItemBlockedEvent{
+		ItemID:   itemID,
+		Blocked:  blocked,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	})
+}
+
+// publishLink sends a link change to both kanbans it connects
+func (s *KanbanService) publishLink(ctx context.Context, typ utils.MessageType, link repository.KanbanItemLink, kanbanID, linkedKanbanID string) {
+	event := dto.KanbanItemLinkEvent{
+		Link:     link,
+		SenderID: utils.GetUserIDFromContext(ctx),
+	}
+	s.publish(ctx, typ, kanbanID, event)
+	if linkedKanbanID != kanbanID {
+		s.publish(ctx, typ, linkedKanbanID, event)
+	}
+}
+
+// blockedKanban returns the kanban of the blocked end of a link
+func blockedKanban(link repository.KanbanItemLink, itemID, kanbanID, linkedKanbanID string) string {
+	if link.LinkedItemID == itemID {
+		return kanbanID
+	}
+	return linkedKanbanID
+}
+
+// getProjectItem fetches an item on any kanban of the project
+func getProjectItem(ctx context.Context, q repository.Querier, projectID, itemID string) (repository.GetProjectKanbanItemRow, error) {
+	item, err := q.GetProjectKanbanItem(ctx, repository.GetProjectKanbanItemParams{
+		ID:        itemID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return item, utils.NewError(http.StatusNotFound, "linked item not found", err)
+		}
+		return item, utils.NewError(http.StatusInternalServerError, "failed to fetch linked item", err)
+	}
+	return item, nil
+}
+
+func isItemBlocked(ctx context.Context, q repository.Querier, itemID string) (bool, error) {
+	blockers, err := q.ListKanbanItemBlockers(ctx, itemID)
+	if err != nil {
+		return false, utils.NewError(http.StatusInternalServerError, "failed to check blocking items", err)
+	}
+	return len(blockers) > 0, nil
+}
+
+// userIDFromContext returns the acting user or nil when there is none
+func userIDFromContext(ctx context.Context) *string {
+	userID := utils.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil
+	}
+	return &userID
+}
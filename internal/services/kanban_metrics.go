@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Longest range of days metrics are computed over
+const maxMetricsDays = 366
+
+// -------------------------------------------------------------
+// Metrics
+// -------------------------------------------------------------
+
+// Metrics computes the flow metrics of a kanban for the days from and to,
+// both included
+func (s *KanbanService) Metrics(ctx context.Context, projectID, kanbanID string, from, to time.Time) (*dto.GetKanbanMetricsResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) {
+		return nil, utils.NewFieldError("to", "must not be before from")
+	}
+	if to.Sub(from) >= maxMetricsDays*24*time.Hour {
+		return nil, utils.NewFieldError("from", "range must not be longer than a year")
+	}
+	end := to.AddDate(0, 0, 1)
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repos.Kanban.ListCategories(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list categories")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to compute metrics", err)
+	}
+	completed, err := s.repos.Kanban.ListCompletedItems(ctx, repository.ListKanbanCompletedItemsParams{
+		KanbanID: kanbanID,
+		From:     utils.PtrToPgTimestamptz(&from),
+		To:       utils.PtrToPgTimestamptz(&end),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list completed items")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to compute metrics", err)
+	}
+	flow, err := s.repos.Kanban.CumulativeFlow(ctx, repository.KanbanCumulativeFlowParams{
+		KanbanID: kanbanID,
+		From:     pgtype.Date{Time: from, Valid: true},
+		To:       pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to compute cumulative flow")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to compute metrics", err)
+	}
+
+	lead := make([]float64, 0, len(completed))
+	cycle := make([]float64, 0, len(completed))
+	for _, item := range completed {
+		done := item.CompletedAt.Time
+		if item.CreatedAt.Valid {
+			lead = append(lead, done.Sub(item.CreatedAt.Time).Seconds())
+		}
+		// Cards completed without passing through a work column count from creation
+		started := item.CreatedAt
+		if item.StartedAt.Valid && item.StartedAt.Time.Before(done) {
+			started = item.StartedAt
+		}
+		if started.Valid {
+			cycle = append(cycle, done.Sub(started.Time).Seconds())
+		}
+	}
+
+	return &dto.GetKanbanMetricsResponse{
+		From:           from.Format(time.DateOnly),
+		To:             to.Format(time.DateOnly),
+		LeadTime:       durationStats(lead),
+		CycleTime:      durationStats(cycle),
+		Throughput:     weeklyThroughput(completed, from, to),
+		CumulativeFlow: cumulativeFlow(flow, categories, from, to),
+		Categories:     categories,
+	}, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// recordTransition stores that an item entered a category, from is nil for
+// newly created items
+func recordTransition(ctx context.Context, q repository.Querier, kanbanID, itemID string, from *string, to string) error {
+	if err := q.CreateKanbanItemTransition(ctx, repository.CreateKanbanItemTransitionParams{
+		ID:             gonanoid.Must(),
+		KanbanID:       kanbanID,
+		ItemID:         itemID,
+		FromCategoryID: utils.PtrToPgText(from),
+		ToCategoryID:   to,
+	}); err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithError(err).Error("failed to record transition")
+		return utils.NewError(http.StatusInternalServerError, "failed to record transition", err)
+	}
+	return nil
+}
+
+func durationStats(seconds []float64) dto.KanbanDurationStats {
+	stats := dto.KanbanDurationStats{Count: len(seconds)}
+	if len(seconds) == 0 {
+		return stats
+	}
+
+	sort.Float64s(seconds)
+	var total float64
+	for _, s := range seconds {
+		total += s
+	}
+	stats.Average = total / float64(len(seconds))
+	stats.P50 = percentile(seconds, 50)
+	stats.P85 = percentile(seconds, 85)
+	stats.P95 = percentile(seconds, 95)
+	return stats
+}
+
+// percentile uses the nearest rank of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// weeklyThroughput counts completed items per week, weeks without any are included
+func weeklyThroughput(completed []repository.ListKanbanCompletedItemsRow, from, to time.Time) []dto.KanbanThroughputWeek {
+	counts := map[time.Time]int{}
+	for _, item := range completed {
+		counts[weekStart(item.CompletedAt.Time)]++
+	}
+
+	weeks := []dto.KanbanThroughputWeek{}
+	for week := weekStart(from); !week.After(to); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, dto.KanbanThroughputWeek{
+			Week:  week.Format(time.DateOnly),
+			Items: counts[week],
+		})
+	}
+	return weeks
+}
+
+// cumulativeFlow lays the counts out per day, active categories without
+// cards on a day count zero
+func cumulativeFlow(rows []repository.KanbanCumulativeFlowRow, categories []repository.KanbanCategory, from, to time.Time) []dto.KanbanFlowDay {
+	byDay := map[string]map[string]int32{}
+	for _, row := range rows {
+		day := row.Day.Time.Format(time.DateOnly)
+		if byDay[day] == nil {
+			byDay[day] = map[string]int32{}
+		}
+		byDay[day][row.CategoryID] = row.Items
+	}
+
+	days := []dto.KanbanFlowDay{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		key := d.Format(time.DateOnly)
+		counts := byDay[key]
+		if counts == nil {
+			counts = map[string]int32{}
+		}
+		for _, c := range categories {
+			if _, ok := counts[c.ID]; !ok {
+				counts[c.ID] = 0
+			}
+		}
+		days = append(days, dto.KanbanFlowDay{
+			Day:        key,
+			Categories: counts,
+		})
+	}
+	return days
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday of the week of t
+func weekStart(t time.Time) time.Time {
+	day := truncateDay(t.UTC())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
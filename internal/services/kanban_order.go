@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// Ranks longer than this trigger a rebalance of the whole list
+const maxRankLength = 32
+
+// rankScope looks up neighbouring ranks in one ordered list, either the
+// categories of a kanban or the items of a category
+type rankScope struct {
+	last func() (string, error)
+	next func(rank string) (string, error)
+	prev func(rank string) (string, error)
+	list func() ([]dto.KanbanRank, error)
+	set  func(id, rank string) error
+}
+
+func categoryRankScope(ctx context.Context, q repository.Querier, kanbanID, excludeID string) rankScope {
+	return rankScope{
+		last: func() (string, error) {
+			return q.LastKanbanCategoryRank(ctx, kanbanID)
+		},
+		next: func(rank string) (string, error) {
+			return q.NextKanbanCategoryRank(ctx, repository.NextKanbanCategoryRankParams{
+				KanbanID:  kanbanID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		prev: func(rank string) (string, error) {
+			return q.PrevKanbanCategoryRank(ctx, repository.PrevKanbanCategoryRankParams{
+				KanbanID:  kanbanID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		list: func() ([]dto.KanbanRank, error) {
+			rows, err := q.ListKanbanCategoryRanks(ctx, kanbanID)
+			if err != nil {
+				return nil, err
+			}
+			ranks := make([]dto.KanbanRank, len(rows))
+			for i, row := range rows {
+				ranks[i] = dto.KanbanRank{ID: row.ID, Rank: row.Rank}
+			}
+			return ranks, nil
+		},
+		set: func(id, rank string) error {
+			_, err := q.SetKanbanCategoryRank(ctx, repository.SetKanbanCategoryRankParams{
+				ID:   id,
+				Rank: rank,
+			})
+			return err
+		},
+	}
+}
+
+func laneRankScope(ctx context.Context, q repository.Querier, kanbanID, excludeID string) rankScope {
+	return rankScope{
+		last: func() (string, error) {
+			return q.LastKanbanLaneRank(ctx, kanbanID)
+		},
+		next: func(rank string) (string, error) {
+			return q.NextKanbanLaneRank(ctx, repository.NextKanbanLaneRankParams{
+				KanbanID:  kanbanID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		prev: func(rank string) (string, error) {
+			return q.PrevKanbanLaneRank(ctx, repository.PrevKanbanLaneRankParams{
+				KanbanID:  kanbanID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		list: func() ([]dto.KanbanRank, error) {
+			rows, err := q.ListKanbanLaneRanks(ctx, kanbanID)
+			if err != nil {
+				return nil, err
+			}
+			ranks := make([]dto.KanbanRank, len(rows))
+			for i, row := range rows {
+				ranks[i] = dto.KanbanRank{ID: row.ID, Rank: row.Rank}
+			}
+			return ranks, nil
+		},
+		set: func(id, rank string) error {
+			_, err := q.SetKanbanLaneRank(ctx, repository.SetKanbanLaneRankParams{
+				ID:   id,
+				Rank: rank,
+			})
+			return err
+		},
+	}
+}
+
+func itemRankScope(ctx context.Context, q repository.Querier, categoryID, excludeID string) rankScope {
+	return rankScope{
+		last: func() (string, error) {
+			return q.LastKanbanItemRank(ctx, categoryID)
+		},
+		next: func(rank string) (string, error) {
+			return q.NextKanbanItemRank(ctx, repository.NextKanbanItemRankParams{
+				KanbanCategoryID: categoryID,
+				Rank:             rank,
+				ExcludeID:        excludeID,
+			})
+		},
+		prev: func(rank string) (string, error) {
+			return q.PrevKanbanItemRank(ctx, repository.PrevKanbanItemRankParams{
+				KanbanCategoryID: categoryID,
+				Rank:             rank,
+				ExcludeID:        excludeID,
+			})
+		},
+		list: func() ([]dto.KanbanRank, error) {
+			rows, err := q.ListKanbanItemRanks(ctx, categoryID)
+			if err != nil {
+				return nil, err
+			}
+			ranks := make([]dto.KanbanRank, len(rows))
+			for i, row := range rows {
+				ranks[i] = dto.KanbanRank{ID: row.ID, Rank: row.Rank}
+			}
+			return ranks, nil
+		},
+		set: func(id, rank string) error {
+			return q.SetKanbanItemRank(ctx, repository.SetKanbanItemRankParams{
+				ID:   id,
+				Rank: rank,
+			})
+		},
+	}
+}
+
+// placeRank returns a rank after the after rank and before the before rank,
+// missing neighbours are looked up so the row lands directly next to the given one.
+// Without either the row goes last.
+func placeRank(scope rankScope, after, before *string) (string, error) {
+	var (
+		lower, upper string
+		err          error
+	)
+	switch {
+	case after != nil && before != nil:
+		lower, upper = *after, *before
+		if lower >= upper {
+			return "", utils.NewError(http.StatusBadRequest, "after must be placed before before", nil)
+		}
+	case after != nil:
+		lower = *after
+		upper, err = scope.next(lower)
+	case before != nil:
+		upper = *before
+		lower, err = scope.prev(upper)
+	default:
+		lower, err = scope.last()
+	}
+	if err != nil {
+		return "", utils.NewError(http.StatusInternalServerError, "failed to read order", err)
+	}
+
+	rank, err := utils.RankBetween(lower, upper)
+	if err != nil {
+		return "", utils.NewError(http.StatusInternalServerError, "failed to compute order", err)
+	}
+	return rank, nil
+}
+
+// rebalance spreads the ranks of a list evenly, it returns the new ranks
+func rebalance(scope rankScope) ([]dto.KanbanRank, error) {
+	ranks, err := scope.list()
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to read order", err)
+	}
+
+	keys := utils.RankSequence(len(ranks))
+	for i := range ranks {
+		ranks[i].Rank = keys[i]
+		if err := scope.set(ranks[i].ID, keys[i]); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, "failed to rebalance order", err)
+		}
+	}
+	return ranks, nil
+}
+
+// rankOf finds the rank of one row in a rebalanced list
+func rankOf(ranks []dto.KanbanRank, id string) string {
+	for _, r := range ranks {
+		if r.ID == id {
+			return r.Rank
+		}
+	}
+	return ""
+}
+
+func checklistRankScope(ctx context.Context, q repository.Querier, itemID, excludeID string) rankScope {
+	return rankScope{
+		last: func() (string, error) {
+			return q.LastKanbanChecklistRank(ctx, itemID)
+		},
+		next: func(rank string) (string, error) {
+			return q.NextKanbanChecklistRank(ctx, repository.NextKanbanChecklistRankParams{
+				ItemID:    itemID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		prev: func(rank string) (string, error) {
+			return q.PrevKanbanChecklistRank(ctx, repository.PrevKanbanChecklistRankParams{
+				ItemID:    itemID,
+				Rank:      rank,
+				ExcludeID: excludeID,
+			})
+		},
+		list: func() ([]dto.KanbanRank, error) {
+			rows, err := q.ListKanbanChecklistRanks(ctx, itemID)
+			if err != nil {
+				return nil, err
+			}
+			ranks := make([]dto.KanbanRank, len(rows))
+			for i, row := range rows {
+				ranks[i] = dto.KanbanRank{ID: row.ID, Rank: row.Rank}
+			}
+			return ranks, nil
+		},
+		set: func(id, rank string) error {
+			return q.SetKanbanChecklistRank(ctx, repository.SetKanbanChecklistRankParams{
+				ID:   id,
+				Rank: rank,
+			})
+		},
+	}
+}
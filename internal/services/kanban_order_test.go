@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// memoryRanks is an ordered list kept in memory, its scope answers like the
+// rank queries do
+type memoryRanks struct {
+	rows []dto.KanbanRank
+	err  error
+}
+
+func (m *memoryRanks) scope() rankScope {
+	return rankScope{
+		last: func() (string, error) {
+			if m.err != nil {
+				return "", m.err
+			}
+			if len(m.rows) == 0 {
+				return "", nil
+			}
+			return m.sorted()[len(m.rows)-1].Rank, nil
+		},
+		next: func(rank string) (string, error) {
+			for _, r := range m.sorted() {
+				if r.Rank > rank {
+					return r.Rank, m.err
+				}
+			}
+			return "", m.err
+		},
+		prev: func(rank string) (string, error) {
+			rows := m.sorted()
+			for i := len(rows) - 1; i >= 0; i-- {
+				if rows[i].Rank < rank {
+					return rows[i].Rank, m.err
+				}
+			}
+			return "", m.err
+		},
+		list: func() ([]dto.KanbanRank, error) {
+			return m.sorted(), m.err
+		},
+		set: func(id, rank string) error {
+			for i := range m.rows {
+				if m.rows[i].ID == id {
+					m.rows[i].Rank = rank
+				}
+			}
+			return m.err
+		},
+	}
+}
+
+func (m *memoryRanks) sorted() []dto.KanbanRank {
+	rows := append([]dto.KanbanRank(nil), m.rows...)
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Rank < rows[j].Rank })
+	return rows
+}
+
+func (m *memoryRanks) ids() []string {
+	var ids []string
+	for _, r := range m.sorted() {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func ptr(s string) *string { return &s }
+
+func TestPlaceRank(t *testing.T) {
+	rows := []dto.KanbanRank{{ID: "a", Rank: "c"}, {ID: "b", Rank: "i"}, {ID: "c", Rank: "o"}}
+	tests := []struct {
+		name          string
+		rows          []dto.KanbanRank
+		after, before *string
+		lower, upper  string
+		status        int
+	}{
+		{name: "empty list", upper: ""},
+		{name: "last by default", rows: rows, lower: "o"},
+		{name: "after a row", rows: rows, after: ptr("c"), lower: "c", upper: "i"},
+		{name: "after the last row", rows: rows, after: ptr("o"), lower: "o"},
+		{name: "before a row", rows: rows, before: ptr("i"), lower: "c", upper: "i"},
+		{name: "before the first row", rows: rows, before: ptr("c"), upper: "c"},
+		{name: "between two rows", rows: rows, after: ptr("c"), before: ptr("i"), lower: "c", upper: "i"},
+		{name: "neighbours reversed", rows: rows, after: ptr("i"), before: ptr("c"), status: 400},
+		{name: "same neighbour twice", rows: rows, after: ptr("i"), before: ptr("i"), status: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &memoryRanks{rows: tt.rows}
+			rank, err := placeRank(list.scope(), tt.after, tt.before)
+			if tt.status != 0 {
+				var apiErr utils.APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != tt.status {
+					t.Fatalf("placeRank() error = %v, want status %d", err, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("placeRank() failed: %v", err)
+			}
+			if rank <= tt.lower || (tt.upper != "" && rank >= tt.upper) {
+				t.Errorf("placeRank() = %q, want between %q and %q", rank, tt.lower, tt.upper)
+			}
+		})
+	}
+}
+
+func TestPlaceRankScopeError(t *testing.T) {
+	list := &memoryRanks{err: errors.New("connection lost")}
+	_, err := placeRank(list.scope(), nil, nil)
+	var apiErr utils.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 500 {
+		t.Fatalf("placeRank() error = %v, want status 500", err)
+	}
+}
+
+// Inserting after the same row over and over grows the new ranks until they
+// pass maxRankLength, a rebalance then shortens every rank and keeps the order
+func TestPlaceRankRebalance(t *testing.T) {
+	list := &memoryRanks{rows: []dto.KanbanRank{{ID: "first", Rank: "a"}, {ID: "last", Rank: "b"}}}
+	want := []string{"first", "last"}
+
+	inserted := 0
+	for {
+		rank, err := placeRank(list.scope(), ptr("a"), nil)
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", inserted, err)
+		}
+		id := fmt.Sprintf("row%d", inserted)
+		list.rows = append(list.rows, dto.KanbanRank{ID: id, Rank: rank})
+		// Every row lands directly after "first", before the earlier ones
+		want = append([]string{want[0], id}, want[1:]...)
+		inserted++
+		if len(rank) > maxRankLength {
+			break
+		}
+		if inserted > 1000 {
+			t.Fatal("ranks never grew past maxRankLength")
+		}
+	}
+
+	ranks, err := rebalance(list.scope())
+	if err != nil {
+		t.Fatalf("rebalance() failed: %v", err)
+	}
+	if len(ranks) != len(want) {
+		t.Fatalf("rebalance() returned %d ranks, want %d", len(ranks), len(want))
+	}
+	for _, r := range list.rows {
+		if len(r.Rank) > 2 {
+			t.Errorf("rank of %s is %q after rebalance, want at most 2 digits", r.ID, r.Rank)
+		}
+		if got := rankOf(ranks, r.ID); got != r.Rank {
+			t.Errorf("rankOf(%s) = %q, stored %q", r.ID, got, r.Rank)
+		}
+	}
+	got := list.ids()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order after rebalance = %v, want %v", got, want)
+		}
+	}
+}
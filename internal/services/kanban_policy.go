@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// checkWipLimit is the hook run before an item moves into a category with a
+// WIP limit. The category must be locked so the count can't change under it.
+func checkWipLimit(ctx context.Context, q repository.Querier, kanban repository.Kanban, category repository.KanbanCategory, itemID string) (*utils.APIError, error) {
+	if !category.WipLimit.Valid {
+		return nil, nil
+	}
+
+	count, err := q.CountKanbanCategoryItems(ctx, repository.CountKanbanCategoryItemsParams{
+		KanbanCategoryID: category.ID,
+		ExcludeID:        itemID,
+	})
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to check WIP limit", err)
+	}
+	if count < int64(category.WipLimit.Int32) {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("category %s is at its WIP limit of %d", category.Name.String, category.WipLimit.Int32)
+	return applyMovePolicy(kanban.WipPolicy, message)
+}
+
+// applyMovePolicy turns a broken board rule into an error when the kanban
+// rejects such moves, or into a warning when it only warns
+func applyMovePolicy(policy, message string) (*utils.APIError, error) {
+	if policy == string(dto.MovePolicyReject) {
+		return nil, utils.NewError(http.StatusConflict, message, nil)
+	}
+	warning := utils.NewWarning(http.StatusConflict, message)
+	return &warning, nil
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Recurrences generated per sweep, the rest wait for the next one
+const recurrenceBatch = 100
+
+// -------------------------------------------------------------
+// Recurrences
+// -------------------------------------------------------------
+
+func (s *KanbanService) Recurrences(ctx context.Context, projectID, kanbanID string) ([]dto.KanbanRecurrence, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repos.Kanban.ListRecurrences(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list recurrences")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list recurrences", err)
+	}
+
+	recurrences := make([]dto.KanbanRecurrence, 0, len(rows))
+	for _, row := range rows {
+		recurrences = append(recurrences, dto.NewKanbanRecurrence(row))
+	}
+	return recurrences, nil
+}
+
+func (s *KanbanService) CreateRecurrence(ctx context.Context, projectID, kanbanID, userID string, params dto.CreateKanbanRecurrenceInput) (*dto.KanbanRecurrence, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	rule, err := parseRecurrence(params.RRule, params.StartsAt, params.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	priority := params.Priority
+	if priority == "" {
+		priority = dto.PriorityNone
+	}
+	enabled := true
+	if params.Enabled != nil {
+		enabled = *params.Enabled
+	}
+	next := nextRecurrence(rule, params.StartsAt, params.Until, time.Now())
+
+	var recurrence repository.KanbanRecurrence
+	err = s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveCategory(ctx, q, projectID, kanbanID, params.CategoryID); err != nil {
+			return notFoundAsField(err, "categoryId", "must be a category of the kanban")
+		}
+
+		recurrence, err = q.CreateKanbanRecurrence(ctx, repository.CreateKanbanRecurrenceParams{
+			ID:            gonanoid.Must(),
+			KanbanID:      kanbanID,
+			CategoryID:    params.CategoryID,
+			Title:         params.Title,
+			Description:   utils.PtrToPgText(params.Description),
+			Priority:      string(priority),
+			EstimatedTime: utils.PtrToPgInt4(params.EstimatedTime),
+			Rrule:         rule.String(),
+			StartsAt:      utils.PtrToPgTimestamptz(&params.StartsAt),
+			Until:         utils.PtrToPgTimestamptz(params.Until),
+			NextRunAt:     utils.PtrToPgTimestamptz(next),
+			Enabled:       enabled,
+			CreatedBy:     utils.PtrToPgText(&userID),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create recurrence")
+			return utils.NewError(http.StatusInternalServerError, "failed to create recurrence", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("recurrence_id", recurrence.ID).Info("recurrence created")
+	result := dto.NewKanbanRecurrence(recurrence)
+	return &result, nil
+}
+
+func (s *KanbanService) UpdateRecurrence(ctx context.Context, projectID, kanbanID, recurrenceID string, params dto.UpdateKanbanRecurrenceInput) (*dto.KanbanRecurrence, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"recurrence_id": recurrenceID,
+	})
+
+	if params.EstimatedTime.Value != nil && *params.EstimatedTime.Value < 0 {
+		return nil, utils.NewFieldError("estimatedTime", "must be at least 0")
+	}
+
+	var recurrence repository.KanbanRecurrence
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		current, err := getRecurrence(ctx, q, kanbanID, recurrenceID)
+		if err != nil {
+			return err
+		}
+		if params.CategoryID != nil {
+			if _, err := getActiveCategory(ctx, q, projectID, kanbanID, *params.CategoryID); err != nil {
+				return notFoundAsField(err, "categoryId", "must be a category of the kanban")
+			}
+		}
+
+		// Reschedule from the schedule as it will be after the update
+		rrule := current.Rrule
+		if params.RRule != nil {
+			rrule = *params.RRule
+		}
+		startsAt := current.StartsAt.Time
+		if params.StartsAt != nil {
+			startsAt = *params.StartsAt
+		}
+		until := utils.PgTimestamptzToPtr(current.Until)
+		if params.Until.Set {
+			until = params.Until.Value
+		}
+		rule, err := parseRecurrence(rrule, startsAt, until)
+		if err != nil {
+			return err
+		}
+		normalized := rule.String()
+		next := nextRecurrence(rule, startsAt, until, time.Now())
+
+		var priority *string
+		if params.Priority != nil {
+			p := string(*params.Priority)
+			priority = &p
+		}
+
+		recurrence, err = q.UpdateKanbanRecurrence(ctx, repository.UpdateKanbanRecurrenceParams{
+			ID:               recurrenceID,
+			KanbanID:         kanbanID,
+			CategoryID:       utils.PtrToPgText(params.CategoryID),
+			Title:            utils.PtrToPgText(params.Title),
+			SetDescription:   params.Description.Set,
+			Description:      utils.PtrToPgText(params.Description.Value),
+			Priority:         utils.PtrToPgText(priority),
+			SetEstimatedTime: params.EstimatedTime.Set,
+			EstimatedTime:    utils.PtrToPgInt4(params.EstimatedTime.Value),
+			Rrule:            utils.PtrToPgText(&normalized),
+			StartsAt:         utils.PtrToPgTimestamptz(&startsAt),
+			SetUntil:         params.Until.Set,
+			Until:            utils.PtrToPgTimestamptz(params.Until.Value),
+			NextRunAt:        utils.PtrToPgTimestamptz(next),
+			Enabled:          utils.PtrToPgBool(params.Enabled),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to update recurrence")
+			return utils.NewError(http.StatusInternalServerError, "failed to update recurrence", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("recurrence updated")
+	result := dto.NewKanbanRecurrence(recurrence)
+	return &result, nil
+}
+
+// DeleteRecurrence stops a schedule, cards it already created are kept
+func (s *KanbanService) DeleteRecurrence(ctx context.Context, projectID, kanbanID, recurrenceID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id":    projectID,
+		"kanban_id":     kanbanID,
+		"recurrence_id": recurrenceID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return err
+	}
+
+	rows, err := s.repos.Kanban.DeleteRecurrence(ctx, recurrenceID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete recurrence")
+		return utils.NewError(http.StatusInternalServerError, "failed to delete recurrence", err)
+	}
+	if rows == 0 {
+		return utils.NewError(http.StatusNotFound, "recurrence not found", nil)
+	}
+
+	logger.Info("recurrence deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Generation
+// -------------------------------------------------------------
+
+// RunRecurrences creates the cards of every due recurrence
+func (s *KanbanService) RunRecurrences(ctx context.Context) error {
+	ids, err := s.repos.Kanban.ListDueRecurrenceIDs(ctx, recurrenceBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.generateRecurrence(ctx, id); err != nil {
+			logging.WithLayer(ctx, "service", "kanban").WithError(err).WithField("recurrence_id", id).Warn("failed to generate recurring card")
+		}
+	}
+	return nil
+}
+
+// generateRecurrence creates the card of the due occurrence of a recurrence
+// and schedules the next one. The recurrence is locked and every occurrence
+// is claimed once, so instances racing for it create a single card. After
+// downtime only the latest missed occurrence gets a card.
+func (s *KanbanService) generateRecurrence(ctx context.Context, recurrenceID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("recurrence_id", recurrenceID)
+
+	var (
+		item      *repository.KanbanItem
+		projectID string
+		kanbanID  string
+	)
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		r, err := q.LockDueKanbanRecurrence(ctx, recurrenceID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Taken by another instance or no longer due
+				return nil
+			}
+			return err
+		}
+		projectID, kanbanID = r.ProjectID, r.KanbanID
+
+		rule, err := utils.ParseRRule(r.Rrule)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		until := utils.PgTimestamptzToPtr(r.Until)
+
+		// Skip to the latest occurrence that is due
+		scheduled := r.NextRunAt.Time
+		for {
+			following := nextRecurrence(rule, r.StartsAt.Time, until, scheduled)
+			if following == nil || following.After(now) {
+				break
+			}
+			scheduled = *following
+		}
+
+		claimed, err := q.ClaimKanbanRecurrenceRun(ctx, repository.ClaimKanbanRecurrenceRunParams{
+			RecurrenceID: r.ID,
+			ScheduledAt:  utils.PtrToPgTimestamptz(&scheduled),
+		})
+		if err != nil {
+			return err
+		}
+
+		if claimed > 0 {
+			if _, err := getActiveCategory(ctx, q, r.ProjectID, r.KanbanID, r.CategoryID); err != nil {
+				logger.WithError(err).Warn("category of recurrence is gone, skipping card")
+			} else {
+				created, err := createItem(ctx, q, r.ProjectID, r.KanbanID, r.CategoryID, repository.CreateKanbanItemParams{
+					Title:         r.Title,
+					Description:   r.Description,
+					Priority:      r.Priority,
+					EstimatedTime: r.EstimatedTime,
+				})
+				if err != nil {
+					return err
+				}
+				item = &created
+
+				if err := q.SetKanbanRecurrenceRunItem(ctx, repository.SetKanbanRecurrenceRunItemParams{
+					RecurrenceID: r.ID,
+					ScheduledAt:  utils.PtrToPgTimestamptz(&scheduled),
+					ItemID:       utils.PtrToPgText(&created.ID),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return q.SetKanbanRecurrenceNextRun(ctx, repository.SetKanbanRecurrenceNextRunParams{
+			ID:        r.ID,
+			NextRunAt: utils.PtrToPgTimestamptz(nextRecurrence(rule, r.StartsAt.Time, until, scheduled)),
+		})
+	})
+	if err != nil || item == nil {
+		return err
+	}
+
+	logger.WithField("item_id", item.ID).Info("recurring card created")
+	s.publish(ctx, utils.NewKanbanItem, kanbanID, dto.KanbanItemEvent{
+		Item: *item,
+	})
+	s.runRules(ctx, ruleEvent{
+		Event:      dto.RuleItemCreated,
+		ProjectID:  projectID,
+		KanbanID:   kanbanID,
+		ItemID:     item.ID,
+		CategoryID: item.KanbanCategoryID,
+	})
+	return nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// parseRecurrence validates a schedule, errors name the offending field
+func parseRecurrence(rrule string, startsAt time.Time, until *time.Time) (utils.Recurrence, error) {
+	rule, err := utils.ParseRRule(rrule)
+	if err != nil {
+		return rule, utils.NewFieldError("rrule", err.Error())
+	}
+	if until != nil && !until.After(startsAt) {
+		return rule, utils.NewFieldError("until", "must be after startsAt")
+	}
+	if rule.Next(startsAt, startsAt.Add(-time.Nanosecond)).IsZero() {
+		return rule, utils.NewFieldError("rrule", "never occurs")
+	}
+	return rule, nil
+}
+
+// nextRecurrence returns the first occurrence after after, nil once the
+// schedule has ended
+func nextRecurrence(rule utils.Recurrence, startsAt time.Time, until *time.Time, after time.Time) *time.Time {
+	next := rule.Next(startsAt, after)
+	if next.IsZero() || (until != nil && next.After(*until)) {
+		return nil
+	}
+	return &next
+}
+
+func getRecurrence(ctx context.Context, q repository.Querier, kanbanID, recurrenceID string) (repository.KanbanRecurrence, error) {
+	recurrence, err := q.GetKanbanRecurrence(ctx, repository.GetKanbanRecurrenceParams{
+		ID:       recurrenceID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return recurrence, utils.NewError(http.StatusNotFound, "recurrence not found", err)
+		}
+		return recurrence, utils.NewError(http.StatusInternalServerError, "failed to fetch recurrence", err)
+	}
+	return recurrence, nil
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/mail"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Advisory lock keys of the reminder jobs, only one instance of the API
+	// runs each job at a time
+	dueReminderLock int64 = 0x646464_0001
+	dueDigestLock   int64 = 0x646464_0002
+
+	// Cards reminded about per sweep, the rest wait for the next one
+	dueReminderBatch = 200
+	// Users sent their digest per sweep
+	dueDigestBatch = 100
+	// Most cards listed in one digest
+	dueDigestItems = 50
+	// Cards further past their due date than this get no reminder, the
+	// overdue digest covers them
+	dueReminderGrace = time.Hour
+)
+
+// ReminderOptions configure the due date reminders and the overdue digest
+type ReminderOptions struct {
+	// Offsets before the due date assignees and watchers are reminded at,
+	// zero reminds them when the card is due
+	Offsets []time.Duration
+	// DigestHour is the hour of the day (UTC) the overdue digest is sent from
+	DigestHour int
+	// AppURL is linked to from emails
+	AppURL string
+}
+
+// dueReminders is the reminder setup of a kanban service
+type dueReminders struct {
+	offsets    []int32
+	digestHour int
+	appURL     string
+	mailer     mail.Mailer
+}
+
+// SetReminders turns on due date reminders and the overdue digest. Without a
+// mailer they are only delivered as notifications in the app.
+func (s *KanbanService) SetReminders(opts ReminderOptions, mailer mail.Mailer) error {
+	if opts.DigestHour < 0 || opts.DigestHour > 23 {
+		return fmt.Errorf("digest hour must be between 0 and 23, got %d", opts.DigestHour)
+	}
+
+	seen := map[int32]bool{}
+	offsets := make([]int32, 0, len(opts.Offsets))
+	for _, offset := range opts.Offsets {
+		if offset < 0 || offset%time.Second != 0 || offset > 365*24*time.Hour {
+			return fmt.Errorf("reminder offset %s must be whole seconds between 0s and a year", offset)
+		}
+		seconds := int32(offset / time.Second)
+		if !seen[seconds] {
+			seen[seconds] = true
+			offsets = append(offsets, seconds)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	s.reminders = &dueReminders{
+		offsets:    offsets,
+		digestHour: opts.DigestHour,
+		appURL:     strings.TrimSuffix(opts.AppURL, "/"),
+		mailer:     mailer,
+	}
+	return nil
+}
+
+// RunReminders reminds the assignees and watchers of cards coming due. Each
+// reminder is claimed per card, due date and offset, so it is sent once even
+// when the due date is moved back and forth, and the sweep holds an advisory
+// lock so several instances of the API don't sweep at the same time.
+// Emails go out after the notifications are committed.
+func (s *KanbanService) RunReminders(ctx context.Context) error {
+	if s.reminders == nil || len(s.reminders.offsets) == 0 {
+		return nil
+	}
+	logger := logging.WithLayer(ctx, "service", "kanban")
+
+	var emails []mail.Message
+	sent := 0
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// Another instance is sweeping when the lock is taken
+		locked, err := q.TryKanbanReminderLock(ctx, dueReminderLock)
+		if err != nil || !locked {
+			return err
+		}
+
+		due, err := q.ListDueKanbanReminders(ctx, repository.ListDueKanbanRemindersParams{
+			Offsets:      s.reminders.offsets,
+			GraceSeconds: int32(dueReminderGrace / time.Second),
+			Limit:        dueReminderBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, d := range due {
+			claimed, err := q.ClaimKanbanDueReminder(ctx, repository.ClaimKanbanDueReminderParams{
+				ItemID:        d.ItemID,
+				DueDate:       d.DueDate,
+				OffsetSeconds: d.OffsetSeconds,
+			})
+			if err != nil {
+				return err
+			}
+			if claimed == 0 {
+				continue
+			}
+
+			recipients, err := q.ListKanbanItemReminderRecipients(ctx, d.ItemID)
+			if err != nil {
+				return err
+			}
+			userIDs := make([]string, 0, len(recipients))
+			for _, r := range recipients {
+				userIDs = append(userIDs, r.UserID)
+				emails = append(emails, s.reminderEmail(r.Email, d))
+			}
+			if err := notify(ctx, q, d.OrganisationID, "", dto.NotificationDueReminder, dto.KanbanDueNotification{
+				ProjectID:     d.ProjectID,
+				KanbanID:      d.KanbanID,
+				ItemID:        d.ItemID,
+				Title:         d.Title,
+				DueDate:       d.DueDate.Time,
+				OffsetSeconds: d.OffsetSeconds,
+			}, userIDs); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		logger.WithField("count", sent).Info("due date reminders sent")
+	}
+	s.sendEmails(ctx, emails)
+	return nil
+}
+
+// RunDigest sends every user with overdue cards they are assigned to or
+// watch a daily digest of them, from the digest hour on. Digests are claimed
+// per user and day under an advisory lock like the reminders.
+func (s *KanbanService) RunDigest(ctx context.Context) error {
+	if s.reminders == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	if now.Hour() < s.reminders.digestHour {
+		return nil
+	}
+	day := pgtype.Date{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+	logger := logging.WithLayer(ctx, "service", "kanban")
+
+	var emails []mail.Message
+	sent := 0
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// Another instance is sweeping when the lock is taken
+		locked, err := q.TryKanbanReminderLock(ctx, dueDigestLock)
+		if err != nil || !locked {
+			return err
+		}
+
+		users, err := q.ListKanbanDigestUsers(ctx, repository.ListKanbanDigestUsersParams{
+			Day:   day,
+			Limit: dueDigestBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			claimed, err := q.ClaimKanbanDueDigest(ctx, repository.ClaimKanbanDueDigestParams{
+				UserID: u.UserID,
+				Day:    day,
+			})
+			if err != nil {
+				return err
+			}
+			if claimed == 0 {
+				continue
+			}
+
+			rows, err := q.ListUserOverdueKanbanItems(ctx, repository.ListUserOverdueKanbanItemsParams{
+				UserID: u.UserID,
+				Limit:  dueDigestItems,
+			})
+			if err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				continue
+			}
+
+			// Notifications belong to an organisation, the email covers all
+			var orgs []string
+			byOrg := map[string][]dto.KanbanOverdueItem{}
+			for _, row := range rows {
+				if _, ok := byOrg[row.OrganisationID]; !ok {
+					orgs = append(orgs, row.OrganisationID)
+				}
+				byOrg[row.OrganisationID] = append(byOrg[row.OrganisationID], dto.KanbanOverdueItem{
+					ProjectID:  row.ProjectID,
+					KanbanID:   row.KanbanID,
+					KanbanName: row.KanbanName,
+					ItemID:     row.ItemID,
+					Title:      row.Title,
+					DueDate:    row.DueDate.Time,
+				})
+			}
+			for _, orgID := range orgs {
+				if err := notify(ctx, q, orgID, "", dto.NotificationOverdueDigest, dto.KanbanOverdueDigestNotification{
+					Items: byOrg[orgID],
+				}, []string{u.UserID}); err != nil {
+					return err
+				}
+			}
+			emails = append(emails, s.digestEmail(u.Email, u.Username, rows, now))
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		logger.WithField("count", sent).Info("overdue digests sent")
+	}
+	s.sendEmails(ctx, emails)
+	return nil
+}
+
+// sendEmails delivers committed reminders, failures are logged and not
+// retried so nobody is reminded twice
+func (s *KanbanService) sendEmails(ctx context.Context, emails []mail.Message) {
+	if s.reminders.mailer == nil {
+		return
+	}
+	for _, msg := range emails {
+		if err := s.reminders.mailer.Send(ctx, msg); err != nil {
+			logging.WithLayer(ctx, "service", "kanban").WithError(err).WithFields(logrus.Fields{
+				"to":      msg.To,
+				"subject": msg.Subject,
+			}).Warn("failed to send email")
+		}
+	}
+}
+
+func (s *KanbanService) reminderEmail(to string, d repository.ListDueKanbanRemindersRow) mail.Message {
+	// Cards created close to their due date skip the earlier offsets, so
+	// the time left is named rather than the offset
+	when := dueIn(time.Until(d.DueDate.Time))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "The card %q is due %s.\n\n", d.Title, when)
+	fmt.Fprintf(&body, "Due: %s\n", d.DueDate.Time.UTC().Format("Mon 2 Jan 2006 15:04 MST"))
+	if s.reminders.appURL != "" {
+		fmt.Fprintf(&body, "\nOpen DidlyDooDash: %s\n", s.reminders.appURL)
+	}
+	body.WriteString("\nYou get this email because you are assigned to or watch the card.\n")
+
+	return mail.Message{
+		To:      to,
+		Subject: fmt.Sprintf("%q is due %s", d.Title, when),
+		Body:    body.String(),
+	}
+}
+
+func (s *KanbanService) digestEmail(to, username string, rows []repository.ListUserOverdueKanbanItemsRow, now time.Time) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\nThese cards are past their due date:\n\n", username)
+	for _, row := range rows {
+		fmt.Fprintf(&body, "- %s (%s), due %s, %s overdue\n",
+			row.Title, row.KanbanName,
+			row.DueDate.Time.UTC().Format("2 Jan 2006 15:04 MST"),
+			overdueFor(now.Sub(row.DueDate.Time)),
+		)
+	}
+	if len(rows) == dueDigestItems {
+		fmt.Fprintf(&body, "\nOnly the %d longest overdue cards are listed.\n", dueDigestItems)
+	}
+	if s.reminders.appURL != "" {
+		fmt.Fprintf(&body, "\nOpen DidlyDooDash: %s\n", s.reminders.appURL)
+	}
+	body.WriteString("\nYou get this email because you are assigned to or watch these cards.\n")
+
+	subject := "1 overdue card"
+	if len(rows) != 1 {
+		subject = fmt.Sprintf("%d overdue cards", len(rows))
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Daily digest: " + subject,
+		Body:    body.String(),
+	}
+}
+
+// dueIn describes the time left until a due date, like "in 24 hours" or "now"
+func dueIn(left time.Duration) string {
+	if left < time.Minute {
+		return "now"
+	}
+	return "in " + durationText(left)
+}
+
+// overdueFor describes how long a card is overdue, at least a minute
+func overdueFor(d time.Duration) string {
+	if d < time.Minute {
+		d = time.Minute
+	}
+	return durationText(d)
+}
+
+// durationText names a duration rounded to its largest unit
+func durationText(d time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case d >= 48*time.Hour:
+		return plural(int64(d.Round(24*time.Hour)/(24*time.Hour)), "day")
+	case d >= time.Hour:
+		return plural(int64(d.Round(time.Hour)/time.Hour), "hour")
+	case d >= time.Minute:
+		return plural(int64(d.Round(time.Minute)/time.Minute), "minute")
+	}
+	return plural(int64(d.Round(time.Second)/time.Second), "second")
+}
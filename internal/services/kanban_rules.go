@@ -0,0 +1,560 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Rules fired by the changes of other rules stop this many levels deep
+	maxRuleDepth = 5
+	// Overdue cards handled per sweep, the rest wait for the next one
+	overdueRuleBatch = 100
+)
+
+type ruleCtxKey string
+
+const (
+	ruleIDKey    ruleCtxKey = "rule_id"
+	ruleChainKey ruleCtxKey = "rule_chain"
+)
+
+// ruleEvent is a committed change a rule can fire on
+type ruleEvent struct {
+	Event      dto.KanbanRuleEvent
+	ProjectID  string
+	KanbanID   string
+	ItemID     string
+	CategoryID string
+}
+
+// ruleChain follows the rules fired because of one change. A rule fires at
+// most once per chain, so rules reacting to each other can't loop.
+type ruleChain struct {
+	depth int
+	fired map[string]bool
+}
+
+// -------------------------------------------------------------
+// Rules
+// -------------------------------------------------------------
+
+func (s *KanbanService) Rules(ctx context.Context, projectID, kanbanID string) ([]dto.KanbanRule, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repos.Kanban.ListRules(ctx, kanbanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to list rules")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list rules", err)
+	}
+
+	rules := make([]dto.KanbanRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, dto.NewKanbanRule(row))
+	}
+	return rules, nil
+}
+
+func (s *KanbanService) CreateRule(ctx context.Context, projectID, kanbanID, userID string, params dto.CreateKanbanRuleInput) (*dto.KanbanRule, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	enabled := true
+	if params.Enabled != nil {
+		enabled = *params.Enabled
+	}
+
+	var rule repository.KanbanRule
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		if err := validateRule(ctx, q, projectID, kanbanID, params.CategoryID, params.Actions); err != nil {
+			return err
+		}
+
+		actions, err := json.Marshal(params.Actions)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create rule", err)
+		}
+		rule, err = q.CreateKanbanRule(ctx, repository.CreateKanbanRuleParams{
+			ID:         gonanoid.Must(),
+			KanbanID:   kanbanID,
+			Name:       params.Name,
+			Enabled:    enabled,
+			Event:      string(params.Event),
+			CategoryID: utils.PtrToPgText(params.CategoryID),
+			Actions:    actions,
+			CreatedBy:  utils.PtrToPgText(&userID),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to create rule")
+			return utils.NewError(http.StatusInternalServerError, "failed to create rule", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityRule, rule.ID, dto.ActivityCreate, nil, dto.NewKanbanRule(rule))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("rule_id", rule.ID).Info("rule created")
+	result := dto.NewKanbanRule(rule)
+	return &result, nil
+}
+
+func (s *KanbanService) UpdateRule(ctx context.Context, projectID, kanbanID, ruleID string, params dto.UpdateKanbanRuleInput) (*dto.KanbanRule, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"rule_id":    ruleID,
+	})
+
+	var rule repository.KanbanRule
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		current, err := getRule(ctx, q, kanbanID, ruleID)
+		if err != nil {
+			return err
+		}
+
+		// Validate the rule as it will be after the update
+		before := dto.NewKanbanRule(current)
+		categoryID := before.CategoryID
+		if params.CategoryID.Set {
+			categoryID = params.CategoryID.Value
+		}
+		actions := before.Actions
+		if params.Actions != nil {
+			actions = *params.Actions
+		}
+		if err := validateRule(ctx, q, projectID, kanbanID, categoryID, actions); err != nil {
+			return err
+		}
+
+		var raw []byte
+		if params.Actions != nil {
+			if raw, err = json.Marshal(*params.Actions); err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to update rule", err)
+			}
+		}
+		var event *string
+		if params.Event != nil {
+			e := string(*params.Event)
+			event = &e
+		}
+
+		rule, err = q.UpdateKanbanRule(ctx, repository.UpdateKanbanRuleParams{
+			ID:            ruleID,
+			KanbanID:      kanbanID,
+			Name:          utils.PtrToPgText(params.Name),
+			Enabled:       utils.PtrToPgBool(params.Enabled),
+			Event:         utils.PtrToPgText(event),
+			SetCategoryID: params.CategoryID.Set,
+			CategoryID:    utils.PtrToPgText(params.CategoryID.Value),
+			Actions:       raw,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to update rule")
+			return utils.NewError(http.StatusInternalServerError, "failed to update rule", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityRule, ruleID, dto.ActivityUpdate, before, dto.NewKanbanRule(rule))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("rule updated")
+	result := dto.NewKanbanRule(rule)
+	return &result, nil
+}
+
+func (s *KanbanService) DeleteRule(ctx context.Context, projectID, kanbanID, ruleID string) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"rule_id":    ruleID,
+	})
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getKanbanInProject(ctx, q, projectID, kanbanID); err != nil {
+			return err
+		}
+		rule, err := getRule(ctx, q, kanbanID, ruleID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.DeleteKanbanRule(ctx, repository.DeleteKanbanRuleParams{
+			ID:       ruleID,
+			KanbanID: kanbanID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to delete rule")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete rule", err)
+		}
+		return recordActivity(ctx, q, kanbanID, dto.KanbanEntityRule, ruleID, dto.ActivityDelete, dto.NewKanbanRule(rule), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("rule deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Evaluation
+// -------------------------------------------------------------
+
+// RunScheduler runs the time based kanban jobs every interval until ctx is
+// done: recurring cards, overdue rules, the archive purge and due date
+// reminders
+func (s *KanbanService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunRecurrences(ctx); err != nil {
+				s.logger.WithError(err).Warn("failed to run recurrences")
+			}
+			if err := s.RunOverdueRules(ctx); err != nil {
+				s.logger.WithError(err).Warn("failed to run overdue rules")
+			}
+			if err := s.PurgeArchives(ctx); err != nil {
+				s.logger.WithError(err).Warn("failed to purge archives")
+			}
+			if err := s.RunReminders(ctx); err != nil {
+				s.logger.WithError(err).Warn("failed to send due date reminders")
+			}
+			if err := s.RunDigest(ctx); err != nil {
+				s.logger.WithError(err).Warn("failed to send overdue digests")
+			}
+		}
+	}
+}
+
+// RunOverdueRules fires the overdue rules for cards whose due date passed.
+// Every rule is claimed per card before it runs, so several instances of the
+// API can sweep at the same time.
+func (s *KanbanService) RunOverdueRules(ctx context.Context) error {
+	logger := logging.WithLayer(ctx, "service", "kanban")
+
+	matches, err := s.repos.Kanban.ListOverdueRuleItems(ctx, overdueRuleBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		claimed, err := s.repos.Kanban.ClaimRuleRun(ctx, repository.ClaimKanbanRuleRunParams{
+			RuleID:  m.RuleID,
+			ItemID:  m.ItemID,
+			DueDate: m.DueDate,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("rule_id", m.RuleID).Warn("failed to claim overdue rule")
+			continue
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		rule, err := s.repos.Kanban.GetRule(ctx, m.RuleID, m.KanbanID)
+		if err != nil {
+			logger.WithError(err).WithField("rule_id", m.RuleID).Warn("failed to fetch overdue rule")
+			continue
+		}
+		s.applyRule(withRuleChain(ctx, &ruleChain{fired: map[string]bool{}}), rule, ruleEvent{
+			Event:      dto.RuleItemOverdue,
+			ProjectID:  m.ProjectID,
+			KanbanID:   m.KanbanID,
+			ItemID:     m.ItemID,
+			CategoryID: m.CategoryID,
+		})
+	}
+	return nil
+}
+
+// runRules fires the enabled rules of a kanban matching a committed change.
+// Failing rules are logged, the change itself already went through.
+func (s *KanbanService) runRules(ctx context.Context, event ruleEvent) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"kanban_id": event.KanbanID,
+		"item_id":   event.ItemID,
+		"event":     event.Event,
+	})
+
+	chain := ruleChainFromContext(ctx)
+	if chain.depth >= maxRuleDepth {
+		logger.Warn("rule chain too deep, not firing further rules")
+		return
+	}
+
+	rules, err := s.repos.Kanban.ListEnabledRules(ctx, event.KanbanID, string(event.Event))
+	if err != nil {
+		logger.WithError(err).Error("failed to list rules")
+		return
+	}
+
+	// Rules keep running after the request that caused them is done
+	ctx = withRuleChain(context.WithoutCancel(ctx), chain)
+	for _, rule := range rules {
+		if rule.CategoryID.Valid && rule.CategoryID.String != event.CategoryID {
+			continue
+		}
+		if chain.fired[rule.ID] {
+			logger.WithField("rule_id", rule.ID).Warn("rule already fired in this chain, skipping")
+			continue
+		}
+		s.applyRule(ctx, rule, event)
+	}
+}
+
+// applyRule runs the actions of a rule on the card of an event and logs the
+// run. Actions go through the regular service methods acting as the rule.
+func (s *KanbanService) applyRule(ctx context.Context, rule repository.KanbanRule, event ruleEvent) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"kanban_id": event.KanbanID,
+		"item_id":   event.ItemID,
+		"rule_id":   rule.ID,
+	})
+
+	chain := ruleChainFromContext(ctx)
+	chain.fired[rule.ID] = true
+	ctx = withRule(ctx, rule.ID, &ruleChain{depth: chain.depth + 1, fired: chain.fired})
+
+	var actions []dto.KanbanRuleAction
+	if err := json.Unmarshal(rule.Actions, &actions); err != nil {
+		logger.WithError(err).Error("unreadable rule actions")
+		return
+	}
+
+	run := dto.KanbanRuleRun{Event: event.Event, Applied: []dto.KanbanRuleActionType{}}
+	for _, action := range actions {
+		if err := s.applyRuleAction(ctx, rule, event, action); err != nil {
+			logger.WithError(err).WithField("action", action.Type).Warn("rule action failed")
+			run.Failed = append(run.Failed, dto.KanbanRuleActionError{
+				Type:    action.Type,
+				Message: err.Error(),
+			})
+			continue
+		}
+		run.Applied = append(run.Applied, action.Type)
+	}
+
+	if err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		return recordRuleRun(ctx, q, rule, event, run)
+	}); err != nil {
+		logger.WithError(err).Error("failed to log rule run")
+	}
+	logger.Infof("rule fired, %d of %d actions applied", len(run.Applied), len(actions))
+}
+
+func (s *KanbanService) applyRuleAction(ctx context.Context, rule repository.KanbanRule, event ruleEvent, action dto.KanbanRuleAction) error {
+	switch action.Type {
+	case dto.RuleSetPriority:
+		_, err := s.UpdateItem(ctx, event.ProjectID, event.KanbanID, event.ItemID, dto.UpdateKanbanItemInput{
+			Priority: action.Priority,
+		}, nil)
+		return err
+	case dto.RuleAssign:
+		_, err := s.AddAssignee(ctx, event.ProjectID, event.KanbanID, event.ItemID, *action.UserID)
+		return err
+	case dto.RuleAddLabel:
+		_, err := s.AddItemLabel(ctx, event.ProjectID, event.KanbanID, event.ItemID, *action.LabelID)
+		return err
+	case dto.RuleMove:
+		_, err := s.MoveItem(ctx, event.ProjectID, event.KanbanID, event.ItemID, dto.MoveKanbanItemInput{
+			CategoryID: *action.CategoryID,
+		})
+		return err
+	case dto.RuleSetBoardStatus:
+		_, err := s.Update(ctx, event.ProjectID, event.KanbanID, dto.UpdateKanbanInput{
+			Status: action.Status,
+		}, nil)
+		return err
+	case dto.RuleNotifyWatchers:
+		return s.tx.WithTx(ctx, func(q repository.Querier) error {
+			project, err := q.GetProjectByID(ctx, event.ProjectID)
+			if err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to fetch project", err)
+			}
+			watchers, err := q.ListKanbanItemWatchers(ctx, event.ItemID)
+			if err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to list watchers", err)
+			}
+			return notify(ctx, q, project.OrganisationID, "", dto.NotificationRule, dto.KanbanRuleNotification{
+				ProjectID: event.ProjectID,
+				KanbanID:  event.KanbanID,
+				ItemID:    event.ItemID,
+				RuleID:    rule.ID,
+				RuleName:  rule.Name,
+				Message:   action.Message,
+			}, watcherIDs(watchers))
+		})
+	}
+	return fmt.Errorf("unknown rule action %q", action.Type)
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// validateRule checks that the targets of a rule exist on the kanban, errors
+// name the offending field
+func validateRule(ctx context.Context, q repository.Querier, projectID, kanbanID string, categoryID *string, actions []dto.KanbanRuleAction) error {
+	if categoryID != nil {
+		if _, err := getActiveCategory(ctx, q, projectID, kanbanID, *categoryID); err != nil {
+			return notFoundAsField(err, "categoryId", "must be a category of the kanban")
+		}
+	}
+
+	for i, action := range actions {
+		field := fmt.Sprintf("actions[%d]", i)
+		switch action.Type {
+		case dto.RuleSetPriority:
+			if action.Priority == nil {
+				return utils.NewFieldError(field+".priority", "is required")
+			}
+		case dto.RuleAssign:
+			if action.UserID == nil {
+				return utils.NewFieldError(field+".userId", "is required")
+			}
+			if err := requireProjectMember(ctx, q, projectID, *action.UserID); err != nil {
+				var apiErr utils.APIError
+				if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+					return utils.NewFieldError(field+".userId", "must be a member of the project")
+				}
+				return err
+			}
+		case dto.RuleAddLabel:
+			if action.LabelID == nil {
+				return utils.NewFieldError(field+".labelId", "is required")
+			}
+			if _, err := q.GetBoardLabel(ctx, repository.GetBoardLabelParams{
+				ID:       *action.LabelID,
+				KanbanID: kanbanID,
+			}); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return utils.NewFieldError(field+".labelId", "must be a label of the kanban")
+				}
+				return utils.NewError(http.StatusInternalServerError, "failed to fetch label", err)
+			}
+		case dto.RuleMove:
+			if action.CategoryID == nil {
+				return utils.NewFieldError(field+".categoryId", "is required")
+			}
+			if _, err := getActiveCategory(ctx, q, projectID, kanbanID, *action.CategoryID); err != nil {
+				return notFoundAsField(err, field+".categoryId", "must be a category of the kanban")
+			}
+		case dto.RuleSetBoardStatus:
+			if action.Status == nil {
+				return utils.NewFieldError(field+".status", "is required")
+			}
+		}
+	}
+	return nil
+}
+
+// notFoundAsField turns a missing row into an error on the field that named it
+func notFoundAsField(err error, field, message string) error {
+	var apiErr utils.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return utils.NewFieldError(field, message)
+	}
+	return err
+}
+
+// recordRuleRun logs a rule firing on a card with the actions it applied
+func recordRuleRun(ctx context.Context, q repository.Querier, rule repository.KanbanRule, event ruleEvent, run dto.KanbanRuleRun) error {
+	changes, err := diffFields(nil, run)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+
+	if _, err := q.CreateKanbanActivity(ctx, repository.CreateKanbanActivityParams{
+		ID:         gonanoid.Must(),
+		KanbanID:   event.KanbanID,
+		RuleID:     pgtype.Text{String: rule.ID, Valid: true},
+		EntityType: string(dto.KanbanEntityRule),
+		EntityID:   rule.ID,
+		ItemID:     pgtype.Text{String: event.ItemID, Valid: true},
+		Action:     string(dto.ActivityRun),
+		Changes:    data,
+	}); err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to record activity", err)
+	}
+	return nil
+}
+
+func getRule(ctx context.Context, q repository.Querier, kanbanID, ruleID string) (repository.KanbanRule, error) {
+	rule, err := q.GetKanbanRule(ctx, repository.GetKanbanRuleParams{
+		ID:       ruleID,
+		KanbanID: kanbanID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rule, utils.NewError(http.StatusNotFound, "rule not found", err)
+		}
+		return rule, utils.NewError(http.StatusInternalServerError, "failed to fetch rule", err)
+	}
+	return rule, nil
+}
+
+// withRule makes the changes made with ctx count as done by a rule instead
+// of the user whose change fired it
+func withRule(ctx context.Context, ruleID string, chain *ruleChain) context.Context {
+	ctx = utils.WithUserID(ctx, "")
+	ctx = context.WithValue(ctx, ruleIDKey, ruleID)
+	return withRuleChain(ctx, chain)
+}
+
+func withRuleChain(ctx context.Context, chain *ruleChain) context.Context {
+	return context.WithValue(ctx, ruleChainKey, chain)
+}
+
+// ruleChainFromContext returns the chain a change belongs to, changes made
+// by users start a new one
+func ruleChainFromContext(ctx context.Context) *ruleChain {
+	if chain, ok := ctx.Value(ruleChainKey).(*ruleChain); ok {
+		return chain
+	}
+	return &ruleChain{fired: map[string]bool{}}
+}
+
+func ruleIDFromContext(ctx context.Context) *string {
+	if id, ok := ctx.Value(ruleIDKey).(string); ok && id != "" {
+		return &id
+	}
+	return nil
+}
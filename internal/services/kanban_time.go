@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// KanbanTimeFilter narrows time entries down to a card, a user and a range
+// of start times, from is inclusive and to exclusive
+type KanbanTimeFilter struct {
+	ItemID *string
+	UserID *string
+	From   *time.Time
+	To     *time.Time
+}
+
+func (f KanbanTimeFilter) params(projectID string) repository.ListKanbanTimeEntriesParams {
+	return repository.ListKanbanTimeEntriesParams{
+		ProjectID: projectID,
+		ItemID:    utils.PtrToPgText(f.ItemID),
+		UserID:    utils.PtrToPgText(f.UserID),
+		From:      utils.PtrToPgTimestamptz(f.From),
+		To:        utils.PtrToPgTimestamptz(f.To),
+	}
+}
+
+// -------------------------------------------------------------
+// Time tracking
+// -------------------------------------------------------------
+
+// ItemTime lists the time logged on an item with totals per user
+func (s *KanbanService) ItemTime(ctx context.Context, projectID, kanbanID, itemID string, filter KanbanTimeFilter) (*dto.GetKanbanItemTimeResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+	})
+
+	if _, err := s.getKanban(ctx, projectID, kanbanID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Kanban.GetItem(ctx, itemID, kanbanID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewError(http.StatusNotFound, "item not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch item", err)
+	}
+
+	filter.ItemID = &itemID
+	params := filter.params(projectID)
+	entries, err := s.repos.Kanban.ListTimeEntries(ctx, params)
+	if err != nil {
+		logger.WithError(err).Error("failed to list time entries")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list time entries", err)
+	}
+	users, err := s.repos.Kanban.TimeTotalsByUser(ctx, repository.KanbanTimeTotalsByUserParams(params))
+	if err != nil {
+		logger.WithError(err).Error("failed to total time entries")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list time entries", err)
+	}
+
+	return &dto.GetKanbanItemTimeResponse{
+		Entries: entries,
+		Users:   users,
+		Seconds: sumUserSeconds(users),
+	}, nil
+}
+
+// TimeReport totals the time logged in a project by user and by item
+func (s *KanbanService) TimeReport(ctx context.Context, projectID string, filter KanbanTimeFilter) (*dto.GetKanbanTimeReportResponse, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID)
+
+	params := filter.params(projectID)
+	users, err := s.repos.Kanban.TimeTotalsByUser(ctx, repository.KanbanTimeTotalsByUserParams(params))
+	if err != nil {
+		logger.WithError(err).Error("failed to total time per user")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load time report", err)
+	}
+	items, err := s.repos.Kanban.TimeTotalsByItem(ctx, repository.KanbanTimeTotalsByItemParams(params))
+	if err != nil {
+		logger.WithError(err).Error("failed to total time per item")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to load time report", err)
+	}
+
+	return &dto.GetKanbanTimeReportResponse{
+		Users:   users,
+		Items:   items,
+		Seconds: sumUserSeconds(users),
+	}, nil
+}
+
+// TimeEntries lists the time entries of a project oldest first, used for exports
+func (s *KanbanService) TimeEntries(ctx context.Context, projectID string, filter KanbanTimeFilter) ([]repository.ListKanbanTimeEntriesRow, error) {
+	entries, err := s.repos.Kanban.ListTimeEntries(ctx, filter.params(projectID))
+	if err != nil {
+		logging.WithLayer(ctx, "service", "kanban").WithField("project_id", projectID).WithError(err).Error("failed to list time entries")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list time entries", err)
+	}
+	return entries, nil
+}
+
+// StartTimer starts a timer for the user on an item, a user runs one timer
+// at a time so a running one has to be stopped first
+func (s *KanbanService) StartTimer(ctx context.Context, projectID, kanbanID, itemID, userID string, params dto.StartKanbanTimerInput) (*repository.KanbanTimeEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"user_id":    userID,
+	})
+
+	var entry repository.KanbanTimeEntry
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getActiveItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		running, err := q.GetRunningKanbanTimer(ctx, userID)
+		if err == nil {
+			return utils.NewConflict("a timer is already running", running)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.WithError(err).Error("failed to fetch running timer")
+			return utils.NewError(http.StatusInternalServerError, "failed to start timer", err)
+		}
+
+		entry, err = q.StartKanbanTimer(ctx, repository.StartKanbanTimerParams{
+			ID:        gonanoid.Must(),
+			ProjectID: projectID,
+			ItemID:    itemID,
+			UserID:    userID,
+			Note:      utils.PtrToPgText(params.Note),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a timer is already running", err)
+			}
+			logger.WithError(err).Error("failed to start timer")
+			return utils.NewError(http.StatusInternalServerError, "failed to start timer", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("entry_id", entry.ID).Info("timer started")
+	return &entry, nil
+}
+
+// StopTimer stops the running timer of the user on an item
+func (s *KanbanService) StopTimer(ctx context.Context, projectID, kanbanID, itemID, userID string) (*repository.KanbanTimeEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"user_id":    userID,
+	})
+
+	var entry repository.KanbanTimeEntry
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		running, err := q.GetRunningKanbanTimer(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "no running timer", err)
+			}
+			logger.WithError(err).Error("failed to fetch running timer")
+			return utils.NewError(http.StatusInternalServerError, "failed to stop timer", err)
+		}
+		if running.ItemID != itemID {
+			return utils.NewConflict("the running timer is on another item", running)
+		}
+
+		entry, err = q.StopKanbanTimer(ctx, userID)
+		if err != nil {
+			logger.WithError(err).Error("failed to stop timer")
+			return utils.NewError(http.StatusInternalServerError, "failed to stop timer", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("entry_id", entry.ID).Info("timer stopped")
+	return &entry, nil
+}
+
+// CreateTimeEntry logs time the user spent on an item without a timer
+func (s *KanbanService) CreateTimeEntry(ctx context.Context, projectID, kanbanID, itemID, userID string, params dto.CreateKanbanTimeEntryInput) (*repository.KanbanTimeEntry, error) {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"user_id":    userID,
+	})
+
+	if !params.EndedAt.After(params.StartedAt) {
+		return nil, utils.NewFieldError("endedAt", "must be after the start")
+	}
+	if params.EndedAt.After(time.Now()) {
+		return nil, utils.NewFieldError("endedAt", "must not be in the future")
+	}
+
+	var entry repository.KanbanTimeEntry
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		var err error
+		entry, err = q.CreateKanbanTimeEntry(ctx, repository.CreateKanbanTimeEntryParams{
+			ID:        gonanoid.Must(),
+			ProjectID: projectID,
+			ItemID:    itemID,
+			UserID:    userID,
+			StartedAt: utils.PtrToPgTimestamptz(&params.StartedAt),
+			EndedAt:   utils.PtrToPgTimestamptz(&params.EndedAt),
+			Note:      utils.PtrToPgText(params.Note),
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to log time")
+			return utils.NewError(http.StatusInternalServerError, "failed to log time", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("entry_id", entry.ID).Info("time logged")
+	return &entry, nil
+}
+
+// DeleteTimeEntry removes a time entry, users delete their own entries and
+// moderators any entry
+func (s *KanbanService) DeleteTimeEntry(ctx context.Context, projectID, kanbanID, itemID, entryID, userID string, moderator bool) error {
+	logger := logging.WithLayer(ctx, "service", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+		"item_id":    itemID,
+		"entry_id":   entryID,
+	})
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getItem(ctx, q, projectID, kanbanID, itemID); err != nil {
+			return err
+		}
+
+		entry, err := q.GetKanbanTimeEntry(ctx, repository.GetKanbanTimeEntryParams{
+			ID:     entryID,
+			ItemID: itemID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "time entry not found", err)
+			}
+			logger.WithError(err).Error("failed to fetch time entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete time entry", err)
+		}
+		if entry.UserID != userID && !moderator {
+			return utils.NewError(http.StatusForbidden, "not allowed to delete this time entry", nil)
+		}
+
+		if _, err := q.DeleteKanbanTimeEntry(ctx, entryID); err != nil {
+			logger.WithError(err).Error("failed to delete time entry")
+			return utils.NewError(http.StatusInternalServerError, "failed to delete time entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("time entry deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+func sumUserSeconds(users []repository.KanbanTimeTotalsByUserRow) int64 {
+	var total int64
+	for _, u := range users {
+		total += u.Seconds
+	}
+	return total
+}
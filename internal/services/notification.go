@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationService struct {
+	repo   *repositories.NotificationRepo
+	logger *logrus.Logger
+}
+
+func NewNotificationService(repo *repositories.NotificationRepo, logger *logrus.Logger) *NotificationService {
+	return &NotificationService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// -------------------------------------------------------------
+// List
+// -------------------------------------------------------------
+func (s *NotificationService) List(ctx context.Context, userID string, pagination Pagination, unreadOnly bool) ([]dto.Notification, int64, error) {
+	logger := logging.WithLayer(ctx, "service", "notification").WithField("user_id", userID)
+
+	rows, err := s.repo.List(ctx, repository.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		Limit:      pagination.Limit,
+		Offset:     pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list notifications")
+		return nil, 0, utils.NewError(http.StatusInternalServerError, "failed to list notifications", err)
+	}
+
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Error("failed to count unread notifications")
+		return nil, 0, utils.NewError(http.StatusInternalServerError, "failed to list notifications", err)
+	}
+
+	notifications := make([]dto.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, dto.NewNotification(row))
+	}
+	return notifications, unread, nil
+}
+
+// -------------------------------------------------------------
+// Read
+// -------------------------------------------------------------
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID string) error {
+	logger := logging.WithLayer(ctx, "service", "notification").WithFields(logrus.Fields{
+		"user_id":         userID,
+		"notification_id": notificationID,
+	})
+
+	rows, err := s.repo.MarkRead(ctx, notificationID, userID)
+	if err != nil {
+		logger.WithError(err).Error("failed to mark notification as read")
+		return utils.NewError(http.StatusInternalServerError, "failed to mark notification as read", err)
+	}
+	if rows == 0 {
+		return utils.NewError(http.StatusNotFound, "notification not found", nil)
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	logger := logging.WithLayer(ctx, "service", "notification").WithField("user_id", userID)
+
+	marked, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Error("failed to mark notifications as read")
+		return 0, utils.NewError(http.StatusInternalServerError, "failed to mark notifications as read", err)
+	}
+
+	logger.Infof("marked %d notifications as read", marked)
+	return marked, nil
+}
+
+// -------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------
+
+// notify stores a notification for each recipient as part of the caller's
+// transaction, the actor is never notified about their own action. An empty
+// actor is the system, such as an automation rule.
+func notify(ctx context.Context, q repository.Querier, orgID, actorID string, typ dto.NotificationType, data any, recipients []string) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to encode notification", err)
+	}
+
+	var actor *string
+	if actorID != "" {
+		actor = &actorID
+	}
+
+	for _, userID := range recipients {
+		if userID == actorID {
+			continue
+		}
+		if err := q.CreateNotification(ctx, repository.CreateNotificationParams{
+			ID:             gonanoid.Must(),
+			UserID:         userID,
+			OrganisationID: orgID,
+			ActorID:        utils.PtrToPgText(actor),
+			Type:           string(typ),
+			Data:           raw,
+		}); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to create notification", err)
+		}
+	}
+	return nil
+}
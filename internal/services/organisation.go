@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
@@ -133,15 +135,39 @@ func (s *OrganisationService) Update(ctx context.Context, id, userId string, par
 
 	logger.Info("attempting to update organisation")
 
+	if days := params.KanbanArchiveRetentionDays.Value; days != nil && (*days < 1 || *days > maxArchiveRetentionDays) {
+		return nil, utils.NewFieldError("kanbanArchiveRetentionDays", fmt.Sprintf("must be between 1 and %d", maxArchiveRetentionDays))
+	}
+	if size := params.AttachmentMaxSize.Value; size != nil && *size < 1 {
+		return nil, utils.NewFieldError("attachmentMaxSize", "must be at least 1")
+	}
+	// No types is stored as null, both allow every type
+	var attachmentTypes []string
+	if params.AttachmentTypes.Value != nil && len(*params.AttachmentTypes.Value) > 0 {
+		attachmentTypes = *params.AttachmentTypes.Value
+		for i, t := range attachmentTypes {
+			attachmentTypes[i] = strings.ToLower(strings.TrimSpace(t))
+		}
+		if err := validAttachmentTypes(attachmentTypes); err != nil {
+			return nil, err
+		}
+	}
+
 	args := repository.UpdateOrganisationParams{
-		ID:            id,
-		Name:          utils.PtrToPgText(params.Name),
-		Description:   utils.PtrToPgText(params.Description),
-		Website:       utils.PtrToPgText(params.Website),
-		LogoUrl:       utils.PtrToPgText(params.LogoUrl),
-		Timezone:      utils.PtrToPgText(params.Timezone),
-		IsActive:      utils.PtrToPgBool(params.IsActive),
-		DefaultRoleID: utils.PtrToPgText(params.DefaultRoleID),
+		ID:                            id,
+		Name:                          utils.PtrToPgText(params.Name),
+		Description:                   utils.PtrToPgText(params.Description),
+		Website:                       utils.PtrToPgText(params.Website),
+		LogoUrl:                       utils.PtrToPgText(params.LogoUrl),
+		Timezone:                      utils.PtrToPgText(params.Timezone),
+		IsActive:                      utils.PtrToPgBool(params.IsActive),
+		DefaultRoleID:                 utils.PtrToPgText(params.DefaultRoleID),
+		SetKanbanArchiveRetentionDays: params.KanbanArchiveRetentionDays.Set,
+		KanbanArchiveRetentionDays:    utils.PtrToPgInt4(params.KanbanArchiveRetentionDays.Value),
+		SetAttachmentMaxSize:          params.AttachmentMaxSize.Set,
+		AttachmentMaxSize:             utils.PtrToPgInt8(params.AttachmentMaxSize.Value),
+		SetAttachmentTypes:            params.AttachmentTypes.Set,
+		AttachmentTypes:               attachmentTypes,
 	}
 
 	// Handle archive toggle
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -10,20 +11,23 @@ import (
 	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
 	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
 	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
 	"github.com/sirupsen/logrus"
 )
 
 type Checker struct {
-	memberRepo *repositories.MemberRepo
-	roleRepo   *repositories.RoleRepo
-	logger     *logrus.Logger
+	memberRepo  *repositories.MemberRepo
+	roleRepo    *repositories.RoleRepo
+	projectRepo *repositories.ProjectRepo
+	logger      *logrus.Logger
 }
 
-func NewChecker(memberRepo *repositories.MemberRepo, roleRepo *repositories.RoleRepo, logger *logrus.Logger) *Checker {
+func NewChecker(memberRepo *repositories.MemberRepo, roleRepo *repositories.RoleRepo, projectRepo *repositories.ProjectRepo, logger *logrus.Logger) *Checker {
 	return &Checker{
-		memberRepo: memberRepo,
-		roleRepo:   roleRepo,
-		logger:     logger,
+		memberRepo:  memberRepo,
+		roleRepo:    roleRepo,
+		projectRepo: projectRepo,
+		logger:      logger,
 	}
 }
 
@@ -62,3 +66,29 @@ func (c *Checker) Check(ctx context.Context, userID, orgID string, perm permissi
 
 	return nil
 }
+
+// CheckProject resolves the organisation owning a project and checks the
+// permission there, the organisation id is returned on success
+func (c *Checker) CheckProject(ctx context.Context, userID, projectID string, perm permissions.Permission) (string, error) {
+	logger := logging.WithLayer(ctx, "service", "checker").WithFields(logrus.Fields{
+		"user_id":    userID,
+		"project_id": projectID,
+		"perm":       perm,
+	})
+
+	project, err := c.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithError(err).Warn("project not found")
+			return "", utils.NewError(http.StatusNotFound, "project not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch project")
+		return "", utils.NewError(http.StatusInternalServerError, "failed to fetch project", err)
+	}
+
+	if err := c.Check(ctx, userID, project.OrganisationID, perm); err != nil {
+		return "", err
+	}
+
+	return project.OrganisationID, nil
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectServiceRepos struct {
+	Project *repositories.ProjectRepo
+}
+
+type ProjectService struct {
+	repos  *ProjectServiceRepos
+	tx     *repositories.TxManager
+	logger *logrus.Logger
+}
+
+func NewProjectService(repos ProjectServiceRepos, tx *repositories.TxManager, logger *logrus.Logger) *ProjectService {
+	return &ProjectService{
+		repos:  &repos,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// -------------------------------------------------------------
+// Create
+// -------------------------------------------------------------
+func (s *ProjectService) Create(ctx context.Context, orgID, userID string, params dto.CreateProjectInput) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+	logger.Infof("creating project: %s", params.Name)
+
+	var project repository.Project
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		var err error
+		project, err = createProject(ctx, logger, q, orgID, userID, params.Name, params.Members)
+		return err
+	})
+	if err != nil {
+		logger.WithError(err).Error("project creation failed")
+		return nil, err
+	}
+
+	logger.WithField("project_id", project.ID).Info("project created successfully")
+	return &project, nil
+}
+
+// -------------------------------------------------------------
+// List
+// -------------------------------------------------------------
+func (s *ProjectService) List(ctx context.Context, orgID, search string, status dto.ProjectStatus, pagination Pagination) ([]repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithField("org_id", orgID)
+	logger.Infof("fetching projects (search='%s', status='%s')", search, status)
+
+	projects, err := s.repos.Project.List(ctx, repository.ListProjectsParams{
+		OrganisationID: orgID,
+		Status:         string(status),
+		Search:         search,
+		Limit:          pagination.Limit,
+		Offset:         pagination.Offset,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list projects")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list projects", err)
+	}
+
+	logger.Infof("fetched %d projects", len(projects))
+	return projects, nil
+}
+
+// -------------------------------------------------------------
+// Get
+// -------------------------------------------------------------
+func (s *ProjectService) Get(ctx context.Context, id, orgID string) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+	})
+
+	project, err := s.repos.Project.Get(ctx, id, orgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithError(err).Warn("project not found")
+			return nil, utils.NewError(http.StatusNotFound, "project not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch project")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch project", err)
+	}
+
+	return &project, nil
+}
+
+// -------------------------------------------------------------
+// Update
+// -------------------------------------------------------------
+func (s *ProjectService) Update(ctx context.Context, id, orgID string, params dto.UpdateProjectInput) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+	})
+	logger.Info("attempting to update project")
+
+	current, err := s.Get(ctx, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.ProjectStatus(current.Status) == dto.ProjectArchived {
+		logger.Warn("cannot edit an archived project")
+		return nil, utils.NewError(http.StatusConflict, "project is archived", nil)
+	}
+
+	project, err := s.repos.Project.Update(ctx, repository.UpdateProjectParams{
+		ID:             id,
+		OrganisationID: orgID,
+		Name:           utils.PtrToPgText(params.Name),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to update project")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to update project", err)
+	}
+
+	logger.Info("project updated successfully")
+	return &project, nil
+}
+
+// UpdateStatus moves a project between Active, Completed and Archived
+func (s *ProjectService) UpdateStatus(ctx context.Context, id, orgID string, status dto.ProjectStatus) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+		"status":     status,
+	})
+
+	current, err := s.Get(ctx, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := dto.ProjectStatus(current.Status)
+	if from == status {
+		return current, nil
+	}
+	if !from.CanTransition(status) {
+		logger.Warnf("invalid status transition from %s", from)
+		return nil, utils.NewError(http.StatusConflict, "invalid status transition from "+string(from)+" to "+string(status), nil)
+	}
+
+	project, err := s.repos.Project.UpdateStatus(ctx, repository.UpdateProjectStatusParams{
+		ID:             id,
+		OrganisationID: orgID,
+		Status:         string(status),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to update project status")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to update project status", err)
+	}
+
+	logger.Infof("project moved from %s to %s", from, status)
+	return &project, nil
+}
+
+// -------------------------------------------------------------
+// Delete
+// -------------------------------------------------------------
+func (s *ProjectService) Delete(ctx context.Context, id, orgID string) error {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+	})
+
+	deleted, err := s.repos.Project.Delete(ctx, id, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete project")
+		return utils.NewError(http.StatusInternalServerError, "failed to delete project", err)
+	}
+	if deleted == 0 {
+		logger.Warn("project not found")
+		return utils.NewError(http.StatusNotFound, "project not found", nil)
+	}
+
+	logger.Info("project deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Members
+// -------------------------------------------------------------
+func (s *ProjectService) ListMembers(ctx context.Context, id, orgID string) ([]repository.ListProjectMembersRow, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+	})
+
+	if _, err := s.Get(ctx, id, orgID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repos.Project.ListMembers(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to list project members")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list project members", err)
+	}
+
+	logger.Infof("fetched %d project members", len(members))
+	return members, nil
+}
+
+func (s *ProjectService) AddMember(ctx context.Context, id, orgID string, params dto.CreateProjectMemberInput) (*repository.ProjectMember, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+		"member_id":  params.UserID,
+	})
+	logger.Info("adding project member")
+
+	if _, err := s.Get(ctx, id, orgID); err != nil {
+		return nil, err
+	}
+
+	var member repository.ProjectMember
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if err := addProjectMember(ctx, logger, q, orgID, id, params.UserID, params.Role); err != nil {
+			return err
+		}
+
+		var err error
+		member, err = q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+			ProjectID: id,
+			UserID:    params.UserID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("project member added")
+	return &member, nil
+}
+
+func (s *ProjectService) UpdateMember(ctx context.Context, id, orgID, memberID string, role dto.ProjectRole) (*repository.ProjectMember, error) {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+		"member_id":  memberID,
+	})
+	logger.Infof("changing project member role to %s", role)
+
+	if _, err := s.Get(ctx, id, orgID); err != nil {
+		return nil, err
+	}
+
+	var member repository.ProjectMember
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// Two admins demoting or removing each other must not both pass
+		// the admin count
+		if err := q.LockProjectMembers(ctx, id); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to lock project members", err)
+		}
+
+		current, err := q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+			ProjectID: id,
+			UserID:    memberID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "project member not found", err)
+			}
+			return utils.NewError(http.StatusInternalServerError, "failed to fetch project member", err)
+		}
+
+		if dto.ProjectRole(current.Role) == dto.ProjectAdmin && role != dto.ProjectAdmin {
+			if err := ensureAnotherAdmin(ctx, q, id); err != nil {
+				return err
+			}
+		}
+
+		member, err = q.UpdateProjectMemberRole(ctx, repository.UpdateProjectMemberRoleParams{
+			ProjectID: id,
+			UserID:    memberID,
+			Role:      string(role),
+		})
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to update project member", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to update project member")
+		return nil, err
+	}
+
+	logger.Info("project member updated")
+	return &member, nil
+}
+
+func (s *ProjectService) RemoveMember(ctx context.Context, id, orgID, memberID string) error {
+	logger := logging.WithLayer(ctx, "service", "project").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"project_id": id,
+		"member_id":  memberID,
+	})
+	logger.Info("removing project member")
+
+	if _, err := s.Get(ctx, id, orgID); err != nil {
+		return err
+	}
+
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		// Two admins demoting or removing each other must not both pass
+		// the admin count
+		if err := q.LockProjectMembers(ctx, id); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to lock project members", err)
+		}
+
+		current, err := q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+			ProjectID: id,
+			UserID:    memberID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.NewError(http.StatusNotFound, "project member not found", err)
+			}
+			return utils.NewError(http.StatusInternalServerError, "failed to fetch project member", err)
+		}
+
+		if dto.ProjectRole(current.Role) == dto.ProjectAdmin {
+			if err := ensureAnotherAdmin(ctx, q, id); err != nil {
+				return err
+			}
+		}
+
+		if _, err := q.RemoveProjectMember(ctx, repository.RemoveProjectMemberParams{
+			ProjectID: id,
+			UserID:    memberID,
+		}); err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to remove project member", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove project member")
+		return err
+	}
+
+	logger.Info("project member removed")
+	return nil
+}
+
+// Helpers
+
+// createProject creates a project and its members, the creator is always added as admin
+func createProject(ctx context.Context, logger *logrus.Entry, q repository.Querier, orgID, userID, name string, members []dto.CreateProjectMemberInput) (repository.Project, error) {
+	project, err := q.CreateProject(ctx, repository.CreateProjectParams{
+		ID:             gonanoid.Must(),
+		OrganisationID: orgID,
+		Name:           utils.PtrToPgText(&name),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create project in DB")
+		return project, utils.NewError(http.StatusInternalServerError, "failed to create project", err)
+	}
+
+	roles := map[string]dto.ProjectRole{userID: dto.ProjectAdmin}
+	for _, m := range members {
+		if m.UserID == userID {
+			continue
+		}
+		roles[m.UserID] = m.Role
+	}
+
+	for memberID, role := range roles {
+		if err := addProjectMember(ctx, logger, q, orgID, project.ID, memberID, role); err != nil {
+			return project, err
+		}
+	}
+
+	return project, nil
+}
+
+// addProjectMember adds an organisation member to a project
+func addProjectMember(ctx context.Context, logger *logrus.Entry, q repository.Querier, orgID, projectID, userID string, role dto.ProjectRole) error {
+	isMember, err := q.OrganisationMemberExists(ctx, repository.OrganisationMemberExistsParams{
+		OrganisationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to check organisation membership")
+		return utils.NewError(http.StatusInternalServerError, "failed to check membership", err)
+	}
+	if !isMember {
+		logger.WithField("member_id", userID).Warn("user is not a member of the organisation")
+		return utils.NewError(http.StatusBadRequest, "user is not a member of the organisation", nil)
+	}
+
+	_, err = q.GetProjectMember(ctx, repository.GetProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err == nil {
+		logger.WithField("member_id", userID).Warn("user is already a project member")
+		return utils.NewError(http.StatusConflict, "user already member of project", nil)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithError(err).Error("failed to check project membership")
+		return utils.NewError(http.StatusInternalServerError, "failed to check project membership", err)
+	}
+
+	if _, err := q.AddProjectMember(ctx, repository.AddProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      string(role),
+	}); err != nil {
+		logger.WithError(err).Error("failed to add project member")
+		return utils.NewError(http.StatusInternalServerError, "failed to add project member", err)
+	}
+
+	// Cards imported with this user as an invited assignee are assigned now
+	if _, err := q.AcceptKanbanItemInvitations(ctx, repository.AcceptKanbanItemInvitationsParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	}); err != nil {
+		logger.WithError(err).Error("failed to accept card invitations")
+		return utils.NewError(http.StatusInternalServerError, "failed to add project member", err)
+	}
+	return nil
+}
+
+// ensureAnotherAdmin prevents a project from losing its last admin, the
+// members of the project must be locked with LockProjectMembers
+func ensureAnotherAdmin(ctx context.Context, q repository.Querier, projectID string) error {
+	admins, err := q.CountProjectAdmins(ctx, projectID)
+	if err != nil {
+		return utils.NewError(http.StatusInternalServerError, "failed to count project admins", err)
+	}
+	if admins <= 1 {
+		return utils.NewError(http.StatusConflict, "project must have at least one admin", nil)
+	}
+	return nil
+}
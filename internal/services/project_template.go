@@ -0,0 +1,509 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/db/repository"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/repositories"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectTemplateServiceRepos struct {
+	Template *repositories.ProjectTemplateRepo
+}
+
+type ProjectTemplateService struct {
+	repos  *ProjectTemplateServiceRepos
+	tx     *repositories.TxManager
+	logger *logrus.Logger
+}
+
+func NewProjectTemplateService(repos ProjectTemplateServiceRepos, tx *repositories.TxManager, logger *logrus.Logger) *ProjectTemplateService {
+	return &ProjectTemplateService{
+		repos:  &repos,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// -------------------------------------------------------------
+// Create
+// -------------------------------------------------------------
+
+// Create saves an existing project as an organisation template
+func (s *ProjectTemplateService) Create(ctx context.Context, orgID, userID string, params dto.CreateProjectTemplateInput) (*repository.ProjectTemplate, error) {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"user_id":    userID,
+		"project_id": params.ProjectID,
+	})
+	logger.Infof("saving project as template: %s", params.Name)
+
+	var template repository.ProjectTemplate
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getProjectInOrg(ctx, q, params.ProjectID, orgID); err != nil {
+			return err
+		}
+
+		content, err := snapshotProject(ctx, q, params.ProjectID, params.IncludeItems, params.IncludeWhiteboards)
+		if err != nil {
+			logger.WithError(err).Error("failed to snapshot project")
+			return utils.NewError(http.StatusInternalServerError, "failed to read project", err)
+		}
+
+		raw, err := json.Marshal(content)
+		if err != nil {
+			return utils.NewError(http.StatusInternalServerError, "failed to encode template", err)
+		}
+
+		template, err = q.CreateProjectTemplate(ctx, repository.CreateProjectTemplateParams{
+			ID:                 gonanoid.Must(),
+			OrganisationID:     orgID,
+			CreatedBy:          utils.PtrToPgText(&userID),
+			Name:               params.Name,
+			Description:        utils.PtrToPgText(params.Description),
+			IncludeItems:       params.IncludeItems,
+			IncludeWhiteboards: params.IncludeWhiteboards,
+			Content:            raw,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return utils.NewError(http.StatusConflict, "a template with that name already exists", err)
+			}
+			logger.WithError(err).Error("failed to create template")
+			return utils.NewError(http.StatusInternalServerError, "failed to create template", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("template creation failed")
+		return nil, err
+	}
+
+	logger.WithField("template_id", template.ID).Info("project template created")
+	return &template, nil
+}
+
+// -------------------------------------------------------------
+// List / Get / Delete
+// -------------------------------------------------------------
+func (s *ProjectTemplateService) List(ctx context.Context, orgID string, pagination Pagination) ([]repository.ProjectTemplate, error) {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithField("org_id", orgID)
+
+	templates, err := s.repos.Template.List(ctx, orgID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		logger.WithError(err).Error("failed to list templates")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to list templates", err)
+	}
+
+	logger.Infof("fetched %d templates", len(templates))
+	return templates, nil
+}
+
+func (s *ProjectTemplateService) Get(ctx context.Context, id, orgID string) (*repository.ProjectTemplate, error) {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"template_id": id,
+	})
+
+	template, err := s.repos.Template.Get(ctx, id, orgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithError(err).Warn("template not found")
+			return nil, utils.NewError(http.StatusNotFound, "template not found", err)
+		}
+		logger.WithError(err).Error("failed to fetch template")
+		return nil, utils.NewError(http.StatusInternalServerError, "failed to fetch template", err)
+	}
+
+	return &template, nil
+}
+
+func (s *ProjectTemplateService) Delete(ctx context.Context, id, orgID string) error {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"template_id": id,
+	})
+
+	deleted, err := s.repos.Template.Delete(ctx, id, orgID)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete template")
+		return utils.NewError(http.StatusInternalServerError, "failed to delete template", err)
+	}
+	if deleted == 0 {
+		logger.Warn("template not found")
+		return utils.NewError(http.StatusNotFound, "template not found", nil)
+	}
+
+	logger.Info("template deleted")
+	return nil
+}
+
+// -------------------------------------------------------------
+// Instantiate
+// -------------------------------------------------------------
+
+// Instantiate creates a new project from a template
+func (s *ProjectTemplateService) Instantiate(ctx context.Context, orgID, userID, templateID string, params dto.InstantiateProjectTemplateInput) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithFields(logrus.Fields{
+		"org_id":      orgID,
+		"user_id":     userID,
+		"template_id": templateID,
+	})
+	logger.Infof("creating project %s from template", params.Name)
+
+	template, err := s.Get(ctx, templateID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var content dto.ProjectTemplateContent
+	if err := json.Unmarshal(template.Content, &content); err != nil {
+		logger.WithError(err).Error("failed to decode template content")
+		return nil, utils.NewError(http.StatusInternalServerError, "template content is corrupt", err)
+	}
+
+	var project repository.Project
+	err = s.tx.WithTx(ctx, func(q repository.Querier) error {
+		project, err = createProject(ctx, logger, q, orgID, userID, params.Name, params.Members)
+		if err != nil {
+			return err
+		}
+
+		if _, err := applyProjectContent(ctx, q, project.ID, &content); err != nil {
+			logger.WithError(err).Error("failed to apply template content")
+			return utils.NewError(http.StatusInternalServerError, "failed to create project from template", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to instantiate template")
+		return nil, err
+	}
+
+	logger.WithField("project_id", project.ID).Info("project created from template")
+	return &project, nil
+}
+
+// -------------------------------------------------------------
+// Clone
+// -------------------------------------------------------------
+
+// Clone deep-copies an existing project into a new project
+func (s *ProjectTemplateService) Clone(ctx context.Context, orgID, userID, projectID string, params dto.CloneProjectInput) (*repository.Project, error) {
+	logger := logging.WithLayer(ctx, "service", "project_template").WithFields(logrus.Fields{
+		"org_id":     orgID,
+		"user_id":    userID,
+		"project_id": projectID,
+	})
+	logger.Infof("cloning project into %s", params.Name)
+
+	var project repository.Project
+	err := s.tx.WithTx(ctx, func(q repository.Querier) error {
+		if _, err := getProjectInOrg(ctx, q, projectID, orgID); err != nil {
+			return err
+		}
+
+		content, err := snapshotProject(ctx, q, projectID, params.IncludeItems, params.IncludeWhiteboards)
+		if err != nil {
+			logger.WithError(err).Error("failed to snapshot project")
+			return utils.NewError(http.StatusInternalServerError, "failed to read project", err)
+		}
+
+		var members []dto.CreateProjectMemberInput
+		if params.IncludeMembers {
+			current, err := q.ListProjectMembers(ctx, projectID)
+			if err != nil {
+				return utils.NewError(http.StatusInternalServerError, "failed to read project members", err)
+			}
+			for _, m := range current {
+				members = append(members, dto.CreateProjectMemberInput{
+					UserID: m.UserID,
+					Role:   dto.ProjectRole(m.Role),
+				})
+			}
+		}
+
+		project, err = createProject(ctx, logger, q, orgID, userID, params.Name, members)
+		if err != nil {
+			return err
+		}
+
+		if _, err := applyProjectContent(ctx, q, project.ID, content); err != nil {
+			logger.WithError(err).Error("failed to copy project content")
+			return utils.NewError(http.StatusInternalServerError, "failed to clone project", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to clone project")
+		return nil, err
+	}
+
+	logger.WithField("new_project_id", project.ID).Info("project cloned")
+	return &project, nil
+}
+
+// Helpers
+
+func getProjectInOrg(ctx context.Context, q repository.Querier, projectID, orgID string) (repository.Project, error) {
+	project, err := q.GetProject(ctx, repository.GetProjectParams{
+		ID:             projectID,
+		OrganisationID: orgID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return project, utils.NewError(http.StatusNotFound, "project not found", err)
+		}
+		return project, utils.NewError(http.StatusInternalServerError, "failed to fetch project", err)
+	}
+	return project, nil
+}
+
+// snapshotProject reads the boards (and optionally items and whiteboards) of a project
+func snapshotProject(ctx context.Context, q repository.Querier, projectID string, includeItems, includeWhiteboards bool) (*dto.ProjectTemplateContent, error) {
+	content := &dto.ProjectTemplateContent{Kanbans: []dto.TemplateKanban{}}
+
+	kanbans, err := q.ListKanbansByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range kanbans {
+		kanban := dto.TemplateKanban{
+			ID:                k.ID,
+			Name:              k.Name,
+			Status:            k.Status,
+			BlockedMovePolicy: &k.BlockedMovePolicy,
+			WipPolicy:         &k.WipPolicy,
+			Categories:        []dto.TemplateCategory{},
+		}
+
+		lanes, err := q.ListKanbanLanes(ctx, k.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lanes {
+			kanban.Lanes = append(kanban.Lanes, dto.TemplateLane{
+				ID:    l.ID,
+				Name:  l.Name,
+				Color: utils.PgTextToPtr(l.Color),
+			})
+		}
+
+		categories, err := q.ListKanbanCategories(ctx, k.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		itemsByCategory := map[string][]dto.TemplateItem{}
+		if includeItems {
+			items, err := q.ListKanbanItems(ctx, k.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range items {
+				itemsByCategory[i.KanbanCategoryID] = append(itemsByCategory[i.KanbanCategoryID], dto.TemplateItem{
+					ID:            i.ID,
+					Title:         i.Title,
+					Description:   utils.PgTextToPtr(i.Description),
+					Priority:      i.Priority,
+					DueDate:       utils.PgTimestamptzToPtr(i.DueDate),
+					EstimatedTime: utils.PgInt4ToPtr(i.EstimatedTime),
+					LaneID:        utils.PgTextToPtr(i.LaneID),
+				})
+			}
+		}
+
+		for _, c := range categories {
+			kanban.Categories = append(kanban.Categories, dto.TemplateCategory{
+				ID:       c.ID,
+				Name:     utils.PgTextToPtr(c.Name),
+				Done:     c.Done,
+				WipLimit: utils.PgInt4ToPtr(c.WipLimit),
+				Items:    itemsByCategory[c.ID],
+			})
+		}
+
+		content.Kanbans = append(content.Kanbans, kanban)
+	}
+
+	if !includeWhiteboards {
+		return content, nil
+	}
+
+	whiteboards, err := q.ListWhiteboardsByProject(ctx, utils.PtrToPgText(&projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range whiteboards {
+		whiteboard := dto.TemplateWhiteboard{
+			ID:    w.ID,
+			Name:  utils.PgTextToPtr(w.Name),
+			Lines: []dto.TemplateLine{},
+		}
+
+		lines, err := q.ListWhiteboardLines(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+		points, err := q.ListWhiteboardPoints(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		pointsByLine := map[string][]float64{}
+		for _, p := range points {
+			pointsByLine[p.LineDataID] = append(pointsByLine[p.LineDataID], p.Point)
+		}
+
+		for _, l := range lines {
+			whiteboard.Lines = append(whiteboard.Lines, dto.TemplateLine{
+				ID:          l.ID,
+				Stroke:      utils.PgTextToPtr(l.Stroke),
+				StrokeWidth: utils.PgInt4ToPtr(l.StrokeWidth),
+				Tool:        utils.PgTextToPtr(l.Tool),
+				TextContent: utils.PgTextToPtr(l.TextContent),
+				Points:      pointsByLine[l.ID],
+			})
+		}
+
+		content.Whiteboards = append(content.Whiteboards, whiteboard)
+	}
+
+	return content, nil
+}
+
+// applyProjectContent inserts a snapshot into a project with fresh ids.
+// Returns a map from the snapshot ids to the newly generated ids.
+func applyProjectContent(ctx context.Context, q repository.Querier, projectID string, content *dto.ProjectTemplateContent) (map[string]string, error) {
+	ids := map[string]string{}
+	remap := func(old string) string {
+		id := gonanoid.Must()
+		if old != "" {
+			ids[old] = id
+		}
+		return id
+	}
+
+	for _, k := range content.Kanbans {
+		// Templates saved before policies were snapshotted take the defaults
+		kanban, err := q.CreateKanban(ctx, repository.CreateKanbanParams{
+			ID:                remap(k.ID),
+			ProjectID:         projectID,
+			Name:              k.Name,
+			Status:            k.Status,
+			BlockedMovePolicy: utils.PtrToPgText(k.BlockedMovePolicy),
+			WipPolicy:         utils.PtrToPgText(k.WipPolicy),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Templates keep lanes, categories and items in board order, ranks
+		// are reassigned. Lanes come first so items can be put in them.
+		laneRanks := utils.RankSequence(len(k.Lanes))
+		for li, l := range k.Lanes {
+			if _, err := q.CreateKanbanLane(ctx, repository.CreateKanbanLaneParams{
+				ID:       remap(l.ID),
+				KanbanID: kanban.ID,
+				Name:     l.Name,
+				Color:    utils.PtrToPgText(l.Color),
+				Rank:     laneRanks[li],
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		categoryRanks := utils.RankSequence(len(k.Categories))
+		for ci, c := range k.Categories {
+			category, err := q.CreateKanbanCategory(ctx, repository.CreateKanbanCategoryParams{
+				ID:       remap(c.ID),
+				KanbanID: kanban.ID,
+				Name:     utils.PtrToPgText(c.Name),
+				Rank:     categoryRanks[ci],
+				Done:     c.Done,
+				WipLimit: utils.PtrToPgInt4(c.WipLimit),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			itemRanks := utils.RankSequence(len(c.Items))
+			for ii, i := range c.Items {
+				// Items of a lane missing from the snapshot go without one
+				var laneID *string
+				if i.LaneID != nil {
+					if id, ok := ids[*i.LaneID]; ok {
+						laneID = &id
+					}
+				}
+				if _, err := q.CreateKanbanItem(ctx, repository.CreateKanbanItemParams{
+					ID:               remap(i.ID),
+					KanbanCategoryID: category.ID,
+					Title:            i.Title,
+					Description:      utils.PtrToPgText(i.Description),
+					Priority:         i.Priority,
+					DueDate:          utils.PtrToPgTimestamptz(i.DueDate),
+					EstimatedTime:    utils.PtrToPgInt4(i.EstimatedTime),
+					Rank:             itemRanks[ii],
+					LaneID:           utils.PtrToPgText(laneID),
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for _, w := range content.Whiteboards {
+		whiteboard, err := q.CreateWhiteboard(ctx, repository.CreateWhiteboardParams{
+			ID:        remap(w.ID),
+			ProjectID: utils.PtrToPgText(&projectID),
+			Name:      utils.PtrToPgText(w.Name),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range w.Lines {
+			line, err := q.CreateLineData(ctx, repository.CreateLineDataParams{
+				ID:           remap(l.ID),
+				WhiteboardID: whiteboard.ID,
+				Stroke:       utils.PtrToPgText(l.Stroke),
+				StrokeWidth:  utils.PtrToPgInt4(l.StrokeWidth),
+				Tool:         utils.PtrToPgText(l.Tool),
+				TextContent:  utils.PtrToPgText(l.TextContent),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if len(l.Points) == 0 {
+				continue
+			}
+			points := make([]repository.CreateLinePointsParams, 0, len(l.Points))
+			for _, p := range l.Points {
+				points = append(points, repository.CreateLinePointsParams{
+					LineDataID: line.ID,
+					Point:      p,
+				})
+			}
+			if _, err := q.CreateLinePoints(ctx, points); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,411 @@
+package kanban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/internal/ws"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+)
+
+// Messages queued per client before it is considered too slow and dropped
+const messageBuffer = 64
+
+// Permission needed for each message a client can send
+var messagePermissions = map[utils.MessageType]permissions.Permission{
+	utils.KanbanArchive:             permissions.KanbanView,
+	utils.EditKanban:                permissions.KanbanEdit,
+	utils.NewKanbanCategory:         permissions.KanbanCreate,
+	utils.EditKanbanCategory:        permissions.KanbanEdit,
+	utils.DeleteKanbanCategory:      permissions.KanbanEdit,
+	utils.RestoreKanbanCategory:     permissions.KanbanEdit,
+	utils.MoveKanbanCategory:        permissions.KanbanEdit,
+	utils.PermaDeleteKanbanCategory: permissions.KanbanDelete,
+	utils.NewKanbanLane:             permissions.KanbanEdit,
+	utils.EditKanbanLane:            permissions.KanbanEdit,
+	utils.MoveKanbanLane:            permissions.KanbanEdit,
+	utils.DeleteKanbanLane:          permissions.KanbanEdit,
+	utils.NewKanbanItem:             permissions.KanbanCreate,
+	utils.EditKanbanItem:            permissions.KanbanEdit,
+	utils.MoveKanbanItem:            permissions.KanbanEdit,
+	utils.DeleteKanbanItem:          permissions.KanbanEdit,
+	utils.RestoreKanbanItem:         permissions.KanbanEdit,
+	utils.PermaDeleteKanbanItem:     permissions.KanbanDelete,
+	utils.BulkKanbanItems:           permissions.KanbanEdit,
+}
+
+type Client struct {
+	Conn      *websocket.Conn
+	Message   chan *ws.WSMessage
+	RoomID    string `json:"roomId"`
+	ProjectID string `json:"projectId"`
+	OrgID     string `json:"orgId"`
+	UserID    string `json:"userId"`
+	ctx       context.Context
+	mu        sync.Mutex
+	closed    bool
+}
+
+// send queues a message without blocking, it reports false if the
+// client is closed or its buffer is full
+func (c *Client) send(message *ws.WSMessage) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Message <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the writer, it is safe to call more than once
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Message)
+	}
+}
+
+// Function to send error message to user
+func (c *Client) SendErrorMessage(title string) {
+	Err := &ws.WSError{
+		Message: title,
+	}
+	c.send(&ws.WSMessage{
+		Type:    utils.KanbanError,
+		RoomID:  c.RoomID,
+		Payload: Err.ToJSON(),
+	})
+}
+
+// Function to send a warning to the user, the action itself went through
+func (c *Client) SendWarningMessage(title string) {
+	Warning := &ws.WSError{
+		Message: title,
+	}
+	c.send(&ws.WSMessage{
+		Type:    utils.KanbanWarning,
+		RoomID:  c.RoomID,
+		Payload: Warning.ToJSON(),
+	})
+}
+
+// sendError reports a service error to the user, with the rejected fields
+// when the input was invalid
+func (c *Client) sendError(err error) {
+	var apiErr utils.APIError
+	if errors.As(err, &apiErr) {
+		Err := &ws.WSError{
+			Message: apiErr.Message,
+			Fields:  apiErr.Fields,
+			Current: apiErr.Current,
+		}
+		c.send(&ws.WSMessage{
+			Type:    utils.KanbanError,
+			RoomID:  c.RoomID,
+			Payload: Err.ToJSON(),
+		})
+		return
+	}
+	c.SendErrorMessage("Server error! Something went wrong")
+}
+
+// Function that listens for messages from the client
+func (c *Client) readMessage(handler *Handler) {
+	defer func() {
+		handler.Hub.Unregister <- c
+		c.Conn.Close()
+	}()
+
+	logger := logging.WithLayer(c.ctx, "ws", "kanban")
+	for {
+		_, msg, err := c.Conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				logger.WithError(err).Warn("unexpected close error")
+			}
+			break
+		}
+		c.handleMessage(msg, handler)
+	}
+}
+
+// Function that writes messages to the clients connection
+func (c *Client) writeMessage() {
+	defer func() {
+		c.Conn.Close()
+	}()
+
+	for {
+		message, ok := <-c.Message
+		if !ok {
+			c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+			break
+		}
+
+		if err := c.Conn.WriteJSON(message); err != nil {
+			break
+		}
+	}
+}
+
+// decode reads a message payload and runs the same validation as the REST binding
+func (c *Client) decode(payload json.RawMessage, out any) bool {
+	if err := json.Unmarshal(payload, out); err != nil {
+		c.sendError(utils.NewValidationError(err))
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(out); err != nil {
+		c.sendError(utils.NewValidationError(err))
+		return false
+	}
+	return true
+}
+
+// Function to handle messages, every change goes through the kanban
+// service which broadcasts it to the room once committed
+func (c *Client) handleMessage(msg []byte, handler *Handler) {
+	logger := logging.WithLayer(c.ctx, "ws", "kanban")
+
+	var input ws.WSMessage
+	if err := json.Unmarshal(msg, &input); err != nil {
+		logger.WithError(err).Warn("failed to unmarshal message")
+		c.SendErrorMessage("User error! Wrong input")
+		return
+	}
+
+	perm, ok := messagePermissions[input.Type]
+	if !ok {
+		c.SendErrorMessage("Unknown message type")
+		return
+	}
+	if err := handler.services.Checker.Check(c.ctx, c.UserID, c.OrgID, perm); err != nil {
+		c.SendErrorMessage("You do not have the rights!")
+		return
+	}
+
+	kanban := handler.services.Kanban
+	var err error
+
+	switch input.Type {
+	// Kanban
+	case utils.KanbanArchive:
+		body := ArchivePage{Page: 1, Limit: dto.DefaultKanbanArchiveLimit}
+		if len(input.Payload) > 0 && !c.decode(input.Payload, &body) {
+			return
+		}
+		archive, archiveErr := kanban.Archive(c.ctx, c.ProjectID, c.RoomID, services.Pagination{
+			Limit:  int32(body.Limit),
+			Offset: int32((body.Page - 1) * body.Limit),
+		})
+		if archiveErr != nil {
+			err = archiveErr
+			break
+		}
+		archive.Page = body.Page
+		archive.Limit = body.Limit
+		payload, marshalErr := json.Marshal(archive)
+		if marshalErr != nil {
+			c.SendErrorMessage("Failed to send archive data")
+			return
+		}
+		c.send(&ws.WSMessage{
+			Type:    utils.KanbanArchive,
+			RoomID:  c.RoomID,
+			Payload: payload,
+		})
+	case utils.EditKanban:
+		var body EditKanban
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		if body.ID != c.RoomID {
+			c.SendErrorMessage("User error! Wrong kanban")
+			return
+		}
+		_, err = kanban.Update(c.ctx, c.ProjectID, c.RoomID, body.Updates, body.ExpectedVersion)
+
+	// Kanban categories
+	case utils.NewKanbanCategory:
+		var body NewCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.CreateCategory(c.ctx, c.ProjectID, c.RoomID, dto.CreateKanbanCategoryInput{
+			Name: body.Name,
+		})
+	case utils.EditKanbanCategory:
+		var body EditCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.UpdateCategory(c.ctx, c.ProjectID, c.RoomID, body.ID, dto.UpdateKanbanCategoryInput{
+			Name:     body.Name,
+			Done:     body.Done,
+			WipLimit: body.WipLimit,
+		}, body.ExpectedVersion)
+	case utils.DeleteKanbanCategory:
+		var body DeleteCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.DeleteCategory(c.ctx, c.ProjectID, c.RoomID, body.ID)
+	case utils.RestoreKanbanCategory:
+		var body RestoreKanbanCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.RestoreCategory(c.ctx, c.ProjectID, c.RoomID, body.ID)
+	case utils.MoveKanbanCategory:
+		var body MoveCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.MoveCategory(c.ctx, c.ProjectID, c.RoomID, body.ID, dto.MoveKanbanCategoryInput{
+			AfterID:  body.AfterID,
+			BeforeID: body.BeforeID,
+		})
+	case utils.PermaDeleteKanbanCategory:
+		var body DeleteCategory
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		err = kanban.PermaDeleteCategory(c.ctx, c.ProjectID, c.RoomID, body.ID)
+
+	// Kanban lanes
+	case utils.NewKanbanLane:
+		var body NewLane
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.CreateLane(c.ctx, c.ProjectID, c.RoomID, dto.CreateKanbanLaneInput{
+			Name:  body.Name,
+			Color: body.Color,
+		})
+	case utils.EditKanbanLane:
+		var body EditLane
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.UpdateLane(c.ctx, c.ProjectID, c.RoomID, body.ID, dto.UpdateKanbanLaneInput{
+			Name:  body.Name,
+			Color: body.Color,
+		}, body.ExpectedVersion)
+	case utils.MoveKanbanLane:
+		var body MoveLane
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.MoveLane(c.ctx, c.ProjectID, c.RoomID, body.ID, dto.MoveKanbanLaneInput{
+			AfterID:  body.AfterID,
+			BeforeID: body.BeforeID,
+		})
+	case utils.DeleteKanbanLane:
+		var body DeleteLane
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		err = kanban.DeleteLane(c.ctx, c.ProjectID, c.RoomID, body.ID)
+
+	// Kanban items
+	case utils.NewKanbanItem:
+		var body NewItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.CreateItem(c.ctx, c.ProjectID, c.RoomID, dto.CreateKanbanItemInput{
+			CategoryID: body.CategoryID,
+			LaneID:     body.LaneID,
+			Title:      body.Name,
+		})
+	case utils.EditKanbanItem:
+		var body EditItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.UpdateItem(c.ctx, c.ProjectID, c.RoomID, body.ItemID, body.Updates, body.ExpectedVersion)
+	case utils.MoveKanbanItem:
+		var body MoveItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		result, moveErr := kanban.MoveItem(c.ctx, c.ProjectID, c.RoomID, body.ItemID, dto.MoveKanbanItemInput{
+			CategoryID: body.NewCategoryID,
+			LaneID:     body.NewLaneID,
+			AfterID:    body.AfterID,
+			BeforeID:   body.BeforeID,
+		})
+		if moveErr != nil {
+			err = moveErr
+			break
+		}
+		for _, warning := range result.Warnings {
+			c.SendWarningMessage(warning.Message)
+		}
+	case utils.DeleteKanbanItem:
+		var body DeleteItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.DeleteItem(c.ctx, c.ProjectID, c.RoomID, body.ItemID)
+	case utils.RestoreKanbanItem:
+		var body RestoreKanbanItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		_, err = kanban.RestoreItem(c.ctx, c.ProjectID, c.RoomID, body.ItemID)
+	case utils.PermaDeleteKanbanItem:
+		var body DeleteItem
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		err = kanban.PermaDeleteItem(c.ctx, c.ProjectID, c.RoomID, body.ItemID)
+	case utils.BulkKanbanItems:
+		var body BulkItems
+		if !c.decode(input.Payload, &body) {
+			return
+		}
+		// Deleting cards for good needs the same right as a single permanent delete
+		if body.Action == dto.BulkDelete {
+			if err := handler.services.Checker.Check(c.ctx, c.UserID, c.OrgID, permissions.KanbanDelete); err != nil {
+				c.SendErrorMessage("You do not have the rights!")
+				return
+			}
+		}
+		result, bulkErr := kanban.BulkItems(c.ctx, c.ProjectID, c.RoomID, dto.BulkKanbanItemsInput{
+			Action:     body.Action,
+			ItemIDs:    body.ItemIDs,
+			CategoryID: body.CategoryID,
+			LabelID:    body.LabelID,
+			UserID:     body.UserID,
+		})
+		if bulkErr != nil {
+			err = bulkErr
+			break
+		}
+		for _, warning := range result.Warnings {
+			c.SendWarningMessage(warning.Message)
+		}
+	}
+
+	if err != nil {
+		logger.WithError(err).WithField("type", input.Type).Warn("kanban message failed")
+		c.sendError(err)
+	}
+}
@@ -0,0 +1,128 @@
+package kanban
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/config"
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
+	"github.com/Stenoliv/didlydoodash_api/internal/ws"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
+	"github.com/Stenoliv/didlydoodash_api/pkg/permissions"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type HandlerServices struct {
+	Kanban  *services.KanbanService
+	Checker *services.Checker
+}
+
+type Handler struct {
+	Hub      *Hub
+	services *HandlerServices
+	cfg      *config.EnvConfig
+}
+
+// Create a new kanban websocket handler, the hub is registered as the
+// publisher of the kanban service so REST changes reach the rooms too
+func NewHandler(services HandlerServices, cfg *config.EnvConfig) *Handler {
+	hub := NewHub()
+	services.Kanban.SetPublisher(hub)
+
+	return &Handler{
+		Hub:      hub,
+		services: &services,
+		cfg:      cfg,
+	}
+}
+
+func (h *Handler) Routes(rg *gin.RouterGroup) {
+	rg.GET("/projects/:id/kanbans/:kanbanId/ws", h.JoinKanban)
+}
+
+// GET /projects/{id}/kanbans/{kanbanId}/ws
+func (h *Handler) JoinKanban(c *gin.Context) {
+	projectID := c.Param("id")
+	kanbanID := c.Param("kanbanId")
+
+	logger := logging.WithLayer(c.Request.Context(), "ws", "kanban").WithFields(logrus.Fields{
+		"project_id": projectID,
+		"kanban_id":  kanbanID,
+	})
+
+	// Browsers can't set headers on websocket requests, the token is
+	// accepted from the cookie, header or the token query param
+	claims, err := utils.ValidateToken(h.cfg, utils.ExtractToken(c), utils.AccessToken)
+	if err != nil {
+		logger.WithError(err).Warn("invalid token provided")
+		c.Error(utils.NewError(http.StatusUnauthorized, "invalid token provided", err))
+		return
+	}
+	userID, err := claims.GetSubject()
+	if err != nil || userID == "" {
+		c.Error(utils.NewError(http.StatusUnauthorized, "user id not found in token", err))
+		return
+	}
+
+	logger = logger.WithField("user_id", userID)
+	ctx := logging.WithContextLogger(utils.WithUserID(context.Background(), userID), logger)
+
+	orgID, err := h.services.Checker.CheckProject(ctx, userID, projectID, permissions.KanbanView)
+	if err != nil {
+		logger.WithError(err).Warn("user not allowed to join kanban")
+		c.Error(err)
+		return
+	}
+
+	join, err := h.loadJoinMessage(ctx, projectID, kanbanID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to load kanban")
+		c.Error(err)
+		return
+	}
+	raw, err := join.ToJSON()
+	if err != nil {
+		c.Error(utils.NewError(http.StatusInternalServerError, "failed to load kanban", err))
+		return
+	}
+
+	conn, err := ws.WebsocketUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed to upgrade connection")
+		return
+	}
+
+	client := &Client{
+		Conn:      conn,
+		Message:   make(chan *ws.WSMessage, messageBuffer),
+		RoomID:    kanbanID,
+		ProjectID: projectID,
+		OrgID:     orgID,
+		UserID:    userID,
+		ctx:       ctx,
+	}
+
+	// Queue the initial state before the client starts receiving broadcasts
+	client.send(&ws.WSMessage{
+		Type:    utils.JoinKanban,
+		RoomID:  kanbanID,
+		Payload: raw,
+	})
+
+	h.Hub.Register <- client
+
+	logger.Info("user joined kanban")
+	go client.writeMessage()
+	go client.readMessage(h)
+}
+
+func (h *Handler) loadJoinMessage(ctx context.Context, projectID, kanbanID string) (*JoinMessage, error) {
+	board, err := h.services.Kanban.Board(ctx, projectID, kanbanID, dto.LaneByCustom)
+	if err != nil {
+		return nil, err
+	}
+	return &JoinMessage{Board: *board}, nil
+}
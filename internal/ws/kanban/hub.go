@@ -1,17 +1,19 @@
 package kanban
 
 import (
+	"context"
+	"encoding/json"
 	"sync"
 
-	"github.com/Stenoliv/didlydoodash_api/internal/db/daos"
+	"github.com/Stenoliv/didlydoodash_api/internal/services"
 	"github.com/Stenoliv/didlydoodash_api/internal/ws"
-	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+	"github.com/Stenoliv/didlydoodash_api/pkg/logging"
 )
 
 type Room struct {
-	ID      string             `json:"id"`
-	Clients map[string]*Client `json:"clients"`
-	mu      sync.Mutex
+	ID        string           `json:"id"`
+	ProjectID string           `json:"projectId"`
+	Clients   map[*Client]bool `json:"-"`
 }
 
 type Hub struct {
@@ -49,95 +51,83 @@ func (h *Hub) run() {
 	}
 }
 
-// Function to handle client register event
-func ClientRegister(h *Hub, client *Client) {
-	h.mu.Lock()
-	// Check for room
-	room, exists := h.Rooms[client.RoomID]
-	if !exists {
-		room = &Room{
-			ID:      client.RoomID,
-			Clients: make(map[string]*Client),
-		}
-		h.Rooms[client.RoomID] = room
-	}
-
-	// Add client to the room
-	room.mu.Lock()
-	room.Clients[client.UserID] = client
-	room.mu.Unlock()
+// Publish broadcasts a committed change to the kanban room, it is called
+// by the kanban service for changes made over both REST and websocket
+func (h *Hub) Publish(ctx context.Context, change services.KanbanChange) {
+	logger := logging.WithLayer(ctx, "ws", "kanban").WithField("kanban_id", change.KanbanID)
 
-	h.mu.Unlock()
-
-	// Get kanban user joined to
-	kanban, err := daos.GetKanban(client.RoomID)
-	if err != nil {
-		client.SendErrorMessage("Failed to get kanban")
+	h.mu.RLock()
+	_, exists := h.Rooms[change.KanbanID]
+	h.mu.RUnlock()
+	if !exists {
 		return
 	}
 
-	archive, err := daos.GetKanbanArchive(client.RoomID)
+	payload, err := json.Marshal(change.Payload)
 	if err != nil {
-		client.SendErrorMessage("Failed to get kanban archive")
+		logger.WithError(err).Error("failed to encode kanban change")
 		return
 	}
 
-	// Join message
-	payload := &JoinMessage{
-		Kanban:  *kanban,
-		Archive: archive,
+	h.Broadcast <- &ws.WSMessage{
+		Type:    change.Type,
+		RoomID:  change.KanbanID,
+		Payload: payload,
 	}
+}
 
-	// Turn payload into json
-	raw, err := payload.ToJSON()
-	if err != nil {
-		client.SendErrorMessage("Failed to get kanban")
-		return
-	}
+// Function to handle client register event
+func ClientRegister(h *Hub, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	// Send join data to client
-	client.Message <- &ws.WSMessage{
-		Type:    utils.JoinKanban,
-		RoomID:  client.RoomID,
-		Payload: raw,
+	// Check for room
+	room, exists := h.Rooms[client.RoomID]
+	if !exists {
+		room = &Room{
+			ID:        client.RoomID,
+			ProjectID: client.ProjectID,
+			Clients:   make(map[*Client]bool),
+		}
+		h.Rooms[client.RoomID] = room
 	}
+
+	// Add client to the room
+	room.Clients[client] = true
 }
 
 // Function to handle client unregister event
 func ClientUnregister(h *Hub, client *Client) {
 	h.mu.Lock()
+	defer h.mu.Unlock()
 
 	// Remove the client from the room if room exists
 	if room, exists := h.Rooms[client.RoomID]; exists {
-		room.mu.Lock()
-		delete(room.Clients, client.UserID)
+		delete(room.Clients, client)
+		client.close()
 
 		if len(room.Clients) <= 0 {
 			delete(h.Rooms, client.RoomID)
 		}
-
-		room.mu.Unlock()
 	}
-
-	h.mu.Unlock()
 }
 
 // Function to handle broadcast events
 func HandleBroadcast(h *Hub, message *ws.WSMessage) {
 	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	// Broadcast message to all clients in room
+	// Broadcast message to all clients in room, slow clients are dropped
 	if room, exists := h.Rooms[message.RoomID]; exists {
-		room.mu.Lock()
-		for _, client := range room.Clients {
-			select {
-			case client.Message <- message:
-			default:
-				close(client.Message)
-				delete(room.Clients, client.UserID)
+		for client := range room.Clients {
+			if !client.send(message) {
+				client.close()
+				delete(room.Clients, client)
 			}
 		}
-		room.mu.Unlock()
+
+		if len(room.Clients) <= 0 {
+			delete(h.Rooms, message.RoomID)
+		}
 	}
-	h.mu.Unlock()
 }
@@ -0,0 +1,142 @@
+package kanban
+
+import (
+	"encoding/json"
+
+	"github.com/Stenoliv/didlydoodash_api/internal/dto"
+	"github.com/Stenoliv/didlydoodash_api/pkg/utils"
+)
+
+// Join kanban room message, the archive is fetched page by page with an
+// archive message
+type JoinMessage struct {
+	Board dto.KanbanBoard `json:"board"`
+}
+
+// Archive page request, both fields are optional
+type ArchivePage struct {
+	Page  int `json:"page" binding:"omitempty,min=1"`
+	Limit int `json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (m *JoinMessage) ToJSON() ([]byte, error) {
+	return json.Marshal(&m)
+}
+
+// Edit kanban websocket message, a set expected version refuses the edit when
+// the kanban has changed since
+type EditKanban struct {
+	ID              string                `json:"id" binding:"required"`
+	ExpectedVersion *int32                `json:"expectedVersion"`
+	Updates         dto.UpdateKanbanInput `json:"updates"`
+}
+
+/**
+ * Category
+ */
+
+// New category input message
+type NewCategory struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type RestoreKanbanCategory struct {
+	ID string `json:"id" binding:"required"`
+}
+
+// Edit category websocket message, done marks the category as a done column
+type EditCategory struct {
+	ID              string                `json:"id" binding:"required"`
+	ExpectedVersion *int32                `json:"expectedVersion"`
+	Name            *string               `json:"name" binding:"omitempty,max=50"`
+	Done            *bool                 `json:"done"`
+	WipLimit        utils.Optional[int32] `json:"wipLimit"`
+}
+
+// Move category websocket message
+type MoveCategory struct {
+	ID       string  `json:"id" binding:"required"`
+	AfterID  *string `json:"afterId"`
+	BeforeID *string `json:"beforeId"`
+}
+
+// Delete category websocket message
+type DeleteCategory struct {
+	ID string `json:"id" binding:"required"`
+}
+
+/**
+ * Lanes
+ */
+
+// New lane websocket message
+type NewLane struct {
+	Name  string  `json:"name" binding:"required,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+// Edit lane websocket message, a null color removes it
+type EditLane struct {
+	ID              string                 `json:"id" binding:"required"`
+	ExpectedVersion *int32                 `json:"expectedVersion"`
+	Name            *string                `json:"name" binding:"omitempty,max=50"`
+	Color           utils.Optional[string] `json:"color"`
+}
+
+// Move lane websocket message
+type MoveLane struct {
+	ID       string  `json:"id" binding:"required"`
+	AfterID  *string `json:"afterId"`
+	BeforeID *string `json:"beforeId"`
+}
+
+// Delete lane websocket message
+type DeleteLane struct {
+	ID string `json:"id" binding:"required"`
+}
+
+/**
+ * Items
+ */
+
+// Items
+type NewItem struct {
+	CategoryID string  `json:"categoryId" binding:"required"`
+	LaneID     *string `json:"laneId"`
+	Name       string  `json:"name" binding:"required,max=40"`
+}
+
+type RestoreKanbanItem struct {
+	ItemID string `json:"itemId" binding:"required"`
+}
+
+// Move item websocket message, the lane is kept when newLaneId is left out
+// and removed when it is null
+type MoveItem struct {
+	OldCategoryID string                 `json:"oldCategoryId"`
+	NewCategoryID string                 `json:"newCategoryId" binding:"required"`
+	OldLaneID     *string                `json:"oldLaneId"`
+	NewLaneID     utils.Optional[string] `json:"newLaneId"`
+	ItemID        string                 `json:"itemId" binding:"required"`
+	AfterID       *string                `json:"afterId"`
+	BeforeID      *string                `json:"beforeId"`
+}
+
+type EditItem struct {
+	CategoryID      string                    `json:"categoryId"`
+	ItemID          string                    `json:"itemId" binding:"required"`
+	ExpectedVersion *int32                    `json:"expectedVersion"`
+	Updates         dto.UpdateKanbanItemInput `json:"updates"`
+}
+
+type DeleteItem struct {
+	ItemID string `json:"itemId" binding:"required"`
+}
+
+type BulkItems struct {
+	Action     dto.KanbanBulkAction `json:"action" binding:"required,oneof=move label unlabel assign unassign archive delete"`
+	ItemIDs    []string             `json:"itemIds" binding:"required,min=1,max=100,unique,dive,required"`
+	CategoryID *string              `json:"categoryId"`
+	LabelID    *string              `json:"labelId"`
+	UserID     *string              `json:"userId"`
+}
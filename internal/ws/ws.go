@@ -25,7 +25,9 @@ type WSMessage struct {
 }
 
 type WSError struct {
-	Message string `json:"message"`
+	Message string             `json:"message"`
+	Fields  []utils.FieldError `json:"fields,omitempty"`
+	Current any                `json:"current,omitempty"`
 }
 
 func (w *WSError) ToJSON() []byte {
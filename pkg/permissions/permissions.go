@@ -28,6 +28,12 @@ const (
 	WhiteboardEdit   Permission = "whiteboard:edit"
 	WhiteboardView   Permission = "whiteboard:view"
 	WhiteboardDelete Permission = "whiteboard:delete"
+
+	// Announcement
+	AnnouncementCreate Permission = "announcement:create"
+	AnnouncementEdit   Permission = "announcement:edit"
+	AnnouncementView   Permission = "announcement:view"
+	AnnouncementDelete Permission = "announcement:delete"
 )
 
 var (
@@ -37,6 +43,7 @@ var (
 		ProjectCreate, ProjectEdit, ProjectView, ProjectDelete,
 		KanbanCreate, KanbanEdit, KanbanView, KanbanDelete,
 		WhiteboardCreate, WhiteboardEdit, WhiteboardView, WhiteboardDelete,
+		AnnouncementCreate, AnnouncementEdit, AnnouncementView, AnnouncementDelete,
 	}
 
 	AdminPermissions = []Permission{
@@ -44,6 +51,7 @@ var (
 		ProjectCreate, ProjectEdit, ProjectView, ProjectDelete,
 		KanbanCreate, KanbanEdit, KanbanView, KanbanDelete,
 		WhiteboardCreate, WhiteboardEdit, WhiteboardView, WhiteboardDelete,
+		AnnouncementCreate, AnnouncementEdit, AnnouncementView, AnnouncementDelete,
 	}
 
 	MemberPermissions = []Permission{
@@ -51,9 +59,10 @@ var (
 		ProjectView, ProjectCreate, ProjectEdit,
 		KanbanView, KanbanCreate, KanbanEdit,
 		WhiteboardView, WhiteboardCreate, WhiteboardEdit,
+		AnnouncementView,
 	}
 
 	ViewerPermissions = []Permission{
-		ProjectView, KanbanView, WhiteboardView,
+		ProjectView, KanbanView, WhiteboardView, AnnouncementView,
 	}
 )
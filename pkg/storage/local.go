@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Local keeps files in a directory on disk. Its signed URLs point back at the
+// API, which checks the signature and serves the file with Open.
+type Local struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocal stores files under dir. baseURL is the address of the download
+// route, the key is appended to it.
+func NewLocal(dir, baseURL string, secret []byte) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, size int64, opts PutOptions) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	// Written next to its final place and renamed, so a file is either
+	// complete or missing
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if written != size {
+		return fmt.Errorf("wrote %d bytes, expected %d", written, size)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) SignedURL(ctx context.Context, key string, opts URLOptions) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	expires := strconv.FormatInt(opts.Expires.Unix(), 10)
+	query := url.Values{
+		"name":      {opts.FileName},
+		"type":      {opts.ContentType},
+		"expires":   {expires},
+		"signature": {l.sign(key, opts.FileName, opts.ContentType, expires)},
+	}
+	return l.baseURL + "/" + uriEncode(key, true) + "?" + query.Encode(), nil
+}
+
+// Verify checks the query of a URL made by SignedURL and returns the options
+// it was signed with
+func (l *Local) Verify(key string, query url.Values) (URLOptions, error) {
+	name, contentType, expires := query.Get("name"), query.Get("type"), query.Get("expires")
+	expected := l.sign(key, name, contentType, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+		return URLOptions{}, errors.New("invalid signature")
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return URLOptions{}, errors.New("invalid expiry")
+	}
+	if time.Now().Unix() > unix {
+		return URLOptions{}, errors.New("link expired")
+	}
+	return URLOptions{
+		FileName:    name,
+		ContentType: contentType,
+		Expires:     time.Unix(unix, 0),
+	}, nil
+}
+
+// Open reads the file stored under key
+func (l *Local) Open(key string) (*os.File, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// ContentDisposition is the header a download of name is served with
+func (l *Local) ContentDisposition(name string) string {
+	return contentDisposition(name)
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
+
+func (l *Local) sign(key, name, contentType, expires string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	// Fields are separated by a byte none of them can contain
+	mac.Write([]byte("storage\x00" + key + "\x00" + name + "\x00" + contentType + "\x00" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
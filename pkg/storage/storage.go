@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a stored file does not exist
+var ErrNotFound = errors.New("file not found")
+
+// Storage keeps uploaded files by key. Files are never served by the API
+// directly, clients download them from a signed URL that stops working after
+// a while.
+type Storage interface {
+	// Put stores size bytes read from body under key
+	Put(ctx context.Context, key string, body io.Reader, size int64, opts PutOptions) error
+	// Delete removes the file stored under key, a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a download link for key that is valid until expires
+	SignedURL(ctx context.Context, key string, opts URLOptions) (string, error)
+}
+
+type PutOptions struct {
+	ContentType string
+	// Checksum is the hex SHA-256 of the content
+	Checksum string
+}
+
+// URLOptions describe how a download is presented to the browser
+type URLOptions struct {
+	FileName    string
+	ContentType string
+	Expires     time.Time
+}
+
+// ValidKey reports whether key is a relative slash separated path without
+// empty, "." or ".." segments
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// contentDisposition offers the file for download under its original name
+func contentDisposition(fileName string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, fileName)
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback, uriEncode(fileName, false))
+}
+
+// uriEncode percent-encodes everything but the unreserved characters of
+// RFC 3986, and slashes when keepSlash is set
+func uriEncode(value string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
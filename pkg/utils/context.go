@@ -20,6 +20,15 @@ func GetUserID(c *gin.Context) string {
 	return val.(string)
 }
 
+// Get org_id resolved by the project permission middleware
+func GetOrgID(c *gin.Context) string {
+	val, exists := c.Get("org_id")
+	if !exists {
+		return ""
+	}
+	return val.(string)
+}
+
 func GetUserIDFromContext(ctx context.Context) string {
 	val := ctx.Value(userIDKey)
 	if id, ok := val.(string); ok {
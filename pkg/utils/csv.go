@@ -0,0 +1,26 @@
+package utils
+
+import "strings"
+
+// Leading characters that make spreadsheets read a cell as a formula
+const csvFormulaChars = "=+-@\t\r"
+
+// CSVCell keeps user text from being read as a formula by spreadsheets, a
+// cell starting with a formula character, after any quotes it already
+// starts with, gets a leading quote. UnescapeCSVCell gives back the text.
+func CSVCell(value string) string {
+	rest := strings.TrimLeft(value, "'")
+	if rest != "" && strings.ContainsRune(csvFormulaChars, rune(rest[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// UnescapeCSVCell removes the quote CSVCell adds, other cells are returned
+// as they are
+func UnescapeCSVCell(value string) string {
+	if rest, ok := strings.CutPrefix(value, "'"); ok && CSVCell(rest) == value {
+		return rest
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "text", want: "text"},
+		{value: "=1", want: "'=1"},
+		{value: "-", want: "'-"},
+		{value: "\t", want: "'\t"},
+		{value: "'a", want: "'a"},
+		{value: "''", want: "''"},
+		{value: "'=1", want: "''=1"},
+		{value: "''=1", want: "'''=1"},
+	}
+	for _, tt := range tests {
+		got := CSVCell(tt.value)
+		if got != tt.want {
+			t.Errorf("CSVCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if back := UnescapeCSVCell(got); back != tt.value {
+			t.Errorf("UnescapeCSVCell(%q) = %q, want %q", got, back, tt.value)
+		}
+	}
+}
@@ -12,9 +12,17 @@ type APIError struct {
 	Code    int            `json:"code"`
 	Message string         `json:"message"`
 	Status  APIErrorStatus `json:"status" default:"success"`
+	Fields  []FieldError   `json:"fields,omitempty"`
+	Current any            `json:"current,omitempty"`
 	Err     error          `json:"-"`
 }
 
+// FieldError tells which input field was rejected and why
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 func (e APIError) Error() string {
 	if e.Err == nil {
 		return e.Message
@@ -26,6 +34,12 @@ func NewError(code int, message string, err error) APIError {
 	return APIError{Code: code, Message: message, Status: Error, Err: err}
 }
 
+// NewConflict is a failed precondition, current is the state on the server
+// the client can merge its change with
+func NewConflict(message string, current any) APIError {
+	return APIError{Code: 409, Message: message, Status: Error, Current: current}
+}
+
 func NewWarning(code int, message string) APIError {
 	return APIError{Code: code, Message: message, Status: Warning}
 }
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mentionPattern   = regexp.MustCompile(`(?:^|[^\w@])@([\w][\w.-]*)`)
+	codeFencePattern = regexp.MustCompile("(?s)```.*?```")
+	codeSpanPattern  = regexp.MustCompile("`[^`\n]*`")
+)
+
+// ParseMentions returns the lower cased, unique @usernames in a markdown
+// body. Mentions inside code spans and fenced code blocks are ignored.
+func ParseMentions(body string) []string {
+	body = codeFencePattern.ReplaceAllString(body, "")
+	body = codeSpanPattern.ReplaceAllString(body, "")
+
+	seen := make(map[string]bool)
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// Trailing punctuation belongs to the sentence, not the name
+		name := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		mentions = append(mentions, name)
+	}
+	return mentions
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rank keys are base 36 fractions compared byte by byte, so they must be
+// stored with the "C" collation. A key never ends in the zero digit which
+// guarantees there is always room for another key below it.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a key that sorts strictly between a and b. An empty a
+// means the start of the list and an empty b the end of it.
+func RankBetween(a, b string) (string, error) {
+	if b != "" && a >= b {
+		return "", fmt.Errorf("rank %q is not before %q", a, b)
+	}
+	if strings.HasSuffix(a, "0") || strings.HasSuffix(b, "0") {
+		return "", fmt.Errorf("invalid rank with trailing zero")
+	}
+	return rankMidpoint(a, b), nil
+}
+
+func rankMidpoint(a, b string) string {
+	// Skip the common prefix
+	if b != "" {
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankMidpoint(rankTail(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(rankDigits[digitA]) + rankMidpoint(rankTail(a, 1), "")
+}
+
+func rankDigitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return rankDigits[0]
+}
+
+func rankTail(s string, i int) string {
+	if i < len(s) {
+		return s[i:]
+	}
+	return ""
+}
+
+// RankSequence returns n evenly spaced, ascending keys, used when a list
+// is created or rebalanced
+func RankSequence(n int) []string {
+	width, space := 1, len(rankDigits)
+	for space <= n {
+		width++
+		space *= len(rankDigits)
+	}
+
+	keys := make([]string, n)
+	step := space / (n + 1)
+	for i := range keys {
+		keys[i] = strings.TrimRight(rankEncode((i+1)*step, width), "0")
+	}
+	return keys
+}
+
+func rankEncode(v, width int) string {
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		out[i] = rankDigits[v%len(rankDigits)]
+		v /= len(rankDigits)
+	}
+	return string(out)
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty list", a: "", b: "", want: "i"},
+		{name: "first", a: "", b: "i", want: "9"},
+		{name: "last", a: "i", b: "", want: "r"},
+		{name: "wide gap", a: "a", b: "c", want: "b"},
+		{name: "adjacent digits", a: "a", b: "b", want: "ai"},
+		{name: "after a longer key", a: "a5", b: "b", want: "al"},
+		{name: "before a longer key", a: "a", b: "a5", want: "a3"},
+		{name: "shared prefix", a: "abc", b: "abe", want: "abd"},
+		{name: "below the lowest digit", a: "", b: "1", want: "0i"},
+		{name: "above the highest digit", a: "z", b: "", want: "zi"},
+		{name: "equal", a: "b", b: "b", wantErr: true},
+		{name: "reversed", a: "c", b: "b", wantErr: true},
+		{name: "trailing zero below", a: "a0", b: "b", wantErr: true},
+		{name: "trailing zero above", a: "", b: "b0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RankBetween(tt.a, tt.b)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("RankBetween(%q, %q) = %q, want error", tt.a, tt.b, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RankBetween(%q, %q) failed: %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("RankBetween(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+			assertBetween(t, tt.a, got, tt.b)
+		})
+	}
+}
+
+func TestRankBetweenRepeated(t *testing.T) {
+	tests := []struct {
+		name string
+		next func(lower, upper, rank string) (string, string)
+	}{
+		{
+			// Always insert at the start of the list
+			name: "prepend",
+			next: func(lower, upper, rank string) (string, string) { return "", rank },
+		},
+		{
+			// Always insert at the end of the list
+			name: "append",
+			next: func(lower, upper, rank string) (string, string) { return rank, "" },
+		},
+		{
+			// Keep inserting directly after the same row
+			name: "after the same row",
+			next: func(lower, upper, rank string) (string, string) { return lower, rank },
+		},
+		{
+			// Keep inserting directly before the same row
+			name: "before the same row",
+			next: func(lower, upper, rank string) (string, string) { return rank, upper },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper := "a", "b"
+			for i := 0; i < 500; i++ {
+				rank, err := RankBetween(lower, upper)
+				if err != nil {
+					t.Fatalf("insert %d between %q and %q failed: %v", i, lower, upper, err)
+				}
+				assertBetween(t, lower, rank, upper)
+				lower, upper = tt.next(lower, upper, rank)
+			}
+		})
+	}
+}
+
+func TestRankSequence(t *testing.T) {
+	tests := []struct {
+		n         int
+		wantWidth int
+	}{
+		{n: 0, wantWidth: 0},
+		{n: 1, wantWidth: 1},
+		{n: 35, wantWidth: 1},
+		{n: 36, wantWidth: 2},
+		{n: 1000, wantWidth: 2},
+		{n: 1296, wantWidth: 3},
+	}
+	for _, tt := range tests {
+		keys := RankSequence(tt.n)
+		if len(keys) != tt.n {
+			t.Fatalf("RankSequence(%d) returned %d keys", tt.n, len(keys))
+		}
+		for i, key := range keys {
+			if key == "" || strings.HasSuffix(key, "0") {
+				t.Errorf("RankSequence(%d)[%d] = %q, want a key without trailing zero", tt.n, i, key)
+			}
+			if len(key) > tt.wantWidth {
+				t.Errorf("RankSequence(%d)[%d] = %q, want at most %d digits", tt.n, i, key, tt.wantWidth)
+			}
+			if i > 0 && keys[i-1] >= key {
+				t.Errorf("RankSequence(%d) not ascending at %d: %q >= %q", tt.n, i, keys[i-1], key)
+			}
+		}
+	}
+}
+
+// Keys of a sequence leave room before the first, between every pair and
+// after the last key
+func TestRankSequenceRoom(t *testing.T) {
+	keys := RankSequence(100)
+	bounds := append(append([]string{""}, keys...), "")
+	for i := 0; i+1 < len(bounds); i++ {
+		rank, err := RankBetween(bounds[i], bounds[i+1])
+		if err != nil {
+			t.Fatalf("no room between %q and %q: %v", bounds[i], bounds[i+1], err)
+		}
+		assertBetween(t, bounds[i], rank, bounds[i+1])
+	}
+}
+
+// assertBetween checks that rank is a valid key strictly between a and b,
+// where empty bounds are the ends of the list
+func assertBetween(t *testing.T, a, rank, b string) {
+	t.Helper()
+	if rank == "" || strings.HasSuffix(rank, "0") {
+		t.Fatalf("rank %q is empty or has a trailing zero", rank)
+	}
+	for _, c := range rank {
+		if !strings.ContainsRune(rankDigits, c) {
+			t.Fatalf("rank %q has digit %q outside base 36", rank, c)
+		}
+	}
+	if rank <= a || (b != "" && rank >= b) {
+		t.Fatalf("rank %q is not between %q and %q", rank, a, b)
+	}
+}
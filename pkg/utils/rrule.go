@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurrenceFreq string
+
+const (
+	FreqDaily   RecurrenceFreq = "DAILY"
+	FreqWeekly  RecurrenceFreq = "WEEKLY"
+	FreqMonthly RecurrenceFreq = "MONTHLY"
+)
+
+// Longest stretch searched for the next occurrence, enough for any rule the
+// parser accepts
+const maxRecurrenceSearchDays = 5 * 366
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Recurrence is the subset of RFC 5545 RRULE supported for recurring cards:
+// daily, weekly on given weekdays and monthly on a day of the month, every
+// interval periods
+type Recurrence struct {
+	Freq       RecurrenceFreq
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+}
+
+// ParseRRule reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", an
+// optional "RRULE:" prefix is allowed
+func ParseRRule(value string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return r, errors.New("rule is empty")
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, fmt.Errorf("%q is not a KEY=VALUE pair", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = RecurrenceFreq(strings.ToUpper(val))
+			if r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly {
+				return r, fmt.Errorf("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 12 {
+				return r, fmt.Errorf("INTERVAL must be between 1 and 12")
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(val), ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return r, fmt.Errorf("%q is not a weekday", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 31 {
+				return r, fmt.Errorf("BYMONTHDAY must be between 1 and 31")
+			}
+			r.ByMonthDay = n
+		default:
+			return r, fmt.Errorf("%s is not supported", key)
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return r, errors.New("FREQ is required")
+	case len(r.ByDay) > 0 && r.Freq != FreqWeekly:
+		return r, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	case r.ByMonthDay > 0 && r.Freq != FreqMonthly:
+		return r, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return r, nil
+}
+
+// String formats the rule the way ParseRRule reads it
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			for name, d := range rruleWeekdays {
+				if d == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after after, occurrences start
+// at start and keep its time of day. The zero time means there is none.
+// Weekly rules default to the weekday of start and monthly rules to its day,
+// months without that day are skipped.
+func (r Recurrence) Next(start, after time.Time) time.Time {
+	start = start.UTC()
+	after = after.UTC()
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	if after.After(start) {
+		day = time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	clock := start.Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC))
+
+	for i := 0; i < maxRecurrenceSearchDays; i++ {
+		candidate := day.Add(clock)
+		if !candidate.Before(start) && candidate.After(after) && r.matches(start, day) {
+			return candidate
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// matches tells whether the rule has an occurrence on day, both at midnight UTC
+func (r Recurrence) matches(start, day time.Time) bool {
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r.Freq {
+	case FreqDaily:
+		days := int(day.Sub(first).Hours() / 24)
+		return days%r.Interval == 0
+	case FreqWeekly:
+		weeks := int(mondayOf(day).Sub(mondayOf(first)).Hours() / (24 * 7))
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == first.Weekday()
+		}
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case FreqMonthly:
+		months := (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		monthDay := r.ByMonthDay
+		if monthDay == 0 {
+			monthDay = first.Day()
+		}
+		return day.Day() == monthDay
+	}
+	return false
+}
+
+func mondayOf(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Recurrence
+		wantErr bool
+	}{
+		{
+			name:  "daily",
+			value: "FREQ=DAILY",
+			want:  Recurrence{Freq: FreqDaily, Interval: 1},
+		},
+		{
+			name:  "weekly with prefix",
+			value: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			want:  Recurrence{Freq: FreqWeekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Thursday}},
+		},
+		{
+			name:  "monthly lower case",
+			value: "freq=monthly;interval=3;bymonthday=31",
+			want:  Recurrence{Freq: FreqMonthly, Interval: 3, ByMonthDay: 31},
+		},
+		{name: "empty", value: " ", wantErr: true},
+		{name: "no frequency", value: "INTERVAL=2", wantErr: true},
+		{name: "yearly", value: "FREQ=YEARLY", wantErr: true},
+		{name: "not a pair", value: "FREQ", wantErr: true},
+		{name: "interval zero", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "interval too large", value: "FREQ=DAILY;INTERVAL=13", wantErr: true},
+		{name: "unknown weekday", value: "FREQ=WEEKLY;BYDAY=MO,XX", wantErr: true},
+		{name: "month day too large", value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{name: "weekdays on a daily rule", value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "month day on a weekly rule", value: "FREQ=WEEKLY;BYMONTHDAY=3", wantErr: true},
+		{name: "unsupported key", value: "FREQ=DAILY;COUNT=3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRRule(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRRule(%q) = %+v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) failed: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRRule(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+
+			// The formatted rule reads back as the same rule
+			again, err := ParseRRule(got.String())
+			if err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseRRule(%q) = %+v, %v, want %+v", got.String(), again, err, got)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "first occurrence is the start",
+			rule:  "FREQ=DAILY",
+			start: date(2025, 1, 1, 9, 30),
+			after: date(2024, 12, 1, 0, 0),
+			want:  date(2025, 1, 1, 9, 30),
+		},
+		{
+			name:  "strictly after",
+			rule:  "FREQ=DAILY",
+			start: date(2025, 1, 1, 9, 30),
+			after: date(2025, 1, 1, 9, 30),
+			want:  date(2025, 1, 2, 9, 30),
+		},
+		{
+			name:  "later the same day",
+			rule:  "FREQ=DAILY",
+			start: date(2025, 1, 1, 9, 30),
+			after: date(2025, 1, 5, 8, 0),
+			want:  date(2025, 1, 5, 9, 30),
+		},
+		{
+			name:  "daily interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2025, 1, 1, 9, 0),
+			after: date(2025, 1, 2, 0, 0),
+			want:  date(2025, 1, 4, 9, 0),
+		},
+		{
+			name:  "weekly on the weekday of start",
+			rule:  "FREQ=WEEKLY",
+			start: date(2025, 1, 10, 12, 0),
+			after: date(2025, 1, 10, 12, 0),
+			want:  date(2025, 1, 17, 12, 0),
+		},
+		{
+			name:  "weekly on the next listed weekday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: date(2025, 1, 6, 10, 0),
+			after: date(2025, 1, 6, 10, 0),
+			want:  date(2025, 1, 9, 10, 0),
+		},
+		{
+			name:  "weekly interval skips a week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: date(2025, 1, 6, 10, 0),
+			after: date(2025, 1, 9, 10, 0),
+			want:  date(2025, 1, 20, 10, 0),
+		},
+		{
+			name:  "weekly interval across the new year",
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			start: date(2024, 12, 23, 8, 0),
+			after: date(2024, 12, 23, 8, 0),
+			want:  date(2025, 1, 6, 8, 0),
+		},
+		{
+			// Weeks count from the week of start, not from the first occurrence
+			name:  "weekly interval from a start mid week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			start: date(2025, 1, 8, 8, 0),
+			after: date(2025, 1, 1, 0, 0),
+			want:  date(2025, 1, 20, 8, 0),
+		},
+		{
+			name:  "monthly on the day of start",
+			rule:  "FREQ=MONTHLY",
+			start: date(2025, 1, 15, 9, 0),
+			after: date(2025, 1, 15, 9, 0),
+			want:  date(2025, 2, 15, 9, 0),
+		},
+		{
+			name:  "monthly interval",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			start: date(2025, 1, 15, 9, 0),
+			after: date(2025, 1, 16, 0, 0),
+			want:  date(2025, 4, 15, 9, 0),
+		},
+		{
+			name:  "monthly interval across the new year",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			start: date(2024, 11, 15, 9, 0),
+			after: date(2024, 11, 15, 9, 0),
+			want:  date(2025, 2, 15, 9, 0),
+		},
+		{
+			name:  "day 31 skips February",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 1, 31, 9, 0),
+			after: date(2025, 1, 31, 9, 0),
+			want:  date(2025, 3, 31, 9, 0),
+		},
+		{
+			name:  "day 31 skips April",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 1, 31, 9, 0),
+			after: date(2025, 3, 31, 9, 0),
+			want:  date(2025, 5, 31, 9, 0),
+		},
+		{
+			name:  "day 31 in consecutive long months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 1, 31, 9, 0),
+			after: date(2025, 7, 31, 9, 0),
+			want:  date(2025, 8, 31, 9, 0),
+		},
+		{
+			name:  "day 31 after a start early in a short month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 4, 10, 9, 0),
+			after: date(2025, 4, 10, 9, 0),
+			want:  date(2025, 5, 31, 9, 0),
+		},
+		{
+			// February, April and June lack a 31st, August is the first
+			// month of the interval that has one
+			name:  "day 31 with an interval",
+			rule:  "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=31",
+			start: date(2025, 2, 1, 9, 0),
+			after: date(2025, 2, 1, 9, 0),
+			want:  date(2025, 8, 31, 9, 0),
+		},
+		{
+			name:  "day of start 31 skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2025, 8, 31, 9, 0),
+			after: date(2025, 8, 31, 9, 0),
+			want:  date(2025, 10, 31, 9, 0),
+		},
+		{
+			name:  "day 29 in a leap year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: date(2024, 1, 29, 9, 0),
+			after: date(2024, 1, 29, 9, 0),
+			want:  date(2024, 2, 29, 9, 0),
+		},
+		{
+			name:  "day 29 outside a leap year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: date(2025, 1, 29, 9, 0),
+			after: date(2025, 1, 29, 9, 0),
+			want:  date(2025, 3, 29, 9, 0),
+		},
+		{
+			name:  "start in another zone",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)),
+			after: date(2025, 1, 1, 0, 0),
+			want:  date(2025, 1, 2, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) failed: %v", tt.rule, err)
+			}
+			got := rule.Next(tt.start, tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("%s from %s, Next(%s) = %s, want %s", tt.rule, tt.start, tt.after, got, tt.want)
+			}
+		})
+	}
+}
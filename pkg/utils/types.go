@@ -16,16 +16,27 @@ const (
 	MessageError     MessageType = "message.error"
 
 	// Kanban messages
-	JoinKanban    MessageType = "kanban.load"
-	EditKanban    MessageType = "kanban.edit"
-	KanbanError   MessageType = "kanban.error"
-	KanbanArchive MessageType = "kanban.archive"
+	JoinKanban         MessageType = "kanban.load"
+	EditKanban         MessageType = "kanban.edit"
+	KanbanError        MessageType = "kanban.error"
+	KanbanWarning      MessageType = "kanban.warning"
+	KanbanArchive      MessageType = "kanban.archive"
+	KanbanArchivePurge MessageType = "kanban.archive.purge"
+	KanbanDelete       MessageType = "kanban.delete"
 	// Categories
 	NewKanbanCategory         MessageType = "kanban.category.new"
 	RestoreKanbanCategory     MessageType = "kanban.category.restore"
 	EditKanbanCategory        MessageType = "kanban.category.edit"
 	DeleteKanbanCategory      MessageType = "kanban.category.delete"
 	PermaDeleteKanbanCategory MessageType = "kanban.category.perma"
+	MoveKanbanCategory        MessageType = "kanban.category.move"
+	RebalanceKanbanCategories MessageType = "kanban.category.rebalance"
+	// Lanes
+	NewKanbanLane        MessageType = "kanban.lane.new"
+	EditKanbanLane       MessageType = "kanban.lane.edit"
+	MoveKanbanLane       MessageType = "kanban.lane.move"
+	DeleteKanbanLane     MessageType = "kanban.lane.delete"
+	RebalanceKanbanLanes MessageType = "kanban.lane.rebalance"
 	// Items
 	NewKanbanItem         MessageType = "kanban.item.new"
 	RestoreKanbanItem     MessageType = "kanban.item.restore"
@@ -33,4 +44,25 @@ const (
 	EditKanbanItem        MessageType = "kanban.item.edit"
 	DeleteKanbanItem      MessageType = "kanban.item.delete"
 	PermaDeleteKanbanItem MessageType = "kanban.item.perma"
+	RebalanceKanbanItems  MessageType = "kanban.item.rebalance"
+	BulkKanbanItems       MessageType = "kanban.item.bulk"
+	KanbanItemAssignees   MessageType = "kanban.item.assignees"
+	KanbanItemWatchers    MessageType = "kanban.item.watchers"
+	KanbanItemLabels      MessageType = "kanban.item.labels"
+	KanbanItemChecklist   MessageType = "kanban.item.checklist"
+	KanbanItemBlocked     MessageType = "kanban.item.blocked"
+	// Item links
+	NewKanbanItemLink    MessageType = "kanban.item.link.new"
+	DeleteKanbanItemLink MessageType = "kanban.item.link.delete"
+	// Item comments
+	NewKanbanItemComment    MessageType = "kanban.item.comment.new"
+	EditKanbanItemComment   MessageType = "kanban.item.comment.edit"
+	DeleteKanbanItemComment MessageType = "kanban.item.comment.delete"
+	// Item attachments
+	NewKanbanItemAttachment    MessageType = "kanban.item.attachment.new"
+	DeleteKanbanItemAttachment MessageType = "kanban.item.attachment.delete"
+	// Labels
+	NewKanbanLabel    MessageType = "kanban.label.new"
+	EditKanbanLabel   MessageType = "kanban.label.edit"
+	DeleteKanbanLabel MessageType = "kanban.label.delete"
 )
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidation makes validation errors name fields by their JSON name,
+// so they match what the client sent
+func RegisterValidation() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// NewValidationError turns a failed bind into a bad request listing every
+// rejected field
+func NewValidationError(err error) APIError {
+	apiErr := NewError(http.StatusBadRequest, "invalid input", err)
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			apiErr.Fields = append(apiErr.Fields, FieldError{
+				Field:   fe.Field(),
+				Message: validationMessage(fe),
+			})
+		}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		field := typeErr.Field
+		if i := strings.LastIndex(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		apiErr.Fields = []FieldError{{
+			Field:   field,
+			Message: typeMessage(typeErr.Type),
+		}}
+	}
+	return apiErr
+}
+
+// NewFieldError is a bad request for input that passed binding but was
+// refused by a rule of the service
+func NewFieldError(field, message string) APIError {
+	apiErr := NewError(http.StatusBadRequest, "invalid input", nil)
+	apiErr.Fields = []FieldError{{Field: field, Message: message}}
+	return apiErr
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be %s characters long", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "hexcolor":
+		return "must be a hex color"
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return "is invalid"
+	}
+}
+
+func typeMessage(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "must be a string"
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.Slice, reflect.Array:
+		return "must be a list"
+	default:
+		return "is invalid"
+	}
+}